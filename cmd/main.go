@@ -3,10 +3,12 @@ package main
 import (
 	"fmt"
 	"log"
+	"todo-api/internal/domain"
 	error_codes "todo-api/internal/infrastructure/api"
 	"todo-api/internal/infrastructure/api/routes"
 	"todo-api/internal/infrastructure/database/connection"
 	"todo-api/internal/infrastructure/database/repositories"
+	"todo-api/internal/infrastructure/notifications"
 	"todo-api/internal/middleware"
 	"todo-api/utils"
 
@@ -30,6 +32,16 @@ func main() {
 	DB_NAME := utils.GetEnvironmentVariable("DB_NAME")
 	SERVER_PORT := utils.GetEnvironmentVariable("SERVER_PORT")
 
+	jwtConfig, err := utils.LoadJWTConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	requestTimeout, err := utils.LoadRequestTimeout()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	err = utils.CheckDatabaseConnection(DB_USERNAME, DB_PASSWORD, DB_HOST, DB_PORT, DB_NAME)
 	if err != nil {
 		log.Fatal(err)
@@ -47,6 +59,8 @@ func main() {
 	router.Use(middleware.SecurityHeaders())
 	router.Use(middleware.CORSHeaders())
 	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.Timeout(requestTimeout))
 	router.Use(middleware.InputValidation())
 	router.Use(middleware.ResponseValidation())
 
@@ -84,10 +98,29 @@ func main() {
 
 	apiV1 := router.Group("/api/v1")
 
-	routes.SetTaskRoutes(apiV1, repositories.NewTaskRepository(db))
-	routes.SetTaskTypeRoutes(apiV1, repositories.NewTaskTypeRepository(db))
-	routes.SetTaskStatusRoutes(apiV1, repositories.NewTaskStatusRepository(db))
-	routes.SetWorkflowRoutes(apiV1, repositories.NewWorkflowRepository(db))
+	// Login and token refresh must stay reachable without an access token
+	userRepository := repositories.NewUserRepository(db)
+	routes.SetAuthRoutes(apiV1, userRepository, repositories.NewRefreshTokenRepository(db), jwtConfig)
+
+	// Every other API route requires a valid bearer token and only sees the data of the caller's teams
+	teamRepository := repositories.NewTeamRepository(db)
+	protected := apiV1.Group("")
+	protected.Use(middleware.Authentication(jwtConfig.Secret, userRepository))
+	protected.Use(middleware.TeamScope(teamRepository))
+
+	taskRepository := repositories.NewTaskRepository(db)
+	workflowRepository := repositories.NewWorkflowRepository(db)
+	taskStatusRepository := repositories.NewTaskStatusRepository(db)
+	taskTypeRepository := repositories.NewTaskTypeRepository(db)
+
+	taskService := domain.NewTaskService(taskRepository, workflowRepository, taskStatusRepository, taskTypeRepository,
+		userRepository, repositories.NewTaskDependencyRepository(db), repositories.NewActionFailureRepository(db), notifications.NewLogNotifier())
+	routes.SetTaskRoutes(protected, taskService)
+	routes.SetTaskTypeRoutes(protected, taskTypeRepository)
+	routes.SetTaskStatusRoutes(protected, taskStatusRepository)
+	routes.SetWorkflowRoutes(protected, domain.NewWorkflowService(workflowRepository))
+	routes.SetUserRoutes(protected, userRepository)
+	routes.SetTeamRoutes(protected, teamRepository)
 
 	// Swagger
 	router.Group("")
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// RefreshToken is the server-side record of an issued refresh JWT, keyed by its jti claim.
+// A token can be exchanged once: refreshing revokes it and points ReplacedBy at its successor.
+type RefreshToken struct {
+	ID         string
+	UserID     int64
+	ExpiresAt  DateTime
+	Revoked    bool
+	ReplacedBy string
+	CreatedAt  DateTime
+}
+
+func NewRefreshToken(id string, userID int64, expiresAt time.Time) RefreshToken {
+	return RefreshToken{
+		ID:        id,
+		UserID:    userID,
+		ExpiresAt: NewDateTime(expiresAt),
+		CreatedAt: Now(),
+	}
+}
+
+func (self *RefreshToken) IsExpired() bool {
+	return time.Now().After(self.ExpiresAt.Time)
+}
+
+func (self *RefreshToken) IsUsable() bool {
+	return !self.Revoked && !self.IsExpired()
+}
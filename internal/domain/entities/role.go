@@ -0,0 +1,20 @@
+package entities
+
+type Role string
+
+const (
+	// RoleAdmin can manage users and the shared configuration: statuses, task types and workflows
+	RoleAdmin Role = "admin"
+	// RoleMember can create and manage tasks
+	RoleMember Role = "member"
+	// RoleViewer has read-only access
+	RoleViewer Role = "viewer"
+)
+
+func (self Role) IsValid() bool {
+	switch self {
+	case RoleAdmin, RoleMember, RoleViewer:
+		return true
+	}
+	return false
+}
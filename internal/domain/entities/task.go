@@ -9,13 +9,18 @@ type Task struct {
 	Status        TaskStatus
 	Parent        *Task
 	AuthorID      int64
+	Author        *User `json:",omitempty"`
 	Deadline      DateTime
 	CreatedAt     DateTime
 	UpdatedAt     DateTime
 	ResponsibleID int64
+	Responsible   *User `json:",omitempty"`
 	Workflow      Workflow
 	Type          TaskType
+	TeamID        int64
 	Completed     bool
+	// Version grows with every stored change of the task
+	Version int64
 }
 
 func NewTask(title string, description string, authorID int64, deadline time.Time, taskType TaskType) *Task {
@@ -40,27 +45,31 @@ func (self *Task) AssignTo(userID int64) {
 
 func (self *Task) ChangeStatus(newStatus TaskStatus) {
 	self.Status = newStatus
-	if self.isTheLastStatus() {
-		self.Completed = true
-	}
+	self.Completed = self.Workflow.IsTerminal(self.Status.ID)
 	self.UpdatedAt = Now()
 }
 
-func (self *Task) IsCompleted() bool {
-	return self.Completed
-}
-
-func (self *Task) isTheLastStatus() bool {
-
-	var lastStatus uint8 = 0
-	for key := range self.Workflow.Statuses {
-		if key > lastStatus {
-			lastStatus = key
-		}
+// TransitionTo moves the task to a status of its workflow, which must be loaded,
+// when the workflow allows it, the task meets the guards of the move and, for a move
+// completing the task, none of its blockers is left open
+func (self *Task) TransitionTo(statusID int64, context TransitionContext) error {
+	if err := self.Workflow.CanTransition(self.Status.ID, statusID); err != nil {
+		return err
+	}
+	if err := self.Workflow.CheckGuards(self, self.Status.ID, statusID, context); err != nil {
+		return err
+	}
+	if open := OpenBlockers(context.Blockers); len(open) > 0 && self.Workflow.IsTerminal(statusID) {
+		return &BlockedTaskError{BlockerIDs: open}
 	}
 
-	return self.Status.ID == self.Workflow.Statuses[lastStatus].ID
+	position, _ := self.Workflow.PositionOf(statusID)
+	self.ChangeStatus(self.Workflow.Statuses[position])
+	return nil
+}
 
+func (self *Task) IsCompleted() bool {
+	return self.Completed
 }
 
 func (self *Task) SetWorkflow(newWorkflow Workflow) {
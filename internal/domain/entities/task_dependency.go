@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TaskDependency records that a task cannot be completed before another one is
+type TaskDependency struct {
+	BlockerID int64
+	BlockedID int64
+	CreatedAt DateTime
+}
+
+// DependencyCycleError reports the tasks a new dependency would make block each other, in blocking order
+// from the blocked task of the dependency back to it
+type DependencyCycleError struct {
+	TaskIDs []int64
+}
+
+func (self *DependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency would create a cycle: %s", joinIDs(self.TaskIDs, " -> "))
+}
+
+// BlockedTaskError reports the open tasks keeping a task from being completed
+type BlockedTaskError struct {
+	BlockerIDs []int64
+}
+
+func (self *BlockedTaskError) Error() string {
+	return fmt.Sprintf("task is blocked by open tasks %s", joinIDs(self.BlockerIDs, ", "))
+}
+
+// CheckCycle returns a *DependencyCycleError when the dependency, added to the existing ones,
+// would let a task block itself
+func (self TaskDependency) CheckCycle(existing []TaskDependency) error {
+	if self.BlockerID == self.BlockedID {
+		return &DependencyCycleError{TaskIDs: []int64{self.BlockedID, self.BlockerID}}
+	}
+
+	blocks := make(map[int64][]int64)
+	for _, dependency := range existing {
+		blocks[dependency.BlockerID] = append(blocks[dependency.BlockerID], dependency.BlockedID)
+	}
+
+	// A cycle exists when the blocker can already be reached from the task it is about to block
+	previous := map[int64]int64{self.BlockedID: 0}
+	queue := []int64{self.BlockedID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == self.BlockerID {
+			path := []int64{current}
+			for current != self.BlockedID {
+				current = previous[current]
+				path = append([]int64{current}, path...)
+			}
+			return &DependencyCycleError{TaskIDs: append(path, self.BlockedID)}
+		}
+		for _, next := range blocks[current] {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// OpenBlockers returns the IDs of the tasks that are not completed yet
+func OpenBlockers(blockers []Task) []int64 {
+	var open []int64
+	for _, blocker := range blockers {
+		if !blocker.Completed {
+			open = append(open, blocker.ID)
+		}
+	}
+	return open
+}
+
+func joinIDs(ids []int64, separator string) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(values, separator)
+}
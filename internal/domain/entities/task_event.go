@@ -0,0 +1,80 @@
+package entities
+
+import "strconv"
+
+// TaskEvent records the change of one field of a task.
+// Values are stored as text, a nil value means the field was not set.
+type TaskEvent struct {
+	ID     int64
+	TaskID int64
+	// ActorID is the user who made the change, 0 when it was not made on behalf of a user
+	ActorID   int64
+	Field     string
+	OldValue  *string
+	NewValue  *string
+	CreatedAt DateTime
+}
+
+// Changes lists an event for every field that differs between the task and its updated version
+func (self Task) Changes(updated Task, actorID int64) []TaskEvent {
+	now := Now()
+	events := []TaskEvent{}
+	add := func(field string, oldValue, newValue *string) {
+		if equalValues(oldValue, newValue) {
+			return
+		}
+		events = append(events, TaskEvent{
+			TaskID:    self.ID,
+			ActorID:   actorID,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			CreatedAt: now,
+		})
+	}
+
+	add("title", textValue(self.Title), textValue(updated.Title))
+	add("description", textValue(self.Description), textValue(updated.Description))
+	add("status", idValue(self.Status.ID), idValue(updated.Status.ID))
+	add("parent", idValue(parentID(self.Parent)), idValue(parentID(updated.Parent)))
+	add("deadline", dateValue(self.Deadline), dateValue(updated.Deadline))
+	add("responsible", idValue(self.ResponsibleID), idValue(updated.ResponsibleID))
+	add("workflow", idValue(self.Workflow.ID), idValue(updated.Workflow.ID))
+	add("type", idValue(self.Type.ID), idValue(updated.Type.ID))
+	add("team", idValue(self.TeamID), idValue(updated.TeamID))
+	add("completed", textValue(strconv.FormatBool(self.Completed)), textValue(strconv.FormatBool(updated.Completed)))
+
+	return events
+}
+
+func parentID(parent *Task) int64 {
+	if parent == nil {
+		return 0
+	}
+	return parent.ID
+}
+
+func textValue(value string) *string {
+	return &value
+}
+
+func idValue(id int64) *string {
+	if id == 0 {
+		return nil
+	}
+	return textValue(strconv.FormatInt(id, 10))
+}
+
+func dateValue(date DateTime) *string {
+	if date.Time.IsZero() {
+		return nil
+	}
+	return textValue(date.Time.UTC().Format(RFC3339Format))
+}
+
+func equalValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -4,6 +4,8 @@ type TaskStatus struct {
 	ID     int64
 	Label  string
 	Active bool
+	// Version grows with every stored change of the status
+	Version int64
 }
 
 func NewTaskStatus(label string, active bool) TaskStatus {
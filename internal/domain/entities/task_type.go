@@ -1,8 +1,12 @@
 package entities
 
+// TaskType is shared by every team when TeamID is 0
 type TaskType struct {
-	ID   int64
-	Name string
+	ID     int64
+	Name   string
+	TeamID int64
+	// Version grows with every stored change of the type
+	Version int64
 }
 
 func NewTaskType(name string) TaskType {
@@ -10,3 +14,7 @@ func NewTaskType(name string) TaskType {
 		Name: name,
 	}
 }
+
+func (self *TaskType) IsShared() bool {
+	return self.TeamID == 0
+}
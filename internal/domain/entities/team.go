@@ -0,0 +1,20 @@
+package entities
+
+// Team is the tenancy boundary of the application: tasks and workflows belong to exactly one team
+// and are only visible to its members, task types belong to a team or are shared by all of them
+type Team struct {
+	ID        int64
+	Name      string
+	CreatedAt DateTime
+}
+
+func NewTeam(name string) Team {
+	return Team{
+		Name:      name,
+		CreatedAt: Now(),
+	}
+}
+
+func (self *Team) Rename(newName string) {
+	self.Name = newName
+}
@@ -0,0 +1,109 @@
+package entities
+
+import "fmt"
+
+type ActionKind string
+
+const (
+	// ActionAssign assigns the task to UserID
+	ActionAssign ActionKind = "assign"
+	// ActionSetDeadline moves the deadline of the task DeadlineOffsetHours after the transition
+	ActionSetDeadline ActionKind = "set_deadline"
+	// ActionNotifyWatchers notifies WatcherIDs that the task changed status
+	ActionNotifyWatchers ActionKind = "notify_watchers"
+	// ActionAdvanceParent moves the parent of the task to ParentStatusID once all its subtasks are completed
+	ActionAdvanceParent ActionKind = "advance_parent"
+)
+
+// TransitionAction is a side effect run after a task entered a status of its workflow
+type TransitionAction struct {
+	// FromStatusID restricts the action to moves from one status, 0 runs it whatever the previous status
+	FromStatusID        int64
+	ToStatusID          int64
+	Kind                ActionKind
+	UserID              int64   `json:",omitempty"`
+	DeadlineOffsetHours int     `json:",omitempty"`
+	WatcherIDs          []int64 `json:",omitempty"`
+	ParentStatusID      int64   `json:",omitempty"`
+}
+
+// ActionFailure records an action that could not be run after a transition
+type ActionFailure struct {
+	ID           int64
+	TaskID       int64
+	FromStatusID int64
+	ToStatusID   int64
+	Action       ActionKind
+	Error        string
+	CreatedAt    DateTime
+}
+
+func NewActionFailure(taskID int64, action TransitionAction, fromStatusID int64, err error) ActionFailure {
+	return ActionFailure{
+		TaskID:       taskID,
+		FromStatusID: fromStatusID,
+		ToStatusID:   action.ToStatusID,
+		Action:       action.Kind,
+		Error:        err.Error(),
+		CreatedAt:    Now(),
+	}
+}
+
+// Applies reports whether the action runs after a move between two statuses
+func (self TransitionAction) Applies(fromID, toID int64) bool {
+	return self.ToStatusID == toID && (self.FromStatusID == 0 || self.FromStatusID == fromID)
+}
+
+// Validate checks that the action is complete for its kind
+func (self TransitionAction) Validate() error {
+	switch self.Kind {
+	case ActionAssign:
+		if self.UserID <= 0 {
+			return fmt.Errorf("assign actions need a UserID")
+		}
+	case ActionSetDeadline:
+		if self.DeadlineOffsetHours <= 0 {
+			return fmt.Errorf("set_deadline actions need a positive DeadlineOffsetHours")
+		}
+	case ActionNotifyWatchers:
+		if len(self.WatcherIDs) == 0 {
+			return fmt.Errorf("notify_watchers actions need WatcherIDs")
+		}
+	case ActionAdvanceParent:
+		if self.ParentStatusID <= 0 {
+			return fmt.Errorf("advance_parent actions need a ParentStatusID")
+		}
+	default:
+		return fmt.Errorf("action kind must be %s, %s, %s or %s",
+			ActionAssign, ActionSetDeadline, ActionNotifyWatchers, ActionAdvanceParent)
+	}
+	return nil
+}
+
+// ValidateActions checks that every action is complete and only runs on statuses of the workflow.
+// The status of advance_parent actions belongs to the parent's workflow and is checked when the action runs.
+func (self *Workflow) ValidateActions() error {
+	for _, action := range self.Actions {
+		if err := action.Validate(); err != nil {
+			return err
+		}
+		if _, ok := self.PositionOf(action.ToStatusID); !ok {
+			return fmt.Errorf("action status %d is not part of the workflow", action.ToStatusID)
+		}
+		if _, ok := self.PositionOf(action.FromStatusID); action.FromStatusID != 0 && !ok {
+			return fmt.Errorf("action status %d is not part of the workflow", action.FromStatusID)
+		}
+	}
+	return nil
+}
+
+// ActionsFor returns the actions to run after a move between two statuses, in declaration order
+func (self *Workflow) ActionsFor(fromID, toID int64) []TransitionAction {
+	var actions []TransitionAction
+	for _, action := range self.Actions {
+		if action.Applies(fromID, toID) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
@@ -0,0 +1,132 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+type GuardKind string
+
+const (
+	// GuardRequiredField requires a field of the task to be set, see RequiredFields
+	GuardRequiredField GuardKind = "required_field"
+	// GuardSubtasksCompleted requires every subtask of the task to be completed
+	GuardSubtasksCompleted GuardKind = "subtasks_completed"
+)
+
+// RequiredFields are the task fields a GuardRequiredField guard can require
+var RequiredFields = map[string]func(task *Task) bool{
+	"responsible": func(task *Task) bool { return task.ResponsibleID != 0 },
+	"description": func(task *Task) bool { return strings.TrimSpace(task.Description) != "" },
+	"deadline":    func(task *Task) bool { return !task.Deadline.IsZero() },
+	"type":        func(task *Task) bool { return task.Type.ID != 0 },
+}
+
+// TransitionGuard is a condition a task must meet to enter a status of its workflow
+type TransitionGuard struct {
+	// FromStatusID restricts the guard to moves from one status, 0 applies it whatever the current status
+	FromStatusID int64
+	ToStatusID   int64
+	Kind         GuardKind
+	// Field is the task field required by GuardRequiredField guards
+	Field string
+}
+
+// TransitionContext carries what guards need to know beyond the task itself
+type TransitionContext struct {
+	Subtasks []Task
+	// Blockers are the tasks the task depends on, whatever their team, which must all be completed before it is
+	Blockers []Task
+}
+
+// GuardError lists the guards a task failed when moving to another status
+type GuardError struct {
+	Failures []GuardFailure
+}
+
+type GuardFailure struct {
+	Guard   TransitionGuard
+	Message string
+}
+
+func (self *GuardError) Error() string {
+	messages := make([]string, len(self.Failures))
+	for i, failure := range self.Failures {
+		messages[i] = failure.Message
+	}
+	return "transition blocked: " + strings.Join(messages, "; ")
+}
+
+// Applies reports whether the guard must be checked for a move between two statuses
+func (self TransitionGuard) Applies(fromID, toID int64) bool {
+	return self.ToStatusID == toID && (self.FromStatusID == 0 || self.FromStatusID == fromID)
+}
+
+// Validate checks that the guard is one the domain knows how to evaluate
+func (self TransitionGuard) Validate() error {
+	switch self.Kind {
+	case GuardRequiredField:
+		if _, ok := RequiredFields[self.Field]; !ok {
+			return fmt.Errorf("guard field must be responsible, description, deadline or type")
+		}
+	case GuardSubtasksCompleted:
+	default:
+		return fmt.Errorf("guard kind must be %s or %s", GuardRequiredField, GuardSubtasksCompleted)
+	}
+	return nil
+}
+
+// Check returns why the task does not meet the guard, or an empty string when it does
+func (self TransitionGuard) Check(task *Task, context TransitionContext) string {
+	switch self.Kind {
+	case GuardRequiredField:
+		if isSet, ok := RequiredFields[self.Field]; ok && !isSet(task) {
+			return fmt.Sprintf("%s is required", self.Field)
+		}
+	case GuardSubtasksCompleted:
+		open := 0
+		for _, subtask := range context.Subtasks {
+			if !subtask.Completed {
+				open++
+			}
+		}
+		if open > 0 {
+			return fmt.Sprintf("%d subtasks are not completed", open)
+		}
+	}
+	return ""
+}
+
+// ValidateGuards checks that every guard is known and only references statuses of the workflow
+func (self *Workflow) ValidateGuards() error {
+	for _, guard := range self.Guards {
+		if err := guard.Validate(); err != nil {
+			return err
+		}
+		if _, ok := self.PositionOf(guard.ToStatusID); !ok {
+			return fmt.Errorf("guard status %d is not part of the workflow", guard.ToStatusID)
+		}
+		if _, ok := self.PositionOf(guard.FromStatusID); guard.FromStatusID != 0 && !ok {
+			return fmt.Errorf("guard status %d is not part of the workflow", guard.FromStatusID)
+		}
+	}
+	return nil
+}
+
+// CheckGuards evaluates the guards of a move between two statuses, reporting every failure at once
+func (self *Workflow) CheckGuards(task *Task, fromID, toID int64, context TransitionContext) error {
+	var failures []GuardFailure
+	for _, guard := range self.Guards {
+		if !guard.Applies(fromID, toID) {
+			continue
+		}
+		if message := guard.Check(task, context); message != "" {
+			failures = append(failures, GuardFailure{Guard: guard, Message: message})
+		}
+	}
+
+	if len(failures) > 0 {
+		return &GuardError{Failures: failures}
+	}
+	return nil
+}
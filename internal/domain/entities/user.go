@@ -1,16 +1,67 @@
 package entities
 
+import (
+	"fmt"
+	"todo-api/utils"
+)
+
 type User struct {
-	ID       int64
-	Name     string
-	Username string
-	Email    string
+	ID           int64
+	Name         string
+	Username     string
+	Email        string
+	PasswordHash string `json:"-"`
+	Role         Role
+	Active       bool
+	CreatedAt    DateTime
+}
+
+func NewUser(name, username, email, password string) (User, error) {
+	user := User{
+		Name:      name,
+		Username:  username,
+		Email:     email,
+		Role:      RoleMember,
+		Active:    true,
+		CreatedAt: Now(),
+	}
+	if password != "" {
+		if err := user.SetPassword(password); err != nil {
+			return User{}, err
+		}
+	}
+	return user, nil
+}
+
+func (self *User) SetPassword(password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash the password: %w", err)
+	}
+	self.PasswordHash = hash
+	return nil
 }
 
-func NewUser(name, username, email, password string) User {
-	return User{
-		Name:     name,
-		Username: username,
-		Email:    email,
+func (self *User) CheckPassword(password string) bool {
+	if self.PasswordHash == "" {
+		return false
 	}
+	return utils.CheckPasswordHash(password, self.PasswordHash)
+}
+
+func (self *User) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		if self.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *User) Deactivate() {
+	self.Active = false
+}
+
+func (self *User) Activate() {
+	self.Active = true
 }
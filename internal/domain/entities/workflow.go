@@ -1,15 +1,35 @@
 package entities
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+)
+
+var (
+	// ErrStatusNotInWorkflow is returned when a task is moved to a status its workflow does not contain
+	ErrStatusNotInWorkflow = errors.New("status is not part of the task's workflow")
+	// ErrTransitionNotAllowed is returned when the workflow forbids moving between two of its statuses
+	ErrTransitionNotAllowed = errors.New("transition is not allowed by the workflow")
 )
 
 type Workflow struct {
-	ID        int64
-	Name      string
-	Statuses  map[uint8]TaskStatus
+	ID       int64
+	Name     string
+	Statuses map[uint8]TaskStatus
+	// Sequential workflows only let tasks move to the previous or next status of the order
+	Sequential bool
+	// Graph, when set, replaces the order of Statuses: tasks only follow its transitions
+	Graph *WorkflowGraph
+	// Guards are the conditions tasks must meet to enter some statuses
+	Guards []TransitionGuard
+	// Actions are the side effects run after tasks entered some statuses
+	Actions   []TransitionAction
 	Author    User
+	TeamID    int64
 	CreatedAt DateTime
+	// Version grows with every stored change of the workflow
+	Version int64
 }
 
 func NewWorkflow(name string, statuses map[uint8]TaskStatus, author User) Workflow {
@@ -50,3 +70,93 @@ func (self *Workflow) ValidateStatus(status TaskStatus) error {
 	}
 	return nil
 }
+
+// OrderedKeys returns the positions of the workflow's statuses from first to last
+func (self *Workflow) OrderedKeys() []uint8 {
+	keys := make([]uint8, 0, len(self.Statuses))
+	for key := range self.Statuses {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// PositionOf returns the position of a status in the workflow
+func (self *Workflow) PositionOf(statusID int64) (uint8, bool) {
+	for key, status := range self.Statuses {
+		if status.ID == statusID {
+			return key, true
+		}
+	}
+	return 0, false
+}
+
+// InitialStatus returns the status new tasks of the workflow start in:
+// the graph's initial status, or else the first status of the order
+func (self *Workflow) InitialStatus() (TaskStatus, bool) {
+	if self.Graph != nil {
+		position, ok := self.PositionOf(self.Graph.InitialStatusID)
+		return self.Statuses[position], ok
+	}
+
+	keys := self.OrderedKeys()
+	if len(keys) == 0 {
+		return TaskStatus{}, false
+	}
+	return self.Statuses[keys[0]], true
+}
+
+// IsTerminal reports whether a task in the status is done: a terminal status of the graph,
+// or else the last status of the order
+func (self *Workflow) IsTerminal(statusID int64) bool {
+	if self.Graph != nil {
+		return self.Graph.IsTerminal(statusID)
+	}
+
+	keys := self.OrderedKeys()
+	return len(keys) > 0 && self.Statuses[keys[len(keys)-1]].ID == statusID
+}
+
+// CanTransition checks that a task in the status fromID may move to the status toID
+func (self *Workflow) CanTransition(fromID, toID int64) error {
+	to, ok := self.PositionOf(toID)
+	if !ok {
+		return ErrStatusNotInWorkflow
+	}
+
+	from, ok := self.PositionOf(fromID)
+	if ok && from == to {
+		return nil
+	}
+
+	// A task whose status left the workflow can only restart from the initial status
+	if self.Graph != nil {
+		if (ok && self.Graph.Allows(fromID, toID)) || (!ok && toID == self.Graph.InitialStatusID) {
+			return nil
+		}
+		return ErrTransitionNotAllowed
+	}
+
+	if !self.Sequential || !ok {
+		return nil
+	}
+
+	// Positions may have gaps, so neighbours are found in the ordered keys
+	keys := self.OrderedKeys()
+	distance := slices.Index(keys, to) - slices.Index(keys, from)
+	if distance != 1 && distance != -1 {
+		return ErrTransitionNotAllowed
+	}
+	return nil
+}
+
+// Validate checks the transition graph, the guards and the actions of the workflow
+func (self *Workflow) Validate() error {
+	if err := self.ValidateGraph(); err != nil {
+		return err
+	}
+	if err := self.ValidateGuards(); err != nil {
+		return err
+	}
+	return self.ValidateActions()
+}
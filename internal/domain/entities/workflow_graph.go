@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Transition lets tasks move from one status of a workflow to another, both given by ID
+type Transition struct {
+	From int64
+	To   int64
+}
+
+// WorkflowGraph describes the moves allowed between the statuses of a workflow when
+// their order alone is not enough, e.g. a task may be blocked and resumed, or cancelled from any step
+type WorkflowGraph struct {
+	InitialStatusID   int64
+	TerminalStatusIDs []int64
+	Transitions       []Transition
+}
+
+// Allows reports whether the graph has an edge from one status to the other
+func (self *WorkflowGraph) Allows(fromID, toID int64) bool {
+	return slices.Contains(self.Transitions, Transition{From: fromID, To: toID})
+}
+
+// IsTerminal reports whether tasks are done once they reach the status
+func (self *WorkflowGraph) IsTerminal(statusID int64) bool {
+	return slices.Contains(self.TerminalStatusIDs, statusID)
+}
+
+// ValidateGraph checks that the graph only references statuses of the workflow, that every status
+// can be reached from the initial one and that a terminal status can be reached from every status,
+// so that no task can get stuck before a terminal status
+func (self *Workflow) ValidateGraph() error {
+	graph := self.Graph
+	if graph == nil {
+		return nil
+	}
+
+	if _, ok := self.PositionOf(graph.InitialStatusID); !ok {
+		return fmt.Errorf("initial status %d is not part of the workflow", graph.InitialStatusID)
+	}
+	if len(graph.TerminalStatusIDs) == 0 {
+		return fmt.Errorf("workflow needs at least one terminal status")
+	}
+	for _, statusID := range graph.TerminalStatusIDs {
+		if _, ok := self.PositionOf(statusID); !ok {
+			return fmt.Errorf("terminal status %d is not part of the workflow", statusID)
+		}
+	}
+
+	next := make(map[int64][]int64)
+	previous := make(map[int64][]int64)
+	for _, transition := range graph.Transitions {
+		for _, statusID := range []int64{transition.From, transition.To} {
+			if _, ok := self.PositionOf(statusID); !ok {
+				return fmt.Errorf("transition %d -> %d uses status %d which is not part of the workflow",
+					transition.From, transition.To, statusID)
+			}
+		}
+		next[transition.From] = append(next[transition.From], transition.To)
+		previous[transition.To] = append(previous[transition.To], transition.From)
+	}
+
+	reached := reachable([]int64{graph.InitialStatusID}, next)
+	// Walking the transitions backwards from the terminal statuses finds the statuses that can still end
+	ending := reachable(graph.TerminalStatusIDs, previous)
+
+	for _, key := range self.OrderedKeys() {
+		status := self.Statuses[key]
+		if !reached[status.ID] {
+			return fmt.Errorf("status %s cannot be reached from the initial status", status.Label)
+		}
+		if len(next[status.ID]) == 0 && !graph.IsTerminal(status.ID) {
+			return fmt.Errorf("status %s has no outgoing transition and is not terminal", status.Label)
+		}
+		if !ending[status.ID] {
+			return fmt.Errorf("status %s cannot lead to a terminal status", status.Label)
+		}
+	}
+
+	return nil
+}
+
+// reachable returns the statuses reached by following the edges from the start statuses, those included
+func reachable(start []int64, edges map[int64][]int64) map[int64]bool {
+	reached := make(map[int64]bool, len(start))
+	pending := make([]int64, 0, len(start))
+	for _, statusID := range start {
+		if !reached[statusID] {
+			reached[statusID] = true
+			pending = append(pending, statusID)
+		}
+	}
+
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, statusID := range edges[current] {
+			if !reached[statusID] {
+				reached[statusID] = true
+				pending = append(pending, statusID)
+			}
+		}
+	}
+	return reached
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMigration is returned when a migration cannot move the tasks where it asks to
+var ErrInvalidMigration = errors.New("invalid workflow migration")
+
+// WorkflowMigration moves the tasks of a workflow to statuses of a target workflow.
+// Tasks in a status missing from StatusMapping keep it, so it must be part of the target too.
+type WorkflowMigration struct {
+	SourceWorkflowID int64
+	// TargetWorkflowID is the workflow receiving the tasks, 0 keeps them in the source workflow
+	TargetWorkflowID int64
+	StatusMapping    map[int64]int64
+	// ActorID is the user recorded in the history of the migrated tasks
+	ActorID int64
+}
+
+// OrphanedTasksError reports the statuses of tasks a change of workflow would leave unresolved
+type OrphanedTasksError struct {
+	StatusIDs []int64
+}
+
+func (self *OrphanedTasksError) Error() string {
+	ids := make([]string, len(self.StatusIDs))
+	for i, id := range self.StatusIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	return fmt.Sprintf("tasks in statuses %s would be left outside their workflow, a status mapping is required",
+		strings.Join(ids, ", "))
+}
+
+// Target returns the ID of the workflow receiving the tasks
+func (self WorkflowMigration) Target() int64 {
+	if self.TargetWorkflowID == 0 {
+		return self.SourceWorkflowID
+	}
+	return self.TargetWorkflowID
+}
+
+// Plan resolves the status of the target workflow each status in use is moved to.
+// Statuses in use that resolve to no status of the target are reported by an OrphanedTasksError.
+func (self WorkflowMigration) Plan(target *Workflow, usedStatusIDs []int64) (map[int64]int64, error) {
+	for from, to := range self.StatusMapping {
+		if _, ok := target.PositionOf(to); !ok {
+			return nil, fmt.Errorf("%w: status %d is mapped to %d, which is not part of workflow %d",
+				ErrInvalidMigration, from, to, target.ID)
+		}
+	}
+
+	plan := make(map[int64]int64, len(usedStatusIDs))
+	var orphaned []int64
+	for _, from := range usedStatusIDs {
+		to, ok := self.StatusMapping[from]
+		if !ok {
+			to = from
+		}
+		if _, ok := target.PositionOf(to); !ok {
+			orphaned = append(orphaned, from)
+			continue
+		}
+		plan[from] = to
+	}
+
+	if len(orphaned) > 0 {
+		slices.Sort(orphaned)
+		return nil, &OrphanedTasksError{StatusIDs: orphaned}
+	}
+	return plan, nil
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Every error of the domain matches one of these kinds with errors.Is, which is all the API needs to answer it
+var (
+	// ErrNotFound is matched by a *NotFoundError
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is matched by a *ConflictError
+	ErrConflict = errors.New("conflict")
+	// ErrValidation is matched by a *ValidationError
+	ErrValidation = errors.New("validation failed")
+	// ErrForbidden is matched by a *ForbiddenError
+	ErrForbidden = errors.New("forbidden")
+)
+
+// NotFoundError reports an entity that does not exist or that the caller's teams cannot see
+type NotFoundError struct {
+	Entity  string
+	ID      int64
+	Message string
+}
+
+// NewNotFoundError reports the entity id missing, a zero id when the lookup was not by ID
+func NewNotFoundError(entity string, id int64) *NotFoundError {
+	if id == 0 {
+		return &NotFoundError{Entity: entity, Message: entity + " not found"}
+	}
+	return &NotFoundError{Entity: entity, ID: id, Message: fmt.Sprintf("%s %d not found", entity, id)}
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ConflictError reports a change clashing with the stored state, such as a duplicate or a removal
+// other entities still depend on
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ValidationError reports a change the domain rules do not allow. Field names the field at fault, when there is one.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func NewValidationError(field string, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ForbiddenError reports an operation the caller is not allowed to make
+type ForbiddenError struct {
+	Message string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+var (
+	ErrUsernameTaken     error = &ConflictError{Message: "username already exists"}
+	ErrEmailTaken        error = &ConflictError{Message: "email already exists"}
+	ErrTeamNameTaken     error = &ConflictError{Message: "team name already exists"}
+	ErrTaskTypeNameTaken error = &ConflictError{Message: "task type name already exists in the team"}
+	ErrAlreadyTeamMember error = &ConflictError{Message: "user is already a member of the team"}
+	ErrDependencyExists  error = &ConflictError{Message: "task is already blocked by this task"}
+)
+
+// InvalidReferenceError reports a field referring to an entity that does not exist or cannot be used there.
+// Field is empty when the database rejected a reference without naming it.
+type InvalidReferenceError struct {
+	Field   string
+	ID      int64
+	Message string
+}
+
+func (e *InvalidReferenceError) Error() string {
+	return e.Message
+}
+
+// NewMissingReferenceError reports a field referring to no entity, or left unset when id is 0
+func NewMissingReferenceError(field string, id int64) *InvalidReferenceError {
+	switch {
+	case field == "":
+		return &InvalidReferenceError{Message: "a referenced entity does not exist"}
+	case id == 0:
+		return &InvalidReferenceError{Field: field, Message: fmt.Sprintf("%s is required", field)}
+	default:
+		return &InvalidReferenceError{Field: field, ID: id, Message: fmt.Sprintf("%s %d does not exist", field, id)}
+	}
+}
+
+var (
+	// ErrInvalidWorkflow is matched by the errors of services given a workflow that fails its validation,
+	// which are validation errors
+	ErrInvalidWorkflow error = &ValidationError{Message: "invalid workflow"}
+
+	ErrTaskAuthorChange     error = &ValidationError{Field: "author_id", Message: "task author cannot be changed"}
+	ErrTaskStatusChange     error = &ValidationError{Field: "status_id", Message: "task status can only be changed by a transition"}
+	ErrWorkflowAuthorChange error = &ValidationError{Field: "author_id", Message: "workflow author cannot be changed"}
+	// ErrHasSubtasks is returned when deleting a task whose subtasks the SubtasksReject policy keeps
+	ErrHasSubtasks error = &ConflictError{Message: "task still has subtasks"}
+	// ErrVersionConflict is returned when updating or removing an entity from a version that is not the stored one
+	ErrVersionConflict = errors.New("version does not match the stored one")
+	// ErrTokenReused is returned when rotating a refresh token that was already revoked
+	ErrTokenReused = errors.New("refresh token was already used")
+)
+
+// kindError makes an error match a sentinel, and the kind of the sentinel, with errors.Is while keeping its own message
+type kindError struct {
+	err  error
+	kind error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind || errors.Is(e.kind, target)
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
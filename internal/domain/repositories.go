@@ -1,47 +1,131 @@
 package domain
 
-import "todo-api/internal/domain/entities"
+import (
+	"context"
+	"todo-api/internal/domain/entities"
+)
 
+// Every method takes the context of the request it serves, queries are cancelled along with it.
+// Lookups, updates and removals of an entity that does not exist, or that the given teams cannot see, fail with
+// a *NotFoundError; duplicates fail with a *ConflictError.
+// Update and Remove of statuses, task types, workflows and tasks fail with ErrVersionConflict when they are
+// given another version than the stored one; a zero version matches any. Every update increments the version.
 type TaskStatusRepository interface {
-	Create(status entities.TaskStatus) (entities.TaskStatus, error)
-	GetByID(id int64) (entities.TaskStatus, error)
-	Update(status entities.TaskStatus) (entities.TaskStatus, error)
-	Remove(id int64) error
-	GetAll() ([]entities.TaskStatus, error)
+	Create(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error)
+	GetByID(ctx context.Context, id int64) (entities.TaskStatus, error)
+	Update(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error)
+	Remove(ctx context.Context, id int64, version int64) error
+	GetAll(ctx context.Context) ([]entities.TaskStatus, error)
 }
 
+// Read methods taking teamIDs only return task types shared by every team or owned by one of the teams
 type TaskTypeRepository interface {
-	Create(taskType entities.TaskType) (entities.TaskType, error)
-	GetByID(id int64) (entities.TaskType, error)
-	Update(taskType entities.TaskType) (entities.TaskType, error)
-	Remove(id int64) error
-	GetAll() ([]entities.TaskType, error)
+	Create(ctx context.Context, taskType entities.TaskType) (entities.TaskType, error)
+	GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.TaskType, error)
+	Update(ctx context.Context, taskType entities.TaskType) (entities.TaskType, error)
+	Remove(ctx context.Context, id int64, version int64) error
+	GetAll(ctx context.Context, teamIDs []int64) ([]entities.TaskType, error)
 }
 
+// Methods taking teamIDs only see workflows owned by one of the teams.
+// Update and Remove fail with an *entities.OrphanedTasksError when they would leave tasks in a status
+// outside their workflow, unless the migration given moves those tasks; it is applied in the same transaction.
 type WorkflowRepository interface {
-	Create(workflow entities.Workflow) (entities.Workflow, error)
-	GetByID(id int64) (entities.Workflow, error)
-	Update(workflow entities.Workflow) (entities.Workflow, error)
-	Remove(id int64) error
-	GetAll() ([]entities.Workflow, error)
+	Create(ctx context.Context, workflow entities.Workflow) (entities.Workflow, error)
+	GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.Workflow, error)
+	Update(ctx context.Context, workflow entities.Workflow, migration *entities.WorkflowMigration, teamIDs []int64) (entities.Workflow, error)
+	Remove(ctx context.Context, id int64, version int64, migration *entities.WorkflowMigration, teamIDs []int64) error
+	// Migrate moves the tasks of a workflow in one transaction and returns how many of them changed
+	Migrate(ctx context.Context, migration entities.WorkflowMigration, teamIDs []int64) (int64, error)
+	GetAll(ctx context.Context, teamIDs []int64) ([]entities.Workflow, error)
 }
 
+// Methods taking teamIDs only see tasks owned by one of the teams
 type TaskRepository interface {
-	Create(task entities.Task) (entities.Task, error)
-	GetByID(id int64) (entities.Task, error)
-	Update(task entities.Task) (entities.Task, error)
-	GetAll() ([]entities.Task, error)
-	GetAllByResponsible(userID int64) ([]entities.Task, error)
-	GetAllByAuthor(userID int64) ([]entities.Task, error)
-	GetAllByStatus(status entities.TaskStatus) ([]entities.Task, error)
-	GetAllOverdue() ([]entities.Task, error)
-	Remove(id int64) error
+	Create(ctx context.Context, task entities.Task) (entities.Task, error)
+	GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.Task, error)
+	GetExpanded(ctx context.Context, id int64, expansion TaskExpansion, teamIDs []int64) (entities.Task, error)
+	// Update records the fields it changes as events of the actor, in the same transaction.
+	// A parent that is one of the task's subtasks fails with an *InvalidReferenceError on the parent.
+	Update(ctx context.Context, task entities.Task, actorID int64, teamIDs []int64) (entities.Task, error)
+	GetHistory(ctx context.Context, id int64, teamIDs []int64) ([]entities.TaskEvent, error)
+	GetAll(ctx context.Context, teamIDs []int64) ([]entities.Task, error)
+	Find(ctx context.Context, query TaskQuery, teamIDs []int64) (TaskPage, error)
+	Search(ctx context.Context, query string, limit int, teamIDs []int64) ([]TaskSearchResult, error)
+	GetAllByResponsible(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error)
+	GetAllByAuthor(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error)
+	GetAllByStatus(ctx context.Context, status entities.TaskStatus, teamIDs []int64) ([]entities.Task, error)
+	GetAllByWorkflow(ctx context.Context, workflowID int64, teamIDs []int64) ([]entities.Task, error)
+	GetAllByParent(ctx context.Context, parentID int64, teamIDs []int64) ([]entities.Task, error)
+	// GetDescendants returns the subtasks of a task at any depth, in no particular order
+	GetDescendants(ctx context.Context, id int64, teamIDs []int64) ([]entities.Task, error)
+	GetAllOverdue(ctx context.Context, teamIDs []int64) ([]entities.Task, error)
+	// Remove deletes a task and handles its subtasks according to policy, in one transaction.
+	// Subtasks promoted to another parent are recorded as changes of the actor.
+	Remove(ctx context.Context, id int64, version int64, policy SubtaskPolicy, actorID int64, teamIDs []int64) error
+}
+
+// TaskDependencyRepository stores which tasks block which.
+// Methods taking teamIDs only see tasks, and dependencies between tasks, owned by one of the teams.
+// The checks keeping tasks from blocking themselves or being completed early see every team.
+type TaskDependencyRepository interface {
+	// Add checks the dependency against every stored one and stores it, in one transaction.
+	// It fails with ErrDependencyExists when the blocker already blocks the task, and with an
+	// *entities.DependencyCycleError when the dependency would let a task block itself.
+	Add(ctx context.Context, dependency entities.TaskDependency) (entities.TaskDependency, error)
+	Remove(ctx context.Context, blockerID int64, blockedID int64) error
+	// GetBlockers returns the tasks a task depends on
+	GetBlockers(ctx context.Context, taskID int64, teamIDs []int64) ([]entities.Task, error)
+	// GetBlockerStates returns every task a task depends on, whatever its team, with only its ID
+	// and whether it is completed set
+	GetBlockerStates(ctx context.Context, taskID int64) ([]entities.Task, error)
+	// GetBlocked returns the tasks depending on a task
+	GetBlocked(ctx context.Context, taskID int64, teamIDs []int64) ([]entities.Task, error)
+	GetAll(ctx context.Context, teamIDs []int64) ([]entities.TaskDependency, error)
+}
+
+// ActionFailureRepository records the workflow actions that failed after a transition
+type ActionFailureRepository interface {
+	Record(ctx context.Context, failure entities.ActionFailure) (entities.ActionFailure, error)
+	GetAllByTask(ctx context.Context, taskID int64) ([]entities.ActionFailure, error)
+}
+
+type UserRepository interface {
+	Create(ctx context.Context, user entities.User) (entities.User, error)
+	GetByID(ctx context.Context, id int64) (entities.User, error)
+	GetByUsername(ctx context.Context, username string) (entities.User, error)
+	Update(ctx context.Context, user entities.User) (entities.User, error)
+	Deactivate(ctx context.Context, id int64) error
+	GetAll(ctx context.Context) ([]entities.User, error)
+}
+
+type TeamRepository interface {
+	Create(ctx context.Context, team entities.Team) (entities.Team, error)
+	GetByID(ctx context.Context, id int64) (entities.Team, error)
+	Update(ctx context.Context, team entities.Team) (entities.Team, error)
+	Remove(ctx context.Context, id int64) error
+	GetAll(ctx context.Context) ([]entities.Team, error)
+	GetAllByMember(ctx context.Context, userID int64) ([]entities.Team, error)
+	GetTeamIDsByMember(ctx context.Context, userID int64) ([]int64, error)
+	AddMember(ctx context.Context, teamID int64, userID int64) error
+	RemoveMember(ctx context.Context, teamID int64, userID int64) error
+	GetMembers(ctx context.Context, teamID int64) ([]entities.User, error)
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token entities.RefreshToken) (entities.RefreshToken, error)
+	GetByID(ctx context.Context, id string) (entities.RefreshToken, error)
+	Revoke(ctx context.Context, id string, replacedBy string) error
+	// Rotate revokes the token id and stores successor in its place, in one transaction.
+	// It returns ErrTokenReused when id was already revoked, by an earlier or concurrent rotation.
+	Rotate(ctx context.Context, id string, successor entities.RefreshToken) error
+	RevokeAllForUser(ctx context.Context, userID int64) error
 }
 
 type StatusRepository interface {
-	Create(status entities.TaskStatus) (entities.TaskStatus, error)
-	GetByID(id int64) (entities.TaskStatus, error)
-	Update(status entities.TaskStatus) (entities.TaskStatus, error)
-	Remove(id int64) error
-	GetAll() ([]entities.TaskStatus, error)
+	Create(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error)
+	GetByID(ctx context.Context, id int64) (entities.TaskStatus, error)
+	Update(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error)
+	Remove(ctx context.Context, id int64, version int64) error
+	GetAll(ctx context.Context) ([]entities.TaskStatus, error)
 }
@@ -0,0 +1,47 @@
+package domain
+
+import "todo-api/internal/domain/entities"
+
+// BoardColumn holds the tasks in one status of a workflow
+type BoardColumn struct {
+	Position uint8
+	Status   entities.TaskStatus
+	Tasks    []entities.Task
+}
+
+// TaskBoard groups the tasks of a workflow in one column per status, in workflow order.
+// Tasks whose status is not part of the workflow are listed in Unmapped so they are never hidden.
+type TaskBoard struct {
+	Workflow entities.Workflow
+	Columns  []BoardColumn
+	Unmapped []entities.Task
+	Total    int
+}
+
+func NewTaskBoard(workflow entities.Workflow, tasks []entities.Task) TaskBoard {
+	tasksByStatus := make(map[int64][]entities.Task)
+	for _, task := range tasks {
+		tasksByStatus[task.Status.ID] = append(tasksByStatus[task.Status.ID], task)
+	}
+
+	columns := make([]BoardColumn, 0, len(workflow.Statuses))
+	for _, position := range workflow.OrderedKeys() {
+		status := workflow.Statuses[position]
+		columnTasks := tasksByStatus[status.ID]
+		if columnTasks == nil {
+			columnTasks = []entities.Task{}
+		}
+		delete(tasksByStatus, status.ID)
+
+		columns = append(columns, BoardColumn{Position: position, Status: status, Tasks: columnTasks})
+	}
+
+	unmapped := []entities.Task{}
+	for _, task := range tasks {
+		if _, ok := tasksByStatus[task.Status.ID]; ok {
+			unmapped = append(unmapped, task)
+		}
+	}
+
+	return TaskBoard{Workflow: workflow, Columns: columns, Unmapped: unmapped, Total: len(tasks)}
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "strings"
+
+// TaskExpansion lists the related entities to load in full along with tasks,
+// instead of leaving only their IDs set
+type TaskExpansion struct {
+	Status      bool
+	Type        bool
+	Workflow    bool
+	Parent      bool
+	Author      bool
+	Responsible bool
+}
+
+// ParseTaskExpansion reads a comma separated list of relations, such as "status,author"
+func ParseTaskExpansion(value string) (TaskExpansion, error) {
+	var expansion TaskExpansion
+	if value == "" {
+		return expansion, nil
+	}
+
+	relations := map[string]*bool{
+		"status":      &expansion.Status,
+		"type":        &expansion.Type,
+		"workflow":    &expansion.Workflow,
+		"parent":      &expansion.Parent,
+		"author":      &expansion.Author,
+		"responsible": &expansion.Responsible,
+	}
+	for _, name := range strings.Split(value, ",") {
+		target, ok := relations[strings.TrimSpace(name)]
+		if !ok {
+			return TaskExpansion{}, NewValidationError("expand", "expand must list status, type, workflow, parent, author or responsible")
+		}
+		*target = true
+	}
+
+	return expansion, nil
+}
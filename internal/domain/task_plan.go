@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"todo-api/internal/domain/entities"
+)
+
+// PlannedTask is an open task with its place in the plan
+type PlannedTask struct {
+	Task entities.Task
+	// Stage is 0 for a task no open task blocks, and one more than the latest stage of its blockers otherwise
+	Stage int
+	// BlockedBy lists the open tasks blocking the task
+	BlockedBy []int64
+}
+
+// NewTaskPlan orders the open tasks topologically: every task comes after its open blockers.
+// Tasks of a stage are ordered by deadline, then ID. Completed tasks are left out, and so are
+// the dependencies on them. Tasks caught in a cycle, which only dependencies stored before
+// cycles were checked across teams can make, end up together in a last stage.
+func NewTaskPlan(tasks []entities.Task, dependencies []entities.TaskDependency) []PlannedTask {
+	open := make(map[int64]entities.Task)
+	for _, task := range tasks {
+		if !task.Completed {
+			open[task.ID] = task
+		}
+	}
+
+	blockedBy := make(map[int64][]int64)
+	blocks := make(map[int64][]int64)
+	for _, dependency := range dependencies {
+		_, blockerOpen := open[dependency.BlockerID]
+		_, blockedOpen := open[dependency.BlockedID]
+		if blockerOpen && blockedOpen {
+			blockedBy[dependency.BlockedID] = append(blockedBy[dependency.BlockedID], dependency.BlockerID)
+			blocks[dependency.BlockerID] = append(blocks[dependency.BlockerID], dependency.BlockedID)
+		}
+	}
+
+	remaining := make(map[int64]int, len(open))
+	var ready []int64
+	for id := range open {
+		remaining[id] = len(blockedBy[id])
+		if remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	stages := make(map[int64]int, len(open))
+	lastStage := -1
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		lastStage = max(lastStage, stages[id])
+		for _, next := range blocks[id] {
+			stages[next] = max(stages[next], stages[id]+1)
+			if remaining[next]--; remaining[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	plan := make([]PlannedTask, 0, len(open))
+	for id, task := range open {
+		stage := stages[id]
+		if remaining[id] > 0 {
+			stage = lastStage + 1
+		}
+		planned := PlannedTask{Task: task, Stage: stage, BlockedBy: slices.Sorted(slices.Values(blockedBy[id]))}
+		if planned.BlockedBy == nil {
+			planned.BlockedBy = []int64{}
+		}
+		plan = append(plan, planned)
+	}
+
+	slices.SortFunc(plan, func(a, b PlannedTask) int {
+		return cmp.Or(
+			cmp.Compare(a.Stage, b.Stage),
+			a.Task.Deadline.Compare(b.Task.Deadline.Time),
+			cmp.Compare(a.Task.ID, b.Task.ID),
+		)
+	})
+	return plan
+}
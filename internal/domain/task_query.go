@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+	"todo-api/internal/domain/entities"
+)
+
+const (
+	DefaultTaskPageSize = 20
+	MaxTaskPageSize     = 100
+)
+
+// TaskSortFields are the fields tasks can be sorted by, mapped to their column
+var TaskSortFields = map[string]string{
+	"deadline":   "deadline",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title",
+}
+
+// TaskQuery describes a page of tasks: zero IDs and nil pointers match every task
+type TaskQuery struct {
+	StatusID      int64
+	TypeID        int64
+	WorkflowID    int64
+	ResponsibleID int64
+	AuthorID      int64
+	Completed     *bool
+	DeadlineFrom  *time.Time
+	DeadlineTo    *time.Time
+	Sort          string
+	Descending    bool
+	Limit         int
+	Offset        int
+	Expand        TaskExpansion
+}
+
+// TaskPage is one page of tasks along with the number of tasks matching the query
+type TaskPage struct {
+	Tasks []entities.Task
+	Total int64
+}
+
+// HasNext reports whether tasks remain after this page
+func (self TaskQuery) HasNext(total int64) bool {
+	return int64(self.Offset+self.Limit) < total
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo-api/internal/domain/entities"
+)
+
+// TaskReferences checks the entities a task refers to before it is stored, so that a dangling
+// reference is reported with the field holding it rather than as a database error
+type TaskReferences struct {
+	tasks     TaskRepository
+	workflows WorkflowRepository
+	statuses  TaskStatusRepository
+	types     TaskTypeRepository
+	users     UserRepository
+}
+
+func NewTaskReferences(tasks TaskRepository, workflows WorkflowRepository, statuses TaskStatusRepository, types TaskTypeRepository, users UserRepository) *TaskReferences {
+	return &TaskReferences{
+		tasks:     tasks,
+		workflows: workflows,
+		statuses:  statuses,
+		types:     types,
+		users:     users,
+	}
+}
+
+// Check returns an *InvalidReferenceError for the first reference of the task that does not resolve.
+// Workflows, types and parent tasks must also be visible to the teams. Lookups failing for another reason
+// than a missing entity return their error as it is.
+func (self *TaskReferences) Check(ctx context.Context, task entities.Task, teamIDs []int64) error {
+	if task.Workflow.ID == 0 {
+		return NewMissingReferenceError("workflow", 0)
+	}
+	workflow, err := self.workflows.GetByID(ctx, task.Workflow.ID, teamIDs)
+	if err != nil {
+		return referenceError(err, "workflow", task.Workflow.ID)
+	}
+
+	if task.Status.ID == 0 {
+		return NewMissingReferenceError("status", 0)
+	}
+	if _, err := self.statuses.GetByID(ctx, task.Status.ID); err != nil {
+		return referenceError(err, "status", task.Status.ID)
+	}
+	if _, ok := workflow.PositionOf(task.Status.ID); !ok {
+		return &InvalidReferenceError{
+			Field:   "status",
+			ID:      task.Status.ID,
+			Message: fmt.Sprintf("status %d is not part of workflow %d", task.Status.ID, workflow.ID),
+		}
+	}
+
+	if task.Type.ID == 0 {
+		return NewMissingReferenceError("type", 0)
+	}
+	if _, err := self.types.GetByID(ctx, task.Type.ID, teamIDs); err != nil {
+		return referenceError(err, "type", task.Type.ID)
+	}
+
+	if _, err := self.users.GetByID(ctx, task.AuthorID); err != nil {
+		return referenceError(err, "author", task.AuthorID)
+	}
+	if task.ResponsibleID != 0 {
+		if _, err := self.users.GetByID(ctx, task.ResponsibleID); err != nil {
+			return referenceError(err, "responsible", task.ResponsibleID)
+		}
+	}
+
+	if task.Parent != nil {
+		if task.Parent.ID == task.ID {
+			return &InvalidReferenceError{Field: "parent", ID: task.Parent.ID, Message: "a task cannot be its own parent"}
+		}
+		if _, err := self.tasks.GetByID(ctx, task.Parent.ID, teamIDs); err != nil {
+			return referenceError(err, "parent", task.Parent.ID)
+		}
+	}
+
+	return nil
+}
+
+// referenceError reports the lookup error of a reference as a dangling reference when the entity was not found
+func referenceError(err error, field string, id int64) error {
+	if errors.Is(err, ErrNotFound) {
+		return NewMissingReferenceError(field, id)
+	}
+	return err
+}
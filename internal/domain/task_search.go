@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"html"
+	"strings"
+	"todo-api/internal/domain/entities"
+	"unicode"
+)
+
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+
+	// snippetRadius is the number of characters kept on each side of the first match
+	snippetRadius = 60
+)
+
+// TaskSearchResult is a task matching a full-text search, with its relevance and highlighted excerpts
+type TaskSearchResult struct {
+	Task         entities.Task
+	Score        float64
+	TitleSnippet string
+	Snippet      string
+}
+
+// SearchTerms splits a search query into lowercase words, dropping punctuation and operators
+// so the query can't be interpreted by the database's full-text syntax
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// Highlight wraps every occurrence of the terms in text with HighlightStart and HighlightEnd.
+// The text is HTML-escaped, so the result can be rendered as HTML whatever the task holds.
+func Highlight(text string, terms []string) string {
+	matches := matchRanges(text, terms)
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		builder.WriteString(html.EscapeString(text[last:match[0]]))
+		builder.WriteString(HighlightStart)
+		builder.WriteString(html.EscapeString(text[match[0]:match[1]]))
+		builder.WriteString(HighlightEnd)
+		last = match[1]
+	}
+	builder.WriteString(html.EscapeString(text[last:]))
+	return builder.String()
+}
+
+// Snippet returns the highlighted, HTML-escaped excerpt of text around the first occurrence of a term,
+// or the beginning of text when no term occurs in it
+func Snippet(text string, terms []string) string {
+	matches := matchRanges(text, terms)
+
+	start := 0
+	if len(matches) > 0 {
+		start = max(matches[0][0]-snippetRadius, 0)
+	}
+	end := min(start+2*snippetRadius, len(text))
+
+	// Never cut a word in half
+	for start > 0 && !unicode.IsSpace(rune(text[start-1])) {
+		start--
+	}
+	for end < len(text) && !unicode.IsSpace(rune(text[end])) {
+		end++
+	}
+
+	excerpt := Highlight(text[start:end], terms)
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(text) {
+		excerpt += "…"
+	}
+	return excerpt
+}
+
+// matchRanges returns the sorted, non overlapping byte ranges where a term starts a word of text
+func matchRanges(text string, terms []string) [][2]int {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Lowercasing changed byte offsets, fall back to exact matching
+		lower = text
+	}
+
+	var ranges [][2]int
+	for i := 0; i < len(lower); i++ {
+		if i > 0 && isWordByte(lower[i-1]) {
+			continue
+		}
+		for _, term := range terms {
+			if strings.HasPrefix(lower[i:], term) {
+				ranges = append(ranges, [2]int{i, i + len(term)})
+				i += len(term) - 1
+				break
+			}
+		}
+	}
+	return ranges
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
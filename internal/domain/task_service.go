@@ -0,0 +1,310 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"todo-api/internal/domain/entities"
+)
+
+// TaskService owns the business rules of tasks: it checks what a task refers to before storing it,
+// moves tasks through their workflow and runs the actions of the moves.
+// Methods taking teamIDs only see tasks owned by one of the teams; lookups of tasks or workflows
+// the teams cannot see fail with the *NotFoundError of the repository.
+type TaskService struct {
+	tasks        TaskRepository
+	workflows    WorkflowRepository
+	dependencies TaskDependencyRepository
+	failures     ActionFailureRepository
+	references   *TaskReferences
+	actions      *TransitionActionRunner
+}
+
+func NewTaskService(tasks TaskRepository, workflows WorkflowRepository, statuses TaskStatusRepository, types TaskTypeRepository, users UserRepository, dependencies TaskDependencyRepository, failures ActionFailureRepository, notifier Notifier) *TaskService {
+	return &TaskService{
+		tasks:        tasks,
+		workflows:    workflows,
+		dependencies: dependencies,
+		failures:     failures,
+		references:   NewTaskReferences(tasks, workflows, statuses, types, users),
+		actions:      NewTransitionActionRunner(tasks, workflows, dependencies, failures, notifier),
+	}
+}
+
+// Create stores a new task authored by actorID. Tasks without a status start in the initial status of their workflow.
+// Identity and timestamps are set here, whatever the task holds.
+func (self *TaskService) Create(ctx context.Context, task entities.Task, actorID int64, teamIDs []int64) (entities.Task, error) {
+	if task.Status.ID == 0 && task.Workflow.ID != 0 {
+		workflow, err := self.workflows.GetByID(ctx, task.Workflow.ID, teamIDs)
+		if err != nil {
+			return entities.Task{}, referenceError(err, "workflow", task.Workflow.ID)
+		}
+		if status, ok := workflow.InitialStatus(); ok {
+			task.Status = status
+		}
+	}
+
+	now := entities.Now()
+	task.ID = 0
+	task.AuthorID = actorID
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	if err := self.references.Check(ctx, task, teamIDs); err != nil {
+		return entities.Task{}, err
+	}
+	return self.tasks.Create(ctx, task)
+}
+
+func (self *TaskService) Get(ctx context.Context, id int64, expansion TaskExpansion, teamIDs []int64) (entities.Task, error) {
+	task, err := self.tasks.GetExpanded(ctx, id, expansion, teamIDs)
+	if err != nil {
+		return entities.Task{}, err
+	}
+	return task, nil
+}
+
+func (self *TaskService) Find(ctx context.Context, query TaskQuery, teamIDs []int64) (TaskPage, error) {
+	return self.tasks.Find(ctx, query, teamIDs)
+}
+
+func (self *TaskService) Search(ctx context.Context, query string, limit int, teamIDs []int64) ([]TaskSearchResult, error) {
+	return self.tasks.Search(ctx, query, limit, teamIDs)
+}
+
+// Update replaces the editable fields of a task with those of changes.
+// The author cannot change, and neither can the status, which only moves through Transition;
+// a zero author, status or team keeps the stored one. A version other than the stored one fails
+// with ErrVersionConflict, a zero version updates whatever is stored.
+func (self *TaskService) Update(ctx context.Context, id int64, changes entities.Task, actorID int64, teamIDs []int64) (entities.Task, error) {
+	task, err := self.tasks.GetByID(ctx, id, teamIDs)
+	if err != nil {
+		return entities.Task{}, err
+	}
+
+	if changes.AuthorID != 0 && changes.AuthorID != task.AuthorID {
+		return entities.Task{}, ErrTaskAuthorChange
+	}
+	if changes.Status.ID != 0 && changes.Status.ID != task.Status.ID {
+		return entities.Task{}, ErrTaskStatusChange
+	}
+
+	task.Title = changes.Title
+	task.Description = changes.Description
+	task.Parent = changes.Parent
+	task.Deadline = changes.Deadline
+	task.Type = changes.Type
+	if changes.TeamID != 0 {
+		task.TeamID = changes.TeamID
+	}
+	task.SetWorkflow(changes.Workflow)
+	task.AssignTo(changes.ResponsibleID)
+	if changes.Version != 0 {
+		task.Version = changes.Version
+	}
+
+	return self.store(ctx, task, actorID, teamIDs)
+}
+
+// Reparent moves a task under another task, or to the top level when parentID is 0.
+// A task cannot move under itself or one of its own subtasks. Versions are checked as by Update.
+func (self *TaskService) Reparent(ctx context.Context, id int64, parentID int64, version int64, actorID int64, teamIDs []int64) (entities.Task, error) {
+	task, err := self.tasks.GetByID(ctx, id, teamIDs)
+	if err != nil {
+		return entities.Task{}, err
+	}
+
+	task.Parent = nil
+	if parentID != 0 {
+		task.Parent = &entities.Task{ID: parentID}
+	}
+	task.UpdatedAt = entities.Now()
+	if version != 0 {
+		task.Version = version
+	}
+
+	return self.store(ctx, task, actorID, teamIDs)
+}
+
+// store checks the references of a stored task before updating it.
+// The repository rejects a parent that is one of the task's subtasks, in the transaction of the update.
+func (self *TaskService) store(ctx context.Context, task entities.Task, actorID int64, teamIDs []int64) (entities.Task, error) {
+	if err := self.references.Check(ctx, task, teamIDs); err != nil {
+		return entities.Task{}, err
+	}
+
+	return self.tasks.Update(ctx, task, actorID, teamIDs)
+}
+
+// Subtasks returns the tree of subtasks below a task, with the progress of every task of the tree
+func (self *TaskService) Subtasks(ctx context.Context, id int64, teamIDs []int64) (SubtaskTree, error) {
+	task, err := self.tasks.GetByID(ctx, id, teamIDs)
+	if err != nil {
+		return SubtaskTree{}, err
+	}
+
+	descendants, err := self.tasks.GetDescendants(ctx, id, teamIDs)
+	if err != nil {
+		return SubtaskTree{}, err
+	}
+	return NewSubtaskTree(task, descendants), nil
+}
+
+// Transition moves a task to another status of its workflow, then runs the workflow's actions for the move.
+// Completed is derived from the new status. Failed actions do not undo the transition, they are returned
+// with the updated task. A move the workflow rejects, or completing a task with open blockers,
+// fails with the error of entities.Task.TransitionTo.
+func (self *TaskService) Transition(ctx context.Context, id int64, statusID int64, actorID int64, teamIDs []int64) (entities.Task, []entities.ActionFailure, error) {
+	task, err := self.tasks.GetByID(ctx, id, teamIDs)
+	if err != nil {
+		return entities.Task{}, nil, err
+	}
+
+	workflow, err := self.workflows.GetByID(ctx, task.Workflow.ID, teamIDs)
+	if err != nil {
+		return entities.Task{}, nil, err
+	}
+	task.Workflow = workflow
+
+	subtasks, err := self.tasks.GetAllByParent(ctx, task.ID, teamIDs)
+	if err != nil {
+		return entities.Task{}, nil, err
+	}
+
+	// Blockers of other teams keep the task open too, the error only names them
+	blockers, err := self.dependencies.GetBlockerStates(ctx, task.ID)
+	if err != nil {
+		return entities.Task{}, nil, err
+	}
+
+	fromStatusID := task.Status.ID
+	if err := task.TransitionTo(statusID, entities.TransitionContext{Subtasks: subtasks, Blockers: blockers}); err != nil {
+		return entities.Task{}, nil, err
+	}
+
+	updated, err := self.tasks.Update(ctx, task, actorID, teamIDs)
+	if err != nil {
+		return entities.Task{}, nil, err
+	}
+
+	failures := self.actions.Run(ctx, &updated, fromStatusID, actorID, teamIDs)
+	return updated, failures, nil
+}
+
+// Dependencies returns the tasks blocking a task and the tasks it blocks
+func (self *TaskService) Dependencies(ctx context.Context, id int64, teamIDs []int64) (blockers []entities.Task, blocked []entities.Task, err error) {
+	if _, err := self.tasks.GetByID(ctx, id, teamIDs); err != nil {
+		return nil, nil, err
+	}
+
+	if blockers, err = self.dependencies.GetBlockers(ctx, id, teamIDs); err != nil {
+		return nil, nil, err
+	}
+	if blocked, err = self.dependencies.GetBlocked(ctx, id, teamIDs); err != nil {
+		return nil, nil, err
+	}
+	return blockers, blocked, nil
+}
+
+// AddDependency makes a task wait for blockerID to be completed before it can be completed.
+// A dependency letting a task block itself, even through tasks of other teams, fails with an *entities.DependencyCycleError.
+func (self *TaskService) AddDependency(ctx context.Context, id int64, blockerID int64, teamIDs []int64) (entities.TaskDependency, error) {
+	if _, err := self.tasks.GetByID(ctx, id, teamIDs); err != nil {
+		return entities.TaskDependency{}, err
+	}
+	if _, err := self.tasks.GetByID(ctx, blockerID, teamIDs); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return entities.TaskDependency{}, &InvalidReferenceError{Field: "blocked_by", ID: blockerID, Message: fmt.Sprintf("task %d does not exist", blockerID)}
+		}
+		return entities.TaskDependency{}, err
+	}
+
+	return self.dependencies.Add(ctx, entities.TaskDependency{BlockerID: blockerID, BlockedID: id, CreatedAt: entities.Now()})
+}
+
+// RemoveDependency lets a task be completed regardless of blockerID
+func (self *TaskService) RemoveDependency(ctx context.Context, id int64, blockerID int64, teamIDs []int64) error {
+	blockers, err := self.dependencies.GetBlockers(ctx, id, teamIDs)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(blockers, func(blocker entities.Task) bool { return blocker.ID == blockerID }) {
+		return &NotFoundError{Entity: "task dependency", Message: fmt.Sprintf("task %d is not blocked by task %d", id, blockerID)}
+	}
+	return self.dependencies.Remove(ctx, blockerID, id)
+}
+
+// Plan orders the open tasks so that every task comes after the tasks blocking it
+func (self *TaskService) Plan(ctx context.Context, teamIDs []int64) ([]PlannedTask, error) {
+	tasks, err := self.tasks.GetAll(ctx, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := self.dependencies.GetAll(ctx, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	return NewTaskPlan(tasks, dependencies), nil
+}
+
+// History lists every field change of a task, oldest first
+func (self *TaskService) History(ctx context.Context, id int64, teamIDs []int64) ([]entities.TaskEvent, error) {
+	if _, err := self.tasks.GetByID(ctx, id, teamIDs); err != nil {
+		return nil, err
+	}
+	return self.tasks.GetHistory(ctx, id, teamIDs)
+}
+
+// ActionFailures lists the workflow actions that failed after transitions of a task, latest first
+func (self *TaskService) ActionFailures(ctx context.Context, id int64, teamIDs []int64) ([]entities.ActionFailure, error) {
+	if _, err := self.tasks.GetByID(ctx, id, teamIDs); err != nil {
+		return nil, err
+	}
+	return self.failures.GetAllByTask(ctx, id)
+}
+
+// Remove deletes a task, its subtasks are deleted, promoted or keep it from being deleted as policy says.
+// A task kept by its subtasks fails with ErrHasSubtasks, one that is not at version fails with ErrVersionConflict
+// unless version is 0.
+func (self *TaskService) Remove(ctx context.Context, id int64, version int64, policy SubtaskPolicy, actorID int64, teamIDs []int64) error {
+	return self.tasks.Remove(ctx, id, version, policy, actorID, teamIDs)
+}
+
+func (self *TaskService) GetAllByResponsible(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error) {
+	return self.tasks.GetAllByResponsible(ctx, userID, teamIDs)
+}
+
+func (self *TaskService) GetAllByAuthor(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error) {
+	return self.tasks.GetAllByAuthor(ctx, userID, teamIDs)
+}
+
+func (self *TaskService) GetAllByStatus(ctx context.Context, statusID int64, teamIDs []int64) ([]entities.Task, error) {
+	return self.tasks.GetAllByStatus(ctx, entities.TaskStatus{ID: statusID}, teamIDs)
+}
+
+// Board groups the tasks of a workflow by status, in workflow order
+func (self *TaskService) Board(ctx context.Context, workflowID int64, teamIDs []int64) (TaskBoard, error) {
+	workflow, err := self.workflows.GetByID(ctx, workflowID, teamIDs)
+	if err != nil {
+		return TaskBoard{}, err
+	}
+
+	tasks, err := self.tasks.GetAllByWorkflow(ctx, workflowID, teamIDs)
+	if err != nil {
+		return TaskBoard{}, err
+	}
+	return NewTaskBoard(workflow, tasks), nil
+}
+
+// Overdue lists the open tasks past their deadline. The repository preselects them,
+// the final say belongs to entities.Task.IsOverdue so that the rule is the same everywhere.
+func (self *TaskService) Overdue(ctx context.Context, teamIDs []int64) ([]entities.Task, error) {
+	tasks, err := self.tasks.GetAllOverdue(ctx, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(tasks, func(task entities.Task) bool {
+		return !task.IsOverdue()
+	}), nil
+}
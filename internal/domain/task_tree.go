@@ -0,0 +1,78 @@
+package domain
+
+import "todo-api/internal/domain/entities"
+
+// SubtaskPolicy decides what happens to the subtasks of a deleted task
+type SubtaskPolicy string
+
+const (
+	// SubtasksCascade deletes the whole subtask tree along with the task
+	SubtasksCascade SubtaskPolicy = "cascade"
+	// SubtasksPromote moves the subtasks up to the parent of the deleted task, or to the top level
+	SubtasksPromote SubtaskPolicy = "promote"
+	// SubtasksReject refuses to delete a task that still has subtasks
+	SubtasksReject SubtaskPolicy = "reject"
+)
+
+// ParseSubtaskPolicy reads a policy, an empty value is SubtasksCascade
+func ParseSubtaskPolicy(value string) (SubtaskPolicy, error) {
+	switch policy := SubtaskPolicy(value); policy {
+	case "":
+		return SubtasksCascade, nil
+	case SubtasksCascade, SubtasksPromote, SubtasksReject:
+		return policy, nil
+	default:
+		return "", NewValidationError("subtasks", "subtasks must be cascade, promote or reject")
+	}
+}
+
+// SubtaskProgress counts the completed tasks among all the descendants of a task
+type SubtaskProgress struct {
+	Completed int
+	Total     int
+	// Percent is rounded down, and 0 for a task without subtasks
+	Percent int
+}
+
+// SubtaskTree is a task with its subtasks, at any depth
+type SubtaskTree struct {
+	Task     entities.Task
+	Progress SubtaskProgress
+	Subtasks []SubtaskTree
+}
+
+// NewSubtaskTree arranges the descendants of root by parent.
+// Descendants whose parent is not part of the tree are left out.
+func NewSubtaskTree(root entities.Task, descendants []entities.Task) SubtaskTree {
+	children := make(map[int64][]entities.Task)
+	for _, task := range descendants {
+		if task.Parent != nil {
+			children[task.Parent.ID] = append(children[task.Parent.ID], task)
+		}
+	}
+	return buildSubtaskTree(root, children, map[int64]bool{})
+}
+
+// buildSubtaskTree keeps track of the tasks visited, so that parents looping on themselves end the tree
+func buildSubtaskTree(task entities.Task, children map[int64][]entities.Task, visited map[int64]bool) SubtaskTree {
+	visited[task.ID] = true
+	tree := SubtaskTree{Task: task, Subtasks: []SubtaskTree{}}
+
+	for _, child := range children[task.ID] {
+		if visited[child.ID] {
+			continue
+		}
+		subtree := buildSubtaskTree(child, children, visited)
+		tree.Progress.Total += subtree.Progress.Total + 1
+		tree.Progress.Completed += subtree.Progress.Completed
+		if child.Completed {
+			tree.Progress.Completed++
+		}
+		tree.Subtasks = append(tree.Subtasks, subtree)
+	}
+
+	if tree.Progress.Total > 0 {
+		tree.Progress.Percent = tree.Progress.Completed * 100 / tree.Progress.Total
+	}
+	return tree
+}
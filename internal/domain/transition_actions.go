@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"todo-api/internal/domain/entities"
+)
+
+// Notifier delivers messages about a task to users
+type Notifier interface {
+	Notify(userIDs []int64, task entities.Task, message string) error
+}
+
+// TransitionActionRunner runs the actions of a workflow once a task changed status.
+// A failing action does not undo the transition: it is recorded and the next actions still run.
+type TransitionActionRunner struct {
+	tasks        TaskRepository
+	workflows    WorkflowRepository
+	dependencies TaskDependencyRepository
+	failures     ActionFailureRepository
+	notifier     Notifier
+}
+
+func NewTransitionActionRunner(tasks TaskRepository, workflows WorkflowRepository, dependencies TaskDependencyRepository, failures ActionFailureRepository, notifier Notifier) *TransitionActionRunner {
+	return &TransitionActionRunner{
+		tasks:        tasks,
+		workflows:    workflows,
+		dependencies: dependencies,
+		failures:     failures,
+		notifier:     notifier,
+	}
+}
+
+// Run executes the actions of the move of a task from the status fromID to its current status,
+// and returns the failures it recorded. The task must have its workflow loaded and its new status
+// stored; it is updated in place by the actions changing it, which are recorded as made by actorID.
+func (self *TransitionActionRunner) Run(ctx context.Context, task *entities.Task, fromID int64, actorID int64, teamIDs []int64) []entities.ActionFailure {
+	return self.run(ctx, task, fromID, actorID, teamIDs, map[int64]bool{})
+}
+
+// run keeps track of the tasks visited, so that a parent chain looping on itself cannot advance forever
+func (self *TransitionActionRunner) run(ctx context.Context, task *entities.Task, fromID int64, actorID int64, teamIDs []int64, visited map[int64]bool) []entities.ActionFailure {
+	visited[task.ID] = true
+	failures := []entities.ActionFailure{}
+
+	for _, action := range task.Workflow.ActionsFor(fromID, task.Status.ID) {
+		var err error
+		switch action.Kind {
+		case entities.ActionAssign:
+			err = self.update(ctx, task, actorID, teamIDs, func(updated *entities.Task) {
+				updated.AssignTo(action.UserID)
+			})
+		case entities.ActionSetDeadline:
+			err = self.update(ctx, task, actorID, teamIDs, func(updated *entities.Task) {
+				updated.Deadline = entities.NewDateTime(time.Now().Add(time.Duration(action.DeadlineOffsetHours) * time.Hour))
+				updated.UpdatedAt = entities.Now()
+			})
+		case entities.ActionNotifyWatchers:
+			message := fmt.Sprintf("Task %q moved to %s", task.Title, task.Status.Label)
+			err = self.notifier.Notify(action.WatcherIDs, *task, message)
+		case entities.ActionAdvanceParent:
+			var parentFailures []entities.ActionFailure
+			parentFailures, err = self.advanceParent(ctx, task, action, actorID, teamIDs, visited)
+			failures = append(failures, parentFailures...)
+		}
+
+		if err != nil {
+			failures = append(failures, self.record(ctx, entities.NewActionFailure(task.ID, action, fromID, err)))
+		}
+	}
+
+	return failures
+}
+
+// update stores a change of the task, which is only applied in place once stored
+func (self *TransitionActionRunner) update(ctx context.Context, task *entities.Task, actorID int64, teamIDs []int64, change func(updated *entities.Task)) error {
+	updated := *task
+	change(&updated)
+	stored, err := self.tasks.Update(ctx, updated, actorID, teamIDs)
+	if err != nil {
+		return err
+	}
+	*task = stored
+	return nil
+}
+
+// advanceParent moves the parent of the task forward when none of its subtasks is left open,
+// then runs the actions of the parent's own transition
+func (self *TransitionActionRunner) advanceParent(ctx context.Context, task *entities.Task, action entities.TransitionAction, actorID int64, teamIDs []int64, visited map[int64]bool) ([]entities.ActionFailure, error) {
+	if task.Parent == nil || visited[task.Parent.ID] {
+		return nil, nil
+	}
+
+	subtasks, err := self.tasks.GetAllByParent(ctx, task.Parent.ID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, subtask := range subtasks {
+		if !subtask.Completed {
+			return nil, nil
+		}
+	}
+
+	parent, err := self.tasks.GetByID(ctx, task.Parent.ID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	if parent.Status.ID == action.ParentStatusID {
+		return nil, nil
+	}
+	if parent.Workflow, err = self.workflows.GetByID(ctx, parent.Workflow.ID, teamIDs); err != nil {
+		return nil, err
+	}
+
+	blockers, err := self.dependencies.GetBlockerStates(ctx, parent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromID := parent.Status.ID
+	if err := parent.TransitionTo(action.ParentStatusID, entities.TransitionContext{Subtasks: subtasks, Blockers: blockers}); err != nil {
+		return nil, fmt.Errorf("parent task %d: %w", parent.ID, err)
+	}
+	stored, err := self.tasks.Update(ctx, parent, actorID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	// The actions of the parent update it again, from the version just stored
+	parent.Version = stored.Version
+
+	return self.run(ctx, &parent, fromID, actorID, teamIDs, visited), nil
+}
+
+// record stores a failure; a failure that cannot be stored is still logged and returned
+func (self *TransitionActionRunner) record(ctx context.Context, failure entities.ActionFailure) entities.ActionFailure {
+	recorded, err := self.failures.Record(ctx, failure)
+	if err != nil {
+		log.Printf("Failed to record %s action failure of task %d: %v", failure.Action, failure.TaskID, err)
+		return failure
+	}
+	return recorded
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"todo-api/internal/domain/entities"
+)
+
+// WorkflowService owns the business rules of workflows: their validation, who authored them,
+// and moving their tasks when a change would leave tasks outside of them.
+// Methods taking teamIDs only see workflows owned by one of the teams; lookups of workflows
+// the teams cannot see fail with the *NotFoundError of the repository.
+type WorkflowService struct {
+	workflows WorkflowRepository
+}
+
+func NewWorkflowService(workflows WorkflowRepository) *WorkflowService {
+	return &WorkflowService{
+		workflows: workflows,
+	}
+}
+
+// Create stores a new workflow authored by actorID. Identity and timestamps are set here, whatever the workflow holds.
+func (self *WorkflowService) Create(ctx context.Context, workflow entities.Workflow, actorID int64) (entities.Workflow, error) {
+	if err := workflow.Validate(); err != nil {
+		return entities.Workflow{}, &kindError{err: err, kind: ErrInvalidWorkflow}
+	}
+
+	workflow.ID = 0
+	workflow.Author = entities.User{ID: actorID}
+	workflow.CreatedAt = entities.Now()
+
+	return self.workflows.Create(ctx, workflow)
+}
+
+func (self *WorkflowService) Get(ctx context.Context, id int64, teamIDs []int64) (entities.Workflow, error) {
+	workflow, err := self.workflows.GetByID(ctx, id, teamIDs)
+	if err != nil {
+		return entities.Workflow{}, err
+	}
+	return workflow, nil
+}
+
+func (self *WorkflowService) GetAll(ctx context.Context, teamIDs []int64) ([]entities.Workflow, error) {
+	return self.workflows.GetAll(ctx, teamIDs)
+}
+
+// Update replaces a workflow, keeping its author and creation time; a zero team keeps the stored one.
+// Tasks in statuses the update removes must be remapped by statusMapping, otherwise the update fails
+// with an *entities.OrphanedTasksError. A version other than the stored one fails with ErrVersionConflict,
+// a zero version updates whatever is stored.
+func (self *WorkflowService) Update(ctx context.Context, id int64, workflow entities.Workflow, statusMapping map[int64]int64, actorID int64, teamIDs []int64) (entities.Workflow, error) {
+	if err := workflow.Validate(); err != nil {
+		return entities.Workflow{}, &kindError{err: err, kind: ErrInvalidWorkflow}
+	}
+
+	existing, err := self.workflows.GetByID(ctx, id, teamIDs)
+	if err != nil {
+		return entities.Workflow{}, err
+	}
+	if workflow.Author.ID != 0 && workflow.Author.ID != existing.Author.ID {
+		return entities.Workflow{}, ErrWorkflowAuthorChange
+	}
+
+	if workflow.TeamID == 0 {
+		workflow.TeamID = existing.TeamID
+	}
+	if workflow.Version == 0 {
+		workflow.Version = existing.Version
+	}
+	workflow.ID = id
+	workflow.Author = existing.Author
+	workflow.CreatedAt = existing.CreatedAt
+
+	var migration *entities.WorkflowMigration
+	if statusMapping != nil {
+		migration = &entities.WorkflowMigration{SourceWorkflowID: id, StatusMapping: statusMapping, ActorID: actorID}
+	}
+	return self.workflows.Update(ctx, workflow, migration, teamIDs)
+}
+
+// Remove deletes a workflow. A workflow still used by tasks is only deleted when migration moves them
+// to another workflow, otherwise it fails with an *entities.OrphanedTasksError. Versions are checked as by Update.
+func (self *WorkflowService) Remove(ctx context.Context, id int64, version int64, migration *entities.WorkflowMigration, teamIDs []int64) error {
+	return self.workflows.Remove(ctx, id, version, migration, teamIDs)
+}
+
+// Migrate moves the tasks of a workflow to other statuses, possibly of another workflow, in one transaction
+// and returns how many of them changed
+func (self *WorkflowService) Migrate(ctx context.Context, migration entities.WorkflowMigration, teamIDs []int64) (int64, error) {
+	if _, err := self.workflows.GetByID(ctx, migration.SourceWorkflowID, teamIDs); err != nil {
+		return 0, err
+	}
+	return self.workflows.Migrate(ctx, migration, teamIDs)
+}
@@ -0,0 +1,250 @@
+package v1
+
+import "todo-api/internal/domain/entities"
+
+// CreateTaskRequest is the body of POST /todo. The status defaults to the initial status of the workflow
+// and the team to the caller's only team. A deadline, when given, must be in the future.
+type CreateTaskRequest struct {
+	Title         string            `json:"title" binding:"required,notblank,max=255"`
+	Description   string            `json:"description" binding:"max=10000"`
+	Deadline      entities.DateTime `json:"deadline" binding:"omitempty,gt"`
+	StatusID      int64             `json:"status_id" binding:"omitempty,gt=0"`
+	ParentID      int64             `json:"parent_id" binding:"omitempty,gt=0"`
+	ResponsibleID int64             `json:"responsible_id" binding:"omitempty,gt=0"`
+	WorkflowID    int64             `json:"workflow_id" binding:"omitempty,gt=0"`
+	TypeID        int64             `json:"type_id" binding:"omitempty,gt=0"`
+	TeamID        int64             `json:"team_id" binding:"omitempty,gt=0"`
+}
+
+func (r CreateTaskRequest) Task() entities.Task {
+	return entities.Task{
+		Title:         r.Title,
+		Description:   r.Description,
+		Deadline:      r.Deadline,
+		Status:        entities.TaskStatus{ID: r.StatusID},
+		Parent:        parentTask(r.ParentID),
+		ResponsibleID: r.ResponsibleID,
+		Workflow:      entities.Workflow{ID: r.WorkflowID},
+		Type:          entities.TaskType{ID: r.TypeID},
+		TeamID:        r.TeamID,
+	}
+}
+
+// UpdateTaskRequest is the body of PUT /todo/:id, and the document PATCH /todo/:id patches.
+// The author and status cannot be changed: given, they must be the stored ones.
+// A zero team keeps the stored one.
+type UpdateTaskRequest struct {
+	Title         string            `json:"title" binding:"required,notblank,max=255"`
+	Description   string            `json:"description" binding:"max=10000"`
+	Deadline      entities.DateTime `json:"deadline"`
+	AuthorID      int64             `json:"author_id" binding:"omitempty,gt=0"`
+	StatusID      int64             `json:"status_id" binding:"omitempty,gt=0"`
+	ParentID      int64             `json:"parent_id" binding:"omitempty,gt=0"`
+	ResponsibleID int64             `json:"responsible_id" binding:"omitempty,gt=0"`
+	WorkflowID    int64             `json:"workflow_id" binding:"omitempty,gt=0"`
+	TypeID        int64             `json:"type_id" binding:"omitempty,gt=0"`
+	TeamID        int64             `json:"team_id" binding:"omitempty,gt=0"`
+}
+
+// NewUpdateTaskRequest returns the request that would store the task as it is
+func NewUpdateTaskRequest(task entities.Task) UpdateTaskRequest {
+	request := UpdateTaskRequest{
+		Title:         task.Title,
+		Description:   task.Description,
+		Deadline:      task.Deadline,
+		AuthorID:      task.AuthorID,
+		StatusID:      task.Status.ID,
+		ResponsibleID: task.ResponsibleID,
+		WorkflowID:    task.Workflow.ID,
+		TypeID:        task.Type.ID,
+		TeamID:        task.TeamID,
+	}
+	if task.Parent != nil {
+		request.ParentID = task.Parent.ID
+	}
+	return request
+}
+
+func (r UpdateTaskRequest) Task() entities.Task {
+	return entities.Task{
+		Title:         r.Title,
+		Description:   r.Description,
+		Deadline:      r.Deadline,
+		AuthorID:      r.AuthorID,
+		Status:        entities.TaskStatus{ID: r.StatusID},
+		Parent:        parentTask(r.ParentID),
+		ResponsibleID: r.ResponsibleID,
+		Workflow:      entities.Workflow{ID: r.WorkflowID},
+		Type:          entities.TaskType{ID: r.TypeID},
+		TeamID:        r.TeamID,
+	}
+}
+
+func parentTask(parentID int64) *entities.Task {
+	if parentID == 0 {
+		return nil
+	}
+	return &entities.Task{ID: parentID}
+}
+
+// TransitionRequest is the body of POST /todo/:id/transition
+type TransitionRequest struct {
+	StatusID int64 `json:"status_id" binding:"required,gt=0"`
+}
+
+// ReparentRequest is the body of PUT /todo/:id/parent, a null or zero parent_id moves the task to the top level
+type ReparentRequest struct {
+	ParentID *int64 `json:"parent_id" binding:"omitempty,gte=0"`
+}
+
+// DependencyRequest is the body of POST /todo/:id/dependencies, blocked_by is the task to wait for
+type DependencyRequest struct {
+	BlockedBy int64 `json:"blocked_by" binding:"required,gt=0"`
+}
+
+// TaskResponse is a task as the API returns it. Related entities only have their ID set
+// unless they were expanded.
+type TaskResponse struct {
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Status        TaskStatusResponse `json:"status"`
+	Parent        *TaskResponse      `json:"parent"`
+	AuthorID      int64              `json:"author_id"`
+	Author        *UserResponse      `json:"author,omitempty"`
+	Deadline      entities.DateTime  `json:"deadline"`
+	CreatedAt     entities.DateTime  `json:"created_at"`
+	UpdatedAt     entities.DateTime  `json:"updated_at"`
+	ResponsibleID int64              `json:"responsible_id"`
+	Responsible   *UserResponse      `json:"responsible,omitempty"`
+	Workflow      WorkflowResponse   `json:"workflow"`
+	Type          TaskTypeResponse   `json:"type"`
+	TeamID        int64              `json:"team_id"`
+	Completed     bool               `json:"completed"`
+	Version       int64              `json:"version"`
+}
+
+func NewTaskResponse(task entities.Task) TaskResponse {
+	response := TaskResponse{
+		ID:            task.ID,
+		Title:         task.Title,
+		Description:   task.Description,
+		Status:        NewTaskStatusResponse(task.Status),
+		AuthorID:      task.AuthorID,
+		Deadline:      task.Deadline,
+		CreatedAt:     task.CreatedAt,
+		UpdatedAt:     task.UpdatedAt,
+		ResponsibleID: task.ResponsibleID,
+		Workflow:      NewWorkflowResponse(task.Workflow),
+		Type:          NewTaskTypeResponse(task.Type),
+		TeamID:        task.TeamID,
+		Completed:     task.Completed,
+		Version:       task.Version,
+	}
+	if task.Parent != nil {
+		parent := NewTaskResponse(*task.Parent)
+		response.Parent = &parent
+	}
+	if task.Author != nil {
+		author := NewUserResponse(*task.Author)
+		response.Author = &author
+	}
+	if task.Responsible != nil {
+		responsible := NewUserResponse(*task.Responsible)
+		response.Responsible = &responsible
+	}
+	return response
+}
+
+// NewTaskResponses converts a list of tasks, an empty list rather than null when there is none
+func NewTaskResponses(tasks []entities.Task) []TaskResponse {
+	responses := make([]TaskResponse, len(tasks))
+	for i, task := range tasks {
+		responses[i] = NewTaskResponse(task)
+	}
+	return responses
+}
+
+// TaskEventResponse is the change of one field of a task, nil values were not set
+type TaskEventResponse struct {
+	ID        int64             `json:"id"`
+	TaskID    int64             `json:"task_id"`
+	ActorID   int64             `json:"actor_id"`
+	Field     string            `json:"field"`
+	OldValue  *string           `json:"old_value"`
+	NewValue  *string           `json:"new_value"`
+	CreatedAt entities.DateTime `json:"created_at"`
+}
+
+func NewTaskEventResponses(events []entities.TaskEvent) []TaskEventResponse {
+	responses := make([]TaskEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = TaskEventResponse{
+			ID:        event.ID,
+			TaskID:    event.TaskID,
+			ActorID:   event.ActorID,
+			Field:     event.Field,
+			OldValue:  event.OldValue,
+			NewValue:  event.NewValue,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// ActionFailureResponse is a workflow action that could not be run after a transition
+type ActionFailureResponse struct {
+	ID           int64               `json:"id"`
+	TaskID       int64               `json:"task_id"`
+	FromStatusID int64               `json:"from_status_id"`
+	ToStatusID   int64               `json:"to_status_id"`
+	Action       entities.ActionKind `json:"action"`
+	Error        string              `json:"error"`
+	CreatedAt    entities.DateTime   `json:"created_at"`
+}
+
+func NewActionFailureResponses(failures []entities.ActionFailure) []ActionFailureResponse {
+	responses := make([]ActionFailureResponse, len(failures))
+	for i, failure := range failures {
+		responses[i] = ActionFailureResponse{
+			ID:           failure.ID,
+			TaskID:       failure.TaskID,
+			FromStatusID: failure.FromStatusID,
+			ToStatusID:   failure.ToStatusID,
+			Action:       failure.Action,
+			Error:        failure.Error,
+			CreatedAt:    failure.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// TaskDependencyResponse records that blocked_id cannot be completed before blocker_id is
+type TaskDependencyResponse struct {
+	BlockerID int64             `json:"blocker_id"`
+	BlockedID int64             `json:"blocked_id"`
+	CreatedAt entities.DateTime `json:"created_at"`
+}
+
+func NewTaskDependencyResponse(dependency entities.TaskDependency) TaskDependencyResponse {
+	return TaskDependencyResponse{
+		BlockerID: dependency.BlockerID,
+		BlockedID: dependency.BlockedID,
+		CreatedAt: dependency.CreatedAt,
+	}
+}
+
+// GuardFailureResponse is a guard of the workflow a task failed when moving to another status
+type GuardFailureResponse struct {
+	Kind    entities.GuardKind `json:"kind"`
+	Field   string             `json:"field"`
+	Message string             `json:"message"`
+}
+
+func NewGuardFailureResponses(err *entities.GuardError) []GuardFailureResponse {
+	responses := make([]GuardFailureResponse, len(err.Failures))
+	for i, failure := range err.Failures {
+		responses[i] = GuardFailureResponse{Kind: failure.Guard.Kind, Field: failure.Guard.Field, Message: failure.Message}
+	}
+	return responses
+}
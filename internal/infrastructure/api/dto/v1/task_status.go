@@ -0,0 +1,33 @@
+package v1
+
+import "todo-api/internal/domain/entities"
+
+// TaskStatusRequest is the body of POST and PUT /statuses
+type TaskStatusRequest struct {
+	Label  string `json:"label" binding:"required,max=100"`
+	Active bool   `json:"active"`
+}
+
+func (r TaskStatusRequest) TaskStatus() entities.TaskStatus {
+	return entities.NewTaskStatus(r.Label, r.Active)
+}
+
+// TaskStatusResponse is a task status as the API returns it
+type TaskStatusResponse struct {
+	ID      int64  `json:"id"`
+	Label   string `json:"label"`
+	Active  bool   `json:"active"`
+	Version int64  `json:"version"`
+}
+
+func NewTaskStatusResponse(status entities.TaskStatus) TaskStatusResponse {
+	return TaskStatusResponse{ID: status.ID, Label: status.Label, Active: status.Active, Version: status.Version}
+}
+
+func NewTaskStatusResponses(statuses []entities.TaskStatus) []TaskStatusResponse {
+	responses := make([]TaskStatusResponse, len(statuses))
+	for i, status := range statuses {
+		responses[i] = NewTaskStatusResponse(status)
+	}
+	return responses
+}
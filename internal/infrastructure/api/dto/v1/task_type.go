@@ -0,0 +1,35 @@
+package v1
+
+import "todo-api/internal/domain/entities"
+
+// TaskTypeRequest is the body of POST and PUT /task-type, a zero team_id shares the type with every team
+type TaskTypeRequest struct {
+	Name   string `json:"name" binding:"required,max=100"`
+	TeamID int64  `json:"team_id" binding:"omitempty,gt=0"`
+}
+
+func (r TaskTypeRequest) TaskType() entities.TaskType {
+	taskType := entities.NewTaskType(r.Name)
+	taskType.TeamID = r.TeamID
+	return taskType
+}
+
+// TaskTypeResponse is a task type as the API returns it
+type TaskTypeResponse struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	TeamID  int64  `json:"team_id"`
+	Version int64  `json:"version"`
+}
+
+func NewTaskTypeResponse(taskType entities.TaskType) TaskTypeResponse {
+	return TaskTypeResponse{ID: taskType.ID, Name: taskType.Name, TeamID: taskType.TeamID, Version: taskType.Version}
+}
+
+func NewTaskTypeResponses(taskTypes []entities.TaskType) []TaskTypeResponse {
+	responses := make([]TaskTypeResponse, len(taskTypes))
+	for i, taskType := range taskTypes {
+		responses[i] = NewTaskTypeResponse(taskType)
+	}
+	return responses
+}
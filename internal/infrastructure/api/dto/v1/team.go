@@ -0,0 +1,32 @@
+package v1
+
+import "todo-api/internal/domain/entities"
+
+// TeamRequest is the body of POST and PUT /teams
+type TeamRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// TeamMemberRequest is the body of POST /teams/:id/members
+type TeamMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required,gt=0"`
+}
+
+// TeamResponse is a team as the API returns it
+type TeamResponse struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	CreatedAt entities.DateTime `json:"created_at"`
+}
+
+func NewTeamResponse(team entities.Team) TeamResponse {
+	return TeamResponse{ID: team.ID, Name: team.Name, CreatedAt: team.CreatedAt}
+}
+
+func NewTeamResponses(teams []entities.Team) []TeamResponse {
+	responses := make([]TeamResponse, len(teams))
+	for i, team := range teams {
+		responses[i] = NewTeamResponse(team)
+	}
+	return responses
+}
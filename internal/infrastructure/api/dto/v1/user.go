@@ -0,0 +1,63 @@
+package v1
+
+import "todo-api/internal/domain/entities"
+
+// CreateUserRequest is the body of POST /users, the password is hashed and never returned.
+// Its limit is in bytes, the most bcrypt hashes.
+type CreateUserRequest struct {
+	Name     string        `json:"name" binding:"required,max=255"`
+	Username string        `json:"username" binding:"required,max=100"`
+	Email    string        `json:"email" binding:"required,email,max=255"`
+	Password string        `json:"password" binding:"required,maxbytes=72"`
+	Role     entities.Role `json:"role" binding:"omitempty,oneof=admin member viewer"`
+}
+
+func (r CreateUserRequest) User() (entities.User, error) {
+	user, err := entities.NewUser(r.Name, r.Username, r.Email, r.Password)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if r.Role != "" {
+		user.Role = r.Role
+	}
+	return user, nil
+}
+
+// UpdateUserRequest is the body of PUT /users/:id, an empty role keeps the stored one
+type UpdateUserRequest struct {
+	Name     string        `json:"name" binding:"required,max=255"`
+	Username string        `json:"username" binding:"required,max=100"`
+	Email    string        `json:"email" binding:"required,email,max=255"`
+	Role     entities.Role `json:"role" binding:"omitempty,oneof=admin member viewer"`
+}
+
+// UserResponse is a user as the API returns it, without its password hash
+type UserResponse struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Username  string            `json:"username"`
+	Email     string            `json:"email"`
+	Role      entities.Role     `json:"role"`
+	Active    bool              `json:"active"`
+	CreatedAt entities.DateTime `json:"created_at"`
+}
+
+func NewUserResponse(user entities.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		Active:    user.Active,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func NewUserResponses(users []entities.User) []UserResponse {
+	responses := make([]UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = NewUserResponse(user)
+	}
+	return responses
+}
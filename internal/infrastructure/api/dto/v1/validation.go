@@ -0,0 +1,163 @@
+// Package v1 holds the bodies of the /api/v1 endpoints: requests are bound and validated through their
+// binding tags, responses are built from the entities. JSON keys are snake_case.
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"todo-api/internal/domain/entities"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+)
+
+func init() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Fields are reported by their JSON key, and dates are validated as the time they hold
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if date, ok := field.Interface().(entities.DateTime); ok {
+			return date.Time
+		}
+		return nil
+	}, entities.DateTime{})
+	// Strings made only of whitespace are as good as missing
+	validate.RegisterValidation("notblank", validators.NotBlank)
+	// Limits on what a string takes once encoded rather than on its characters
+	validate.RegisterValidation("maxbytes", func(field validator.FieldLevel) bool {
+		limit, err := strconv.Atoi(field.Param())
+		return err == nil && len(field.Field().String()) <= limit
+	})
+}
+
+// FieldError is the reason a field of a request was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field of a request that was rejected
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (self *ValidationError) Error() string {
+	messages := make([]string, len(self.Fields))
+	for i, field := range self.Fields {
+		messages[i] = field.Message
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+// Validate checks a request against its binding tags, as binding it does
+func Validate(request interface{}) error {
+	return NewValidationError(binding.Validator.ValidateStruct(request))
+}
+
+// NewValidationError turns the error of binding or validating a request into a *ValidationError
+// when it can be pinned on fields. Other errors, such as malformed JSON, are returned as they are.
+func NewValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var invalid validator.ValidationErrors
+	if errors.As(err, &invalid) {
+		fields := make([]FieldError, len(invalid))
+		for i, fieldErr := range invalid {
+			fields[i] = newFieldError(fieldErr)
+		}
+		return &ValidationError{Fields: fields}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &ValidationError{Fields: []FieldError{{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("%s must be %s", typeErr.Field, jsonKind(typeErr.Type)),
+		}}}
+	}
+
+	return err
+}
+
+func newFieldError(err validator.FieldError) FieldError {
+	// The namespace starts with the name of the request type
+	_, field, _ := strings.Cut(err.Namespace(), ".")
+	if field == "" {
+		field = err.Field()
+	}
+
+	var message string
+	switch err.Tag() {
+	case "required":
+		message = "is required"
+	case "max":
+		message = fmt.Sprintf("must be at most %s characters long", err.Param())
+		if err.Kind() != reflect.String {
+			message = fmt.Sprintf("must be at most %s", err.Param())
+		}
+	case "notblank":
+		message = "must not be blank"
+	case "maxbytes":
+		message = fmt.Sprintf("must be at most %s bytes long", err.Param())
+	case "min":
+		message = fmt.Sprintf("must be at least %s characters long", err.Param())
+		if err.Kind() != reflect.String {
+			message = fmt.Sprintf("must be at least %s", err.Param())
+		}
+	case "gt":
+		message = fmt.Sprintf("must be greater than %s", err.Param())
+		if err.Param() == "" {
+			message = "must be in the future"
+		} else if err.Param() == "0" {
+			message = "must be a positive ID"
+		}
+	case "gte":
+		message = fmt.Sprintf("must be at least %s", err.Param())
+	case "oneof":
+		message = "must be one of " + strings.Join(strings.Fields(err.Param()), ", ")
+	case "email":
+		message = "must be an email address"
+	default:
+		message = fmt.Sprintf("does not satisfy %s", err.Tag())
+	}
+	return FieldError{Field: field, Message: field + " " + message}
+}
+
+// jsonKind names the JSON value a Go type is decoded from, with its article
+func jsonKind(kind reflect.Type) string {
+	switch kind.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,197 @@
+package v1
+
+import "todo-api/internal/domain/entities"
+
+// WorkflowRequest is the body of POST /workflows. Statuses are keyed by their position in the workflow.
+type WorkflowRequest struct {
+	Name       string                          `json:"name" binding:"required,max=255"`
+	Statuses   map[uint8]WorkflowStatusRequest `json:"statuses" binding:"required,min=1,dive"`
+	Sequential bool                            `json:"sequential"`
+	Graph      *WorkflowGraph                  `json:"graph"`
+	Guards     []TransitionGuard               `json:"guards" binding:"dive"`
+	Actions    []TransitionAction              `json:"actions" binding:"dive"`
+	TeamID     int64                           `json:"team_id" binding:"omitempty,gt=0"`
+}
+
+// WorkflowStatusRequest is a status of a workflow
+type WorkflowStatusRequest struct {
+	ID     int64  `json:"id" binding:"required,gt=0"`
+	Label  string `json:"label" binding:"max=100"`
+	Active bool   `json:"active"`
+}
+
+func (r WorkflowRequest) Workflow() entities.Workflow {
+	workflow := entities.Workflow{
+		Name:       r.Name,
+		Statuses:   make(map[uint8]entities.TaskStatus, len(r.Statuses)),
+		Sequential: r.Sequential,
+		Guards:     make([]entities.TransitionGuard, len(r.Guards)),
+		Actions:    make([]entities.TransitionAction, len(r.Actions)),
+		TeamID:     r.TeamID,
+	}
+	for position, status := range r.Statuses {
+		workflow.Statuses[position] = entities.TaskStatus{ID: status.ID, Label: status.Label, Active: status.Active}
+	}
+	if r.Graph != nil {
+		graph := r.Graph.graph()
+		workflow.Graph = &graph
+	}
+	for i, guard := range r.Guards {
+		workflow.Guards[i] = guard.guard()
+	}
+	for i, action := range r.Actions {
+		workflow.Actions[i] = action.action()
+	}
+	return workflow
+}
+
+// UpdateWorkflowRequest is the body of PUT /workflows/:id: the workflow along with status_mapping,
+// which maps the IDs of the statuses the update removes to IDs of statuses kept
+type UpdateWorkflowRequest struct {
+	WorkflowRequest
+	StatusMapping map[int64]int64 `json:"status_mapping"`
+}
+
+// WorkflowMigrationRequest moves the tasks of a workflow: status_mapping maps IDs of statuses in use
+// to IDs of statuses of the target workflow, which defaults to the workflow itself
+type WorkflowMigrationRequest struct {
+	TargetWorkflowID int64           `json:"target_workflow_id" binding:"omitempty,gt=0"`
+	StatusMapping    map[int64]int64 `json:"status_mapping"`
+}
+
+func (r WorkflowMigrationRequest) Migration(sourceID int64, actorID int64) entities.WorkflowMigration {
+	return entities.WorkflowMigration{
+		SourceWorkflowID: sourceID,
+		TargetWorkflowID: r.TargetWorkflowID,
+		StatusMapping:    r.StatusMapping,
+		ActorID:          actorID,
+	}
+}
+
+// WorkflowGraph lists the moves allowed between the statuses of a workflow, by status ID
+type WorkflowGraph struct {
+	InitialStatusID   int64        `json:"initial_status_id" binding:"required,gt=0"`
+	TerminalStatusIDs []int64      `json:"terminal_status_ids" binding:"required,min=1,dive,gt=0"`
+	Transitions       []Transition `json:"transitions" binding:"dive"`
+}
+
+type Transition struct {
+	From int64 `json:"from" binding:"required,gt=0"`
+	To   int64 `json:"to" binding:"required,gt=0"`
+}
+
+func (r WorkflowGraph) graph() entities.WorkflowGraph {
+	graph := entities.WorkflowGraph{
+		InitialStatusID:   r.InitialStatusID,
+		TerminalStatusIDs: r.TerminalStatusIDs,
+		Transitions:       make([]entities.Transition, len(r.Transitions)),
+	}
+	for i, transition := range r.Transitions {
+		graph.Transitions[i] = entities.Transition{From: transition.From, To: transition.To}
+	}
+	return graph
+}
+
+// TransitionGuard is a condition tasks must meet to enter a status, a zero from_status_id applies it
+// whatever the current status
+type TransitionGuard struct {
+	FromStatusID int64              `json:"from_status_id" binding:"omitempty,gt=0"`
+	ToStatusID   int64              `json:"to_status_id" binding:"required,gt=0"`
+	Kind         entities.GuardKind `json:"kind" binding:"required,oneof=required_field subtasks_completed"`
+	Field        string             `json:"field,omitempty"`
+}
+
+func (r TransitionGuard) guard() entities.TransitionGuard {
+	return entities.TransitionGuard{FromStatusID: r.FromStatusID, ToStatusID: r.ToStatusID, Kind: r.Kind, Field: r.Field}
+}
+
+// TransitionAction is a side effect run after tasks entered a status, a zero from_status_id runs it
+// whatever the previous status
+type TransitionAction struct {
+	FromStatusID        int64               `json:"from_status_id" binding:"omitempty,gt=0"`
+	ToStatusID          int64               `json:"to_status_id" binding:"required,gt=0"`
+	Kind                entities.ActionKind `json:"kind" binding:"required,oneof=assign set_deadline notify_watchers advance_parent"`
+	UserID              int64               `json:"user_id,omitempty" binding:"omitempty,gt=0"`
+	DeadlineOffsetHours int                 `json:"deadline_offset_hours,omitempty"`
+	WatcherIDs          []int64             `json:"watcher_ids,omitempty" binding:"dive,gt=0"`
+	ParentStatusID      int64               `json:"parent_status_id,omitempty" binding:"omitempty,gt=0"`
+}
+
+func (r TransitionAction) action() entities.TransitionAction {
+	return entities.TransitionAction{
+		FromStatusID:        r.FromStatusID,
+		ToStatusID:          r.ToStatusID,
+		Kind:                r.Kind,
+		UserID:              r.UserID,
+		DeadlineOffsetHours: r.DeadlineOffsetHours,
+		WatcherIDs:          r.WatcherIDs,
+		ParentStatusID:      r.ParentStatusID,
+	}
+}
+
+// WorkflowResponse is a workflow as the API returns it
+type WorkflowResponse struct {
+	ID         int64                        `json:"id"`
+	Name       string                       `json:"name"`
+	Statuses   map[uint8]TaskStatusResponse `json:"statuses"`
+	Sequential bool                         `json:"sequential"`
+	Graph      *WorkflowGraph               `json:"graph"`
+	Guards     []TransitionGuard            `json:"guards"`
+	Actions    []TransitionAction           `json:"actions"`
+	AuthorID   int64                        `json:"author_id"`
+	TeamID     int64                        `json:"team_id"`
+	CreatedAt  entities.DateTime            `json:"created_at"`
+	Version    int64                        `json:"version"`
+}
+
+func NewWorkflowResponse(workflow entities.Workflow) WorkflowResponse {
+	response := WorkflowResponse{
+		ID:         workflow.ID,
+		Name:       workflow.Name,
+		Statuses:   make(map[uint8]TaskStatusResponse, len(workflow.Statuses)),
+		Sequential: workflow.Sequential,
+		Guards:     make([]TransitionGuard, len(workflow.Guards)),
+		Actions:    make([]TransitionAction, len(workflow.Actions)),
+		AuthorID:   workflow.Author.ID,
+		TeamID:     workflow.TeamID,
+		CreatedAt:  workflow.CreatedAt,
+		Version:    workflow.Version,
+	}
+	for position, status := range workflow.Statuses {
+		response.Statuses[position] = NewTaskStatusResponse(status)
+	}
+	if workflow.Graph != nil {
+		graph := WorkflowGraph{
+			InitialStatusID:   workflow.Graph.InitialStatusID,
+			TerminalStatusIDs: workflow.Graph.TerminalStatusIDs,
+			Transitions:       make([]Transition, len(workflow.Graph.Transitions)),
+		}
+		for i, transition := range workflow.Graph.Transitions {
+			graph.Transitions[i] = Transition{From: transition.From, To: transition.To}
+		}
+		response.Graph = &graph
+	}
+	for i, guard := range workflow.Guards {
+		response.Guards[i] = TransitionGuard{FromStatusID: guard.FromStatusID, ToStatusID: guard.ToStatusID, Kind: guard.Kind, Field: guard.Field}
+	}
+	for i, action := range workflow.Actions {
+		response.Actions[i] = TransitionAction{
+			FromStatusID:        action.FromStatusID,
+			ToStatusID:          action.ToStatusID,
+			Kind:                action.Kind,
+			UserID:              action.UserID,
+			DeadlineOffsetHours: action.DeadlineOffsetHours,
+			WatcherIDs:          action.WatcherIDs,
+			ParentStatusID:      action.ParentStatusID,
+		}
+	}
+	return response
+}
+
+func NewWorkflowResponses(workflows []entities.Workflow) []WorkflowResponse {
+	responses := make([]WorkflowResponse, len(workflows))
+	for i, workflow := range workflows {
+		responses[i] = NewWorkflowResponse(workflow)
+	}
+	return responses
+}
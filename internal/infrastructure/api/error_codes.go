@@ -1,7 +1,28 @@
 package api
 
-var ERROR_CODE_HASH_PASS_FAILED = "ERR-HP001"
 var ERROR_CODE_WRONG_CREDENTIALS = "ERR-WP041"
 var ERROR_DUPLICATED_STATUS = "ERR-DS041"
 var ERROR_ENVIRONMENT_VARIABLES = "ERR-ENV001"
 var ERROR_DATABASE_CONNECTION = "ERR-DB001"
+var ERROR_CODE_INVALID_TOKEN = "ERR-TK041"
+var ERROR_CODE_UNAUTHORIZED = "ERR-AU041"
+var ERROR_CODE_FORBIDDEN = "ERR-FB043"
+
+// Codes of the problems the API answers with, see problems.New
+var ERROR_CODE_VALIDATION = "ERR-VL040"
+var ERROR_CODE_NOT_FOUND = "ERR-NF044"
+var ERROR_CODE_CONFLICT = "ERR-CF049"
+var ERROR_CODE_ORPHANED_TASKS = "ERR-OT049"
+var ERROR_CODE_VERSION_CONFLICT = "ERR-VC412"
+var ERROR_CODE_UNSUPPORTED_MEDIA_TYPE = "ERR-MT415"
+var ERROR_CODE_INVALID_REFERENCE = "ERR-RF422"
+var ERROR_CODE_TRANSITION_BLOCKED = "ERR-GD422"
+var ERROR_CODE_TRANSITION_NOT_ALLOWED = "ERR-TR422"
+var ERROR_CODE_DEPENDENCY_CYCLE = "ERR-CY422"
+var ERROR_CODE_TASK_BLOCKED = "ERR-BK422"
+var ERROR_CODE_INVALID_MIGRATION = "ERR-MG422"
+var ERROR_CODE_INVALID_PATCH = "ERR-PT422"
+var ERROR_CODE_PRECONDITION_REQUIRED = "ERR-PR428"
+var ERROR_CODE_CLIENT_CLOSED_REQUEST = "ERR-CC499"
+var ERROR_CODE_INTERNAL = "ERR-IN500"
+var ERROR_CODE_TIMEOUT = "ERR-TO504"
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api"
+	"todo-api/internal/infrastructure/api/problems"
+	"todo-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errWrongCredentials error = problems.NewRequestError(http.StatusUnauthorized, api.ERROR_CODE_WRONG_CREDENTIALS, "wrong username or password")
+	errInvalidToken     error = problems.NewRequestError(http.StatusUnauthorized, api.ERROR_CODE_INVALID_TOKEN, "refresh token is invalid, expired or revoked")
+)
+
+type AuthHandler struct {
+	users  domain.UserRepository
+	tokens domain.RefreshTokenRepository
+	config utils.JWTConfig
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func NewAuthHandler(users domain.UserRepository, tokens domain.RefreshTokenRepository, config utils.JWTConfig) *AuthHandler {
+	return &AuthHandler{
+		users:  users,
+		tokens: tokens,
+		config: config,
+	}
+}
+
+// Login exchanges a username and password for an access and refresh token pair
+// @POST /auth/login
+func (h *AuthHandler) Login(c *gin.Context) {
+	var credentials loginRequest
+
+	if err := c.ShouldBindJSON(&credentials); err != nil || credentials.Username == "" || credentials.Password == "" {
+		abortWithError(c, domain.NewValidationError("", "username and password are required"))
+		return
+	}
+
+	// The same error is returned for unknown users, inactive users and wrong passwords
+	// so the endpoint can't be used to enumerate accounts
+	user, err := h.users.GetByUsername(c.Request.Context(), credentials.Username)
+	if err != nil || !user.Active || !user.CheckPassword(credentials.Password) {
+		abortWithError(c, errWrongCredentials)
+		return
+	}
+
+	response, refreshToken, err := h.signTokens(user)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if _, err := h.tokens.Create(c.Request.Context(), refreshToken); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, response)
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new pair is issued
+// carrying the user's current role. Presenting an already revoked token, or the same token twice at once,
+// revokes every refresh token of its user.
+// @POST /auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var body refreshRequest
+
+	if err := c.ShouldBindJSON(&body); err != nil || body.RefreshToken == "" {
+		abortWithError(c, domain.NewValidationError("refresh_token", "refresh_token is required"))
+		return
+	}
+
+	claims, err := utils.ParseToken(body.RefreshToken, h.config.Secret)
+	if err != nil || claims.Type != utils.RefreshTokenType {
+		h.rejectToken(c)
+		return
+	}
+
+	stored, err := h.tokens.GetByID(c.Request.Context(), claims.TokenID)
+	if err != nil {
+		h.rejectToken(c)
+		return
+	}
+
+	if stored.Revoked {
+		h.rejectReusedToken(c, stored.UserID)
+		return
+	}
+
+	if !stored.IsUsable() {
+		h.rejectToken(c)
+		return
+	}
+
+	user, err := h.users.GetByID(c.Request.Context(), stored.UserID)
+	if err != nil || !user.Active {
+		h.rejectToken(c)
+		return
+	}
+
+	response, refreshToken, err := h.signTokens(user)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// The new pair is only handed out once the presented token is claimed, so a concurrent
+	// refresh with the same token gets nothing
+	if err := h.tokens.Rotate(c.Request.Context(), stored.ID, refreshToken); err != nil {
+		if errors.Is(err, domain.ErrTokenReused) {
+			h.rejectReusedToken(c, stored.UserID)
+			return
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, response)
+}
+
+// Logout revokes the presented refresh token
+// @POST /auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var body refreshRequest
+
+	if err := c.ShouldBindJSON(&body); err != nil || body.RefreshToken == "" {
+		abortWithError(c, domain.NewValidationError("refresh_token", "refresh_token is required"))
+		return
+	}
+
+	claims, err := utils.ParseToken(body.RefreshToken, h.config.Secret)
+	if errors.Is(err, utils.ErrExpiredToken) {
+		// An expired refresh token can't be used anymore, there is nothing left to revoke
+		addSuccessHeaders(c)
+		addValidationHeaders(c)
+		c.JSON(http.StatusNoContent, nil)
+		return
+	}
+	if err != nil || claims.Type != utils.RefreshTokenType {
+		h.rejectToken(c)
+		return
+	}
+
+	if err := h.tokens.Revoke(c.Request.Context(), claims.TokenID, ""); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// signTokens signs a new access/refresh pair and returns the refresh token to store
+func (h *AuthHandler) signTokens(user entities.User) (gin.H, entities.RefreshToken, error) {
+	now := time.Now()
+	subject := strconv.FormatInt(user.ID, 10)
+
+	accessToken, err := utils.GenerateToken(utils.TokenClaims{
+		Subject:   subject,
+		TokenID:   uuid.New().String(),
+		Type:      utils.AccessTokenType,
+		Role:      string(user.Role),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(h.config.AccessTTL).Unix(),
+	}, h.config.Secret)
+	if err != nil {
+		return nil, entities.RefreshToken{}, err
+	}
+
+	refreshToken := entities.NewRefreshToken(uuid.New().String(), user.ID, now.Add(h.config.RefreshTTL))
+	signedRefreshToken, err := utils.GenerateToken(utils.TokenClaims{
+		Subject:   subject,
+		TokenID:   refreshToken.ID,
+		Type:      utils.RefreshTokenType,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: refreshToken.ExpiresAt.Unix(),
+	}, h.config.Secret)
+	if err != nil {
+		return nil, entities.RefreshToken{}, err
+	}
+
+	return gin.H{
+		"access_token":  accessToken,
+		"refresh_token": signedRefreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    int64(h.config.AccessTTL.Seconds()),
+	}, refreshToken, nil
+}
+
+func (h *AuthHandler) rejectToken(c *gin.Context) {
+	abortWithError(c, errInvalidToken)
+}
+
+// rejectReusedToken ends every session of the user: token reuse means the token was stolen or replayed
+func (h *AuthHandler) rejectReusedToken(c *gin.Context, userID int64) {
+	if err := h.tokens.RevokeAllForUser(c.Request.Context(), userID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	h.rejectToken(c)
+}
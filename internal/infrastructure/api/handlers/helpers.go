@@ -1,6 +1,15 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+	"todo-api/internal/infrastructure/api/problems"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,3 +42,118 @@ func addValidationHeaders(c *gin.Context) {
 	// Content security policy for JSON responses
 	c.Header("Content-Type", "application/json; charset=utf-8")
 }
+
+// Helper function to read the ID of the caller stored by the authentication middleware
+func authenticatedUserID(c *gin.Context) (int64, bool) {
+	userID := c.GetInt64("user_id")
+	return userID, userID != 0
+}
+
+// Helper function to reject requests that reached a handler without an authenticated caller
+func abortUnauthenticated(c *gin.Context) {
+	abortWithError(c, problems.ErrUnauthenticated)
+}
+
+// Helper function to abort a request with the error rejecting it. The error handler middleware answers it
+// with the problem the error maps to.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// Helper function to read the IDs of the caller's teams stored by the team scope middleware
+func callerTeamIDs(c *gin.Context) []int64 {
+	value, _ := c.Get("team_ids")
+	teamIDs, _ := value.([]int64)
+	return teamIDs
+}
+
+// Helper function to resolve the team a new resource is created in.
+// A zero team defaults to the caller's only team, any other team must be one of the caller's.
+func resolveCallerTeam(c *gin.Context, requested int64) (int64, bool) {
+	teamIDs := callerTeamIDs(c)
+	if requested == 0 {
+		if len(teamIDs) == 1 {
+			return teamIDs[0], true
+		}
+		return 0, false
+	}
+
+	for _, teamID := range teamIDs {
+		if teamID == requested {
+			return requested, true
+		}
+	}
+	return 0, false
+}
+
+// Helper function to reject requests targeting a team the caller is not a member of
+func abortForbiddenTeam(c *gin.Context) {
+	abortWithError(c, &domain.ForbiddenError{Message: "caller is not a member of the requested team"})
+}
+
+// Helper function to check whether the caller has the admin role
+func isAdmin(c *gin.Context) bool {
+	value, _ := c.Get("user_role")
+	role, _ := value.(entities.Role)
+	return role == entities.RoleAdmin
+}
+
+// Helper function to set the ETag of a versioned resource, its version as a strong entity tag
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// Helper function to read the version a write is based on from the If-Match header, "*" matches any version
+// and is read as 0. Writes without the header are answered with 428, headers holding anything but one of our
+// ETags with 400; ok is false when the request was answered.
+func ifMatchVersion(c *gin.Context) (version int64, ok bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		abortWithError(c, problems.ErrIfMatchRequired)
+		return 0, false
+	}
+	if header == "*" {
+		return 0, true
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err == nil {
+		version, err = strconv.ParseInt(unquoted, 10, 64)
+	}
+	if err != nil || version <= 0 {
+		abortWithError(c, domain.NewValidationError("If-Match", "If-Match must be the ETag of the resource or *"))
+		return 0, false
+	}
+	return version, true
+}
+
+// Helper function to answer a write based on a stale version with 412, along with the current representation
+// of the resource and its ETag
+func abortVersionConflict(c *gin.Context, current interface{}, version int64) {
+	addErrorHeaders(c)
+	setETag(c, version)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.JSON(http.StatusPreconditionFailed, current)
+}
+
+// Helper function to bind and validate the JSON body of a request. Bodies that cannot be read or break
+// the rules of the request are answered with 400; ok is false when the request was answered.
+func bindRequest(c *gin.Context, request interface{}) (ok bool) {
+	if err := c.ShouldBindJSON(request); err != nil {
+		abortWithError(c, invalidBody(err))
+		return false
+	}
+	return true
+}
+
+// Helper function to turn the error of reading a request body into a validation error, listing the rejected
+// fields when they are known
+func invalidBody(err error) error {
+	err = dto.NewValidationError(err)
+	var invalid *dto.ValidationError
+	if errors.As(err, &invalid) {
+		return invalid
+	}
+	return domain.NewValidationError("", err.Error())
+}
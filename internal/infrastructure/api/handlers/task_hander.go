@@ -1,47 +1,73 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"todo-api/internal/domain"
-	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+	"todo-api/internal/infrastructure/api/problems"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TaskHandler struct {
-	repository domain.TaskRepository
+	service *domain.TaskService
 }
 
-func NewTaskHandler(repository domain.TaskRepository) *TaskHandler {
+func NewTaskHandler(service *domain.TaskService) *TaskHandler {
 	return &TaskHandler{
-		repository: repository,
+		service: service,
 	}
 }
 
+// abortTaskWrite answers a write of the task id the service rejected. A stale version is answered with 412
+// and the task as it is now, the other errors by the problem they map to.
+func (h *TaskHandler) abortTaskWrite(c *gin.Context, id int64, err error) {
+	if errors.Is(err, domain.ErrVersionConflict) {
+		if current, getErr := h.service.Get(c.Request.Context(), id, domain.TaskExpansion{}, callerTeamIDs(c)); getErr == nil {
+			abortVersionConflict(c, dto.NewTaskResponse(current), current.Version)
+			return
+		}
+	}
+	abortWithError(c, err)
+}
+
 // CreateTask creates a new task
 // @POST /todo
 func (h *TaskHandler) CreateTask(c *gin.Context) {
-	var task entities.Task
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		abortUnauthenticated(c)
+		return
+	}
+
+	var request dto.CreateTaskRequest
+	if !bindRequest(c, &request) {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&task); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	task := request.Task()
+	teamID, ok := resolveCallerTeam(c, task.TeamID)
+	if !ok {
+		abortForbiddenTeam(c)
 		return
 	}
+	task.TeamID = teamID
 
-	createdTask, err := h.repository.Create(task)
+	createdTask, err := h.service.Create(c.Request.Context(), task, userID, callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusCreated, createdTask)
+	setETag(c, createdTask.Version)
+	c.JSON(http.StatusCreated, dto.NewTaskResponse(createdTask))
 }
 
 // GetTask retrieves a task by ID
@@ -49,90 +75,472 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
 		return
 	}
 
-	task, err := h.repository.GetByID(id)
+	expansion, err := domain.ParseTaskExpansion(c.Query("expand"))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		abortWithError(c, err)
+		return
+	}
+
+	task, err := h.service.Get(c.Request.Context(), id, expansion, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, task)
+	setETag(c, task.Version)
+	c.JSON(http.StatusOK, dto.NewTaskResponse(task))
 }
 
-// GetAllTasks retrieves all tasks
+// GetAllTasks retrieves a page of tasks, filtered and sorted by the query parameters
 // @GET /todo
 func (h *TaskHandler) GetAllTasks(c *gin.Context) {
-	tasks, err := h.repository.GetAll()
+	query, err := parseTaskQuery(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	page, err := h.service.Find(c.Request.Context(), query, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	links := gin.H{"self": pageLink(c, query.Offset)}
+	if query.HasNext(page.Total) {
+		links["next"] = pageLink(c, query.Offset+query.Limit)
+	}
+	if query.Offset > 0 {
+		links["prev"] = pageLink(c, max(query.Offset-query.Limit, 0))
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, gin.H{
+		"data":   dto.NewTaskResponses(page.Tasks),
+		"total":  page.Total,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+		"links":  links,
+	})
+}
+
+// SearchTasks retrieves the tasks whose title or description match q, best match first
+// @GET /todo/search
+func (h *TaskHandler) SearchTasks(c *gin.Context) {
+	query := c.Query("q")
+	if len(domain.SearchTerms(query)) == 0 {
+		abortWithError(c, domain.NewValidationError("q", "q must contain at least one word"))
+		return
+	}
+
+	limit, err := intParam(c, "limit", domain.DefaultTaskPageSize)
+	if err != nil || limit < 1 || limit > domain.MaxTaskPageSize {
+		abortWithError(c, domain.NewValidationError("limit", fmt.Sprintf("limit must be between 1 and %d", domain.MaxTaskPageSize)))
+		return
+	}
+
+	results, err := h.service.Search(c.Request.Context(), query, limit, callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
+	data := make([]gin.H, len(results))
+	for i, result := range results {
+		data[i] = gin.H{
+			"task":  dto.NewTaskResponse(result.Task),
+			"score": result.Score,
+			"highlights": gin.H{
+				"title":       result.TitleSnippet,
+				"description": result.Snippet,
+			},
+		}
+	}
+
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, tasks)
+	c.JSON(http.StatusOK, gin.H{"data": data, "query": query})
 }
 
-// UpdateTask updates a task
+// UpdateTask updates a task. If-Match must hold the ETag of the task, a stale one is answered with 412
+// and the task as it is now.
 // @PUT /todo/:id
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	var task entities.Task
-	if err := c.ShouldBindJSON(&task); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request dto.UpdateTaskRequest
+	if !bindRequest(c, &request) {
 		return
 	}
 
-	task.ID = id
-	updatedTask, err := h.repository.Update(task)
+	// Moving a task to another team requires membership of that team too
+	task := request.Task()
+	if task.TeamID != 0 {
+		if _, ok := resolveCallerTeam(c, task.TeamID); !ok {
+			abortForbiddenTeam(c)
+			return
+		}
+	}
+
+	task.Version = version
+	updatedTask, err := h.service.Update(c.Request.Context(), id, task, c.GetInt64("user_id"), callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortTaskWrite(c, id, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, updatedTask)
+	setETag(c, updatedTask.Version)
+	c.JSON(http.StatusOK, dto.NewTaskResponse(updatedTask))
 }
 
-// DeleteTask deletes a task
+// PatchTask changes only the fields of a task a patch names, as a JSON Merge Patch with
+// Content-Type application/merge-patch+json or a JSON Patch with application/json-patch+json.
+// The patched task is stored the same way as by UpdateTask.
+// @PATCH /todo/:id
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	mediaType, ok := patchMediaType(c.ContentType())
+	if !ok {
+		abortWithError(c, problems.NewRequestError(http.StatusUnsupportedMediaType, api.ERROR_CODE_UNSUPPORTED_MEDIA_TYPE,
+			"Content-Type must be "+mergePatchContentType+" or "+jsonPatchContentType))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		abortWithError(c, invalidBody(err))
+		return
+	}
+
+	current, err := h.service.Get(c.Request.Context(), id, domain.TaskExpansion{}, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	request, err := patchTask(current, mediaType, patch)
+	if errors.Is(err, errMalformedPatch) {
+		abortWithError(c, invalidBody(err))
+		return
+	}
+	if err != nil {
+		abortWithError(c, &problems.RequestError{Status: http.StatusUnprocessableEntity, Code: api.ERROR_CODE_INVALID_PATCH, Err: err})
+		return
+	}
+
+	// Moving a task to another team requires membership of that team too
+	task := request.Task()
+	if task.TeamID != current.TeamID {
+		if _, ok := resolveCallerTeam(c, task.TeamID); !ok {
+			abortForbiddenTeam(c)
+			return
+		}
+	}
+
+	task.Version = version
+	updatedTask, err := h.service.Update(c.Request.Context(), id, task, c.GetInt64("user_id"), callerTeamIDs(c))
+	if err != nil {
+		h.abortTaskWrite(c, id, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	setETag(c, updatedTask.Version)
+	c.JSON(http.StatusOK, dto.NewTaskResponse(updatedTask))
+}
+
+// TransitionTask moves a task to another status of its workflow, then runs the workflow's actions for the move.
+// Completed is derived from the new status: a terminal status of the workflow's graph completes a task,
+// or the last status of the order for workflows without a graph.
+// Failed actions do not undo the transition, they are listed in the response.
+// @POST /todo/:id/transition
+func (h *TaskHandler) TransitionTask(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	var request dto.TransitionRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	updatedTask, failures, err := h.service.Transition(c.Request.Context(), id, request.StatusID, c.GetInt64("user_id"), callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	setETag(c, updatedTask.Version)
+	c.JSON(http.StatusOK, gin.H{"task": dto.NewTaskResponse(updatedTask), "action_failures": dto.NewActionFailureResponses(failures)})
+}
+
+// GetSubtasks lists the direct subtasks of a task, or all of them as a tree with recursive=true,
+// along with the share of the task's descendants that are completed
+// @GET /todo/:id/subtasks
+func (h *TaskHandler) GetSubtasks(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	recursive, err := strconv.ParseBool(c.DefaultQuery("recursive", "false"))
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("recursive", "recursive must be true or false"))
+		return
+	}
+
+	tree, err := h.service.Subtasks(c.Request.Context(), id, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	subtasks := make([]interface{}, len(tree.Subtasks))
+	for i, subtree := range tree.Subtasks {
+		if recursive {
+			subtasks[i] = subtaskNode(subtree)
+		} else {
+			subtasks[i] = dto.NewTaskResponse(subtree.Task)
+		}
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, gin.H{
+		"task_id":  tree.Task.ID,
+		"progress": subtaskProgress(tree.Progress),
+		"subtasks": subtasks,
+	})
+}
+
+// subtaskNode describes a task of a subtask tree along with its own subtasks
+func subtaskNode(tree domain.SubtaskTree) gin.H {
+	subtasks := make([]gin.H, len(tree.Subtasks))
+	for i, subtree := range tree.Subtasks {
+		subtasks[i] = subtaskNode(subtree)
+	}
+	return gin.H{
+		"task":     dto.NewTaskResponse(tree.Task),
+		"progress": subtaskProgress(tree.Progress),
+		"subtasks": subtasks,
+	}
+}
+
+func subtaskProgress(progress domain.SubtaskProgress) gin.H {
+	return gin.H{
+		"completed": progress.Completed,
+		"total":     progress.Total,
+		"percent":   progress.Percent,
+	}
+}
+
+// ReparentTask moves a task under another task, or to the top level. Moving a task under one of its own subtasks
+// is rejected with 422. If-Match is handled as by UpdateTask.
+// @PUT /todo/:id/parent
+func (h *TaskHandler) ReparentTask(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var request dto.ReparentRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	var parentID int64
+	if request.ParentID != nil {
+		parentID = *request.ParentID
+	}
+
+	updatedTask, err := h.service.Reparent(c.Request.Context(), id, parentID, version, c.GetInt64("user_id"), callerTeamIDs(c))
+	if err != nil {
+		h.abortTaskWrite(c, id, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	setETag(c, updatedTask.Version)
+	c.JSON(http.StatusOK, dto.NewTaskResponse(updatedTask))
+}
+
+// GetTaskHistory lists every field change of a task with its previous and new value, oldest first
+// @GET /todo/:id/history
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	events, err := h.service.History(c.Request.Context(), id, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewTaskEventResponses(events))
+}
+
+// GetActionFailures lists the workflow actions that failed after transitions of a task, latest first
+// @GET /todo/:id/action-failures
+func (h *TaskHandler) GetActionFailures(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	failures, err := h.service.ActionFailures(c.Request.Context(), id, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewActionFailureResponses(failures))
+}
+
+// GetDependencies lists the tasks blocking a task and the tasks it blocks
+// @GET /todo/:id/dependencies
+func (h *TaskHandler) GetDependencies(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	blockers, blocked, err := h.service.Dependencies(c.Request.Context(), id, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, gin.H{"blocked_by": dto.NewTaskResponses(blockers), "blocks": dto.NewTaskResponses(blocked)})
+}
+
+// AddDependency makes a task wait for another one: the task cannot be completed while blocked_by is open.
+// Dependencies that would let a task block itself are rejected with 422, existing ones with 409.
+// @POST /todo/:id/dependencies
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	var request dto.DependencyRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	dependency, err := h.service.AddDependency(c.Request.Context(), id, request.BlockedBy, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusCreated, dto.NewTaskDependencyResponse(dependency))
+}
+
+// RemoveDependency lets a task be completed regardless of one of its blockers
+// @DELETE /todo/:id/dependencies/:blockerID
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	blockerID, err := strconv.ParseInt(c.Param("blockerID"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("blockerID", "Invalid blocker ID"))
+		return
+	}
+
+	if err := h.service.RemoveDependency(c.Request.Context(), id, blockerID, callerTeamIDs(c)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// DeleteTask deletes a task. The subtasks query parameter decides what happens to its subtasks:
+// cascade (the default) deletes them too, promote moves them to the task's parent, reject keeps the task with 409.
+// If-Match is handled as by UpdateTask.
 // @DELETE /todo/:id
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid task ID"))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	policy, err := domain.ParseSubtaskPolicy(c.Query("subtasks"))
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
-	err = h.repository.Remove(id)
+	err = h.service.Remove(c.Request.Context(), id, version, policy, c.GetInt64("user_id"), callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortTaskWrite(c, id, err)
 		return
 	}
 
@@ -146,23 +554,19 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 func (h *TaskHandler) GetTasksByResponsible(c *gin.Context) {
 	userID, err := strconv.ParseInt(c.Param("userID"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		abortWithError(c, domain.NewValidationError("userID", "Invalid user ID"))
 		return
 	}
 
-	tasks, err := h.repository.GetAllByResponsible(userID)
+	tasks, err := h.service.GetAllByResponsible(c.Request.Context(), userID, callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, tasks)
+	c.JSON(http.StatusOK, dto.NewTaskResponses(tasks))
 }
 
 // GetTasksByAuthor retrieves all tasks created by a user
@@ -170,37 +574,107 @@ func (h *TaskHandler) GetTasksByResponsible(c *gin.Context) {
 func (h *TaskHandler) GetTasksByAuthor(c *gin.Context) {
 	userID, err := strconv.ParseInt(c.Param("userID"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		abortWithError(c, domain.NewValidationError("userID", "Invalid user ID"))
 		return
 	}
 
-	tasks, err := h.repository.GetAllByAuthor(userID)
+	tasks, err := h.service.GetAllByAuthor(c.Request.Context(), userID, callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, tasks)
+	c.JSON(http.StatusOK, dto.NewTaskResponses(tasks))
+}
+
+// GetTasksByStatus retrieves all tasks currently in a status
+// @GET /todo/status/:statusID
+func (h *TaskHandler) GetTasksByStatus(c *gin.Context) {
+	statusID, err := strconv.ParseInt(c.Param("statusID"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("statusID", "Invalid status ID"))
+		return
+	}
+
+	tasks, err := h.service.GetAllByStatus(c.Request.Context(), statusID, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewTaskResponses(tasks))
+}
+
+// GetBoard retrieves the tasks of a workflow grouped in one column per status, in workflow order.
+// Tasks whose status is not part of the workflow are listed apart so they are never hidden.
+// @GET /todo/board/:workflowID
+func (h *TaskHandler) GetBoard(c *gin.Context) {
+	workflowID, err := strconv.ParseInt(c.Param("workflowID"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("workflowID", "Invalid workflow ID"))
+		return
+	}
+
+	board, err := h.service.Board(c.Request.Context(), workflowID, callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	columns := make([]gin.H, len(board.Columns))
+	for i, column := range board.Columns {
+		columns[i] = gin.H{
+			"position": column.Position,
+			"status":   dto.NewTaskStatusResponse(column.Status),
+			"count":    len(column.Tasks),
+			"tasks":    dto.NewTaskResponses(column.Tasks),
+		}
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, gin.H{
+		"workflow": gin.H{"id": board.Workflow.ID, "name": board.Workflow.Name},
+		"columns":  columns,
+		"unmapped": gin.H{"count": len(board.Unmapped), "tasks": dto.NewTaskResponses(board.Unmapped)},
+		"total":    board.Total,
+	})
 }
 
 // GetOverdueTasks retrieves all overdue tasks
 // @GET /todo/overdue
 func (h *TaskHandler) GetOverdueTasks(c *gin.Context) {
-	tasks, err := h.repository.GetAllOverdue()
+	tasks, err := h.service.Overdue(c.Request.Context(), callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, tasks)
+	c.JSON(http.StatusOK, dto.NewTaskResponses(tasks))
+}
+
+// GetPlan lists the open tasks in an order that respects their dependencies: every task comes after the tasks
+// blocking it. Tasks of the same stage can be worked on together, they are ordered by deadline.
+// @GET /todo/plan
+func (h *TaskHandler) GetPlan(c *gin.Context) {
+	plan, err := h.service.Plan(c.Request.Context(), callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	data := make([]gin.H, len(plan))
+	for i, planned := range plan {
+		data[i] = gin.H{"task": dto.NewTaskResponse(planned.Task), "stage": planned.Stage, "blocked_by": planned.BlockedBy}
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, gin.H{"data": data})
 }
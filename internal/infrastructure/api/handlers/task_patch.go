@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// errMalformedPatch is wrapped by the errors of patches that cannot be read, as opposed to patches
+// that are well formed but cannot be applied to the task
+var errMalformedPatch = errors.New("malformed patch")
+
+// patchMediaType returns the patch format of a Content-Type header, or false for a format that is not supported
+func patchMediaType(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != mergePatchContentType && mediaType != jsonPatchContentType) {
+		return "", false
+	}
+	return mediaType, true
+}
+
+// patchTask applies a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902), as told by mediaType,
+// to the update request that would store the task as it is. The patched request is validated as the
+// body of a PUT would be, a *dto.ValidationError lists the fields it breaks.
+func patchTask(task entities.Task, mediaType string, patch []byte) (dto.UpdateTaskRequest, error) {
+	var request dto.UpdateTaskRequest
+	document, err := json.Marshal(dto.NewUpdateTaskRequest(task))
+	if err != nil {
+		return request, err
+	}
+
+	if mediaType == jsonPatchContentType {
+		document, err = applyJSONPatch(document, patch)
+	} else {
+		document, err = applyMergePatch(document, patch)
+	}
+	if err != nil {
+		return request, err
+	}
+
+	if err := json.Unmarshal(document, &request); err != nil {
+		return request, dto.NewValidationError(err)
+	}
+	return request, dto.Validate(request)
+}
+
+// applyMergePatch merges patch into document: members of the patch replace those of the document,
+// objects are merged recursively and null removes a member
+func applyMergePatch(document []byte, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := decodeJSON(document, &target); err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(patch, &changes); err != nil {
+		return nil, fmt.Errorf("%w: %v", errMalformedPatch, err)
+	}
+	if _, ok := changes.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("%w: a merge patch of a task must be an object", errMalformedPatch)
+	}
+	return json.Marshal(mergeValues(target, changes))
+}
+
+func mergeValues(target interface{}, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{})
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(object, key)
+		} else {
+			object[key] = mergeValues(object[key], value)
+		}
+	}
+	return object
+}
+
+// jsonPatchOperation is one operation of a JSON Patch
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch applies the operations of patch to document in order. The patch fails as a whole
+// when one of its operations does.
+func applyJSONPatch(document []byte, patch []byte) ([]byte, error) {
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: a JSON patch must be an array of operations: %v", errMalformedPatch, err)
+	}
+
+	var target interface{}
+	if err := decodeJSON(document, &target); err != nil {
+		return nil, err
+	}
+
+	for i, operation := range operations {
+		var err error
+		if target, err = operation.apply(target); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, operation.Op, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func (self jsonPatchOperation) apply(target interface{}) (interface{}, error) {
+	if self.Path == nil {
+		return nil, fmt.Errorf("%w: path is required", errMalformedPatch)
+	}
+	path, err := parsePointer(*self.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch self.Op {
+	case "add", "replace", "test":
+		if self.Value == nil {
+			return nil, fmt.Errorf("%w: value is required", errMalformedPatch)
+		}
+		if err := decodeJSON(self.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", errMalformedPatch, err)
+		}
+	case "move", "copy":
+		if self.From == nil {
+			return nil, fmt.Errorf("%w: from is required", errMalformedPatch)
+		}
+		from, err := parsePointer(*self.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = valueAt(target, from); err != nil {
+			return nil, err
+		}
+		if self.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, fmt.Errorf("a value cannot be moved into itself")
+			}
+			if target, err = removeAt(target, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = copyValue(value)
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", errMalformedPatch, self.Op)
+	}
+
+	switch self.Op {
+	case "remove":
+		return removeAt(target, path)
+	case "replace":
+		if len(path) == 0 {
+			return value, nil
+		}
+		if _, err := valueAt(target, path); err != nil {
+			return nil, err
+		}
+		if target, err = removeAt(target, path); err != nil {
+			return nil, err
+		}
+		return addAt(target, path, value)
+	case "test":
+		current, err := valueAt(target, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalValues(current, value) {
+			return nil, fmt.Errorf("the value at %s is not the tested one", *self.Path)
+		}
+		return target, nil
+	default:
+		return addAt(target, path, value)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %q is not a JSON pointer", errMalformedPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func valueAt(target interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch container := target.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("no value at /%s", strings.Join(path, "/"))
+			}
+			target = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			target = container[index]
+		default:
+			return nil, fmt.Errorf("no value at /%s", strings.Join(path, "/"))
+		}
+	}
+	return target, nil
+}
+
+// addAt adds value at path, replacing a member of an object or inserting into an array
+func addAt(target interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return changeParent(target, path, func(parent interface{}, token string) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			index := len(container)
+			if token != "-" {
+				var err error
+				if index, err = arrayIndex(token, len(container)); err != nil {
+					return nil, err
+				}
+			}
+			return append(container[:index], append([]interface{}{value}, container[index:]...)...), nil
+		default:
+			return nil, fmt.Errorf("no object or array to add /%s to", strings.Join(path, "/"))
+		}
+	})
+}
+
+func removeAt(target interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("the whole task cannot be removed")
+	}
+	return changeParent(target, path, func(parent interface{}, token string) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, fmt.Errorf("no value at /%s", strings.Join(path, "/"))
+			}
+			delete(container, token)
+			return container, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("no value at /%s", strings.Join(path, "/"))
+		}
+	})
+}
+
+// changeParent walks down to the container holding the last token of path and replaces it by what change returns,
+// since changing an array can move it
+func changeParent(target interface{}, path []string, change func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return change(target, path[0])
+	}
+
+	child, err := valueAt(target, path[:1])
+	if err != nil {
+		return nil, fmt.Errorf("no value at /%s", strings.Join(path, "/"))
+	}
+	if child, err = changeParent(child, path[1:], change); err != nil {
+		return nil, err
+	}
+
+	switch container := target.(type) {
+	case map[string]interface{}:
+		container[path[0]] = child
+	case []interface{}:
+		index, _ := arrayIndex(path[0], len(container)-1)
+		container[index] = child
+	}
+	return target, nil
+}
+
+// arrayIndex parses an array index of a JSON pointer, which cannot be above last
+func arrayIndex(token string, last int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%q is not an array index", token)
+	}
+	if index > last {
+		return 0, fmt.Errorf("array index %d is out of range", index)
+	}
+	return index, nil
+}
+
+func isPrefix(prefix []string, path []string) bool {
+	return len(prefix) <= len(path) && reflect.DeepEqual(prefix, path[:len(prefix)])
+}
+
+func copyValue(value interface{}) interface{} {
+	switch container := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(container))
+		for key, member := range container {
+			copied[key] = copyValue(member)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(container))
+		for i, element := range container {
+			copied[i] = copyValue(element)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// equalValues compares two JSON values, numbers by their value rather than by how they are written
+func equalValues(a interface{}, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !equalValues(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalValues(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as they are written so IDs do not go through float64
+func decodeJSON(data []byte, value *interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(value); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after the JSON value")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseTaskQuery reads the pagination, sorting, filter and expand parameters of GET /todo, invalid ones fail with
+// a *domain.ValidationError.
+// sort takes a field name, prefixed with "-" for descending order.
+func parseTaskQuery(c *gin.Context) (domain.TaskQuery, error) {
+	var query domain.TaskQuery
+
+	var err error
+	if query.Limit, err = intParam(c, "limit", domain.DefaultTaskPageSize); err != nil {
+		return query, err
+	}
+	if query.Limit < 1 || query.Limit > domain.MaxTaskPageSize {
+		return query, domain.NewValidationError("limit", fmt.Sprintf("limit must be between 1 and %d", domain.MaxTaskPageSize))
+	}
+	if query.Offset, err = intParam(c, "offset", 0); err != nil {
+		return query, err
+	}
+	if query.Offset < 0 {
+		return query, domain.NewValidationError("offset", "offset must not be negative")
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		query.Sort, query.Descending = strings.TrimPrefix(sort, "-"), strings.HasPrefix(sort, "-")
+		if _, ok := domain.TaskSortFields[query.Sort]; !ok {
+			return query, domain.NewValidationError("sort", "sort must be one of deadline, created_at, updated_at or title")
+		}
+	}
+
+	filters := map[string]*int64{
+		"status_id":      &query.StatusID,
+		"type_id":        &query.TypeID,
+		"workflow_id":    &query.WorkflowID,
+		"responsible_id": &query.ResponsibleID,
+		"author_id":      &query.AuthorID,
+	}
+	for name, target := range filters {
+		if value := c.Query(name); value != "" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return query, domain.NewValidationError(name, name+" must be a positive integer")
+			}
+			*target = id
+		}
+	}
+
+	if value := c.Query("completed"); value != "" {
+		completed, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, domain.NewValidationError("completed", "completed must be true or false")
+		}
+		query.Completed = &completed
+	}
+
+	if query.DeadlineFrom, err = dateParam(c, "deadline_from"); err != nil {
+		return query, err
+	}
+	if query.DeadlineTo, err = dateParam(c, "deadline_to"); err != nil {
+		return query, err
+	}
+	// A date alone includes the whole day, up to its last second as deadlines are stored to the second
+	if _, err := time.Parse(time.DateOnly, c.Query("deadline_to")); err == nil {
+		endOfDay := query.DeadlineTo.AddDate(0, 0, 1).Add(-time.Second)
+		query.DeadlineTo = &endOfDay
+	}
+
+	if query.Expand, err = domain.ParseTaskExpansion(c.Query("expand")); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+// intParam reads an optional integer query parameter
+func intParam(c *gin.Context, name string, fallback int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, domain.NewValidationError(name, name+" must be an integer")
+	}
+	return number, nil
+}
+
+// dateParam reads an optional date query parameter, in any format accepted for task deadlines
+func dateParam(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	var date entities.DateTime
+	if err := date.UnmarshalJSON([]byte(value)); err != nil {
+		return nil, domain.NewValidationError(name, name+" must be a date or an RFC3339 timestamp")
+	}
+	return &date.Time, nil
+}
+
+// pageLink returns the URL of the current request with another offset, keeping every other parameter
+func pageLink(c *gin.Context, offset int) string {
+	values := url.Values{}
+	for key, value := range c.Request.URL.Query() {
+		values[key] = value
+	}
+	values.Set("offset", strconv.Itoa(offset))
+
+	link := url.URL{Path: c.Request.URL.Path, RawQuery: values.Encode()}
+	return link.String()
+}
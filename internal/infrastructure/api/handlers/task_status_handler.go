@@ -1,10 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"todo-api/internal/domain"
-	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,26 +23,21 @@ func NewTaskStatusHandler(repository domain.TaskStatusRepository) *TaskStatusHan
 // CreateTaskStatus creates a new task status
 // @POST /task-statuses
 func (h *TaskStatusHandler) CreateTaskStatus(c *gin.Context) {
-	var status entities.TaskStatus
-
-	if err := c.ShouldBindJSON(&status); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request dto.TaskStatusRequest
+	if !bindRequest(c, &request) {
 		return
 	}
 
-	createdStatus, err := h.repository.Create(status)
+	createdStatus, err := h.repository.Create(c.Request.Context(), request.TaskStatus())
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusCreated, createdStatus)
+	setETag(c, createdStatus.Version)
+	c.JSON(http.StatusCreated, dto.NewTaskStatusResponse(createdStatus))
 }
 
 // GetTaskStatus retrieves a task status by ID
@@ -49,39 +45,34 @@ func (h *TaskStatusHandler) CreateTaskStatus(c *gin.Context) {
 func (h *TaskStatusHandler) GetTaskStatus(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid status ID"))
 		return
 	}
 
-	status, err := h.repository.GetByID(id)
+	status, err := h.repository.GetByID(c.Request.Context(), id)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, status)
+	setETag(c, status.Version)
+	c.JSON(http.StatusOK, dto.NewTaskStatusResponse(status))
 }
 
 // GetAllTaskStatuses retrieves all task statuses
 // @GET /task-statuses
 func (h *TaskStatusHandler) GetAllTaskStatuses(c *gin.Context) {
-	statuses, err := h.repository.GetAll()
+	statuses, err := h.repository.GetAll(c.Request.Context())
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, statuses)
+	c.JSON(http.StatusOK, dto.NewTaskStatusResponses(statuses))
 }
 
 // UpdateTaskStatus updates a task status
@@ -89,32 +80,33 @@ func (h *TaskStatusHandler) GetAllTaskStatuses(c *gin.Context) {
 func (h *TaskStatusHandler) UpdateTaskStatus(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid status ID"))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	var status entities.TaskStatus
-	if err := c.ShouldBindJSON(&status); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request dto.TaskStatusRequest
+	if !bindRequest(c, &request) {
 		return
 	}
 
+	status := request.TaskStatus()
 	status.ID = id
-	updatedStatus, err := h.repository.Update(status)
+	status.Version = version
+	updatedStatus, err := h.repository.Update(c.Request.Context(), status)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortTaskStatusWrite(c, id, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, updatedStatus)
+	setETag(c, updatedStatus.Version)
+	c.JSON(http.StatusOK, dto.NewTaskStatusResponse(updatedStatus))
 }
 
 // DeleteTaskStatus deletes a task status
@@ -122,17 +114,18 @@ func (h *TaskStatusHandler) UpdateTaskStatus(c *gin.Context) {
 func (h *TaskStatusHandler) DeleteTaskStatus(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid status ID"))
 		return
 	}
 
-	err = h.repository.Remove(id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	err = h.repository.Remove(c.Request.Context(), id, version)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortTaskStatusWrite(c, id, err)
 		return
 	}
 
@@ -140,3 +133,15 @@ func (h *TaskStatusHandler) DeleteTaskStatus(c *gin.Context) {
 	addValidationHeaders(c)
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// abortTaskStatusWrite answers a failed write, a version conflict with 412 and the stored task status
+func (h *TaskStatusHandler) abortTaskStatusWrite(c *gin.Context, id int64, err error) {
+	if errors.Is(err, domain.ErrVersionConflict) {
+		if current, getErr := h.repository.GetByID(c.Request.Context(), id); getErr == nil {
+			abortVersionConflict(c, dto.NewTaskStatusResponse(current), current.Version)
+			return
+		}
+	}
+
+	abortWithError(c, err)
+}
@@ -1,10 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"todo-api/internal/domain"
-	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,26 +23,29 @@ func NewTaskTypeHandler(repository domain.TaskTypeRepository) *TaskTypeHandler {
 // CreateTaskType creates a new task type
 // @POST /task-types
 func (h *TaskTypeHandler) CreateTaskType(c *gin.Context) {
-	var taskType entities.TaskType
-
-	if err := c.ShouldBindJSON(&taskType); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request dto.TaskTypeRequest
+	if !bindRequest(c, &request) {
 		return
 	}
 
-	createdTaskType, err := h.repository.Create(taskType)
+	// A task type without team is shared by every team
+	if request.TeamID != 0 {
+		if _, ok := resolveCallerTeam(c, request.TeamID); !ok {
+			abortForbiddenTeam(c)
+			return
+		}
+	}
+
+	createdTaskType, err := h.repository.Create(c.Request.Context(), request.TaskType())
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusCreated, createdTaskType)
+	setETag(c, createdTaskType.Version)
+	c.JSON(http.StatusCreated, dto.NewTaskTypeResponse(createdTaskType))
 }
 
 // GetTaskType retrieves a task type by ID
@@ -49,39 +53,34 @@ func (h *TaskTypeHandler) CreateTaskType(c *gin.Context) {
 func (h *TaskTypeHandler) GetTaskType(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task type ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid task type ID"))
 		return
 	}
 
-	taskType, err := h.repository.GetByID(id)
+	taskType, err := h.repository.GetByID(c.Request.Context(), id, callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, taskType)
+	setETag(c, taskType.Version)
+	c.JSON(http.StatusOK, dto.NewTaskTypeResponse(taskType))
 }
 
 // GetAllTaskTypes retrieves all task types
 // @GET /task-types
 func (h *TaskTypeHandler) GetAllTaskTypes(c *gin.Context) {
-	taskTypes, err := h.repository.GetAll()
+	taskTypes, err := h.repository.GetAll(c.Request.Context(), callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, taskTypes)
+	c.JSON(http.StatusOK, dto.NewTaskTypeResponses(taskTypes))
 }
 
 // UpdateTaskType updates a task type
@@ -89,32 +88,45 @@ func (h *TaskTypeHandler) GetAllTaskTypes(c *gin.Context) {
 func (h *TaskTypeHandler) UpdateTaskType(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task type ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid task type ID"))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	var taskType entities.TaskType
-	if err := c.ShouldBindJSON(&taskType); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request dto.TaskTypeRequest
+	if !bindRequest(c, &request) {
 		return
 	}
 
+	if _, err := h.repository.GetByID(c.Request.Context(), id, callerTeamIDs(c)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if request.TeamID != 0 {
+		if _, ok := resolveCallerTeam(c, request.TeamID); !ok {
+			abortForbiddenTeam(c)
+			return
+		}
+	}
+
+	taskType := request.TaskType()
 	taskType.ID = id
-	updatedTaskType, err := h.repository.Update(taskType)
+	taskType.Version = version
+	updatedTaskType, err := h.repository.Update(c.Request.Context(), taskType)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortTaskTypeWrite(c, id, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, updatedTaskType)
+	setETag(c, updatedTaskType.Version)
+	c.JSON(http.StatusOK, dto.NewTaskTypeResponse(updatedTaskType))
 }
 
 // DeleteTaskType deletes a task type
@@ -122,17 +134,23 @@ func (h *TaskTypeHandler) UpdateTaskType(c *gin.Context) {
 func (h *TaskTypeHandler) DeleteTaskType(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task type ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid task type ID"))
 		return
 	}
 
-	err = h.repository.Remove(id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repository.GetByID(c.Request.Context(), id, callerTeamIDs(c)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	err = h.repository.Remove(c.Request.Context(), id, version)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortTaskTypeWrite(c, id, err)
 		return
 	}
 
@@ -140,3 +158,15 @@ func (h *TaskTypeHandler) DeleteTaskType(c *gin.Context) {
 	addValidationHeaders(c)
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// abortTaskTypeWrite answers a failed write, a version conflict with 412 and the stored task type
+func (h *TaskTypeHandler) abortTaskTypeWrite(c *gin.Context, id int64, err error) {
+	if errors.Is(err, domain.ErrVersionConflict) {
+		if current, getErr := h.repository.GetByID(c.Request.Context(), id, callerTeamIDs(c)); getErr == nil {
+			abortVersionConflict(c, dto.NewTaskTypeResponse(current), current.Version)
+			return
+		}
+	}
+
+	abortWithError(c, err)
+}
@@ -1 +1,226 @@
 package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TeamHandler struct {
+	repository domain.TeamRepository
+}
+
+func NewTeamHandler(repository domain.TeamRepository) *TeamHandler {
+	return &TeamHandler{
+		repository: repository,
+	}
+}
+
+// CreateTeam creates a new team
+// @POST /teams
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	var request dto.TeamRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	createdTeam, err := h.repository.Create(c.Request.Context(), entities.NewTeam(request.Name))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusCreated, dto.NewTeamResponse(createdTeam))
+}
+
+// GetTeam retrieves a team by ID, only admins can see teams they are not a member of
+// @GET /teams/:id
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	id, ok := h.visibleTeamID(c)
+	if !ok {
+		return
+	}
+
+	team, err := h.repository.GetByID(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewTeamResponse(team))
+}
+
+// GetAllTeams retrieves every team for admins and the caller's teams for everyone else
+// @GET /teams
+func (h *TeamHandler) GetAllTeams(c *gin.Context) {
+	var teams []entities.Team
+	var err error
+	if isAdmin(c) {
+		teams, err = h.repository.GetAll(c.Request.Context())
+	} else {
+		teams, err = h.repository.GetAllByMember(c.Request.Context(), c.GetInt64("user_id"))
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewTeamResponses(teams))
+}
+
+// UpdateTeam renames a team
+// @PUT /teams/:id
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid team ID"))
+		return
+	}
+
+	var request dto.TeamRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	team, err := h.repository.GetByID(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	team.Rename(request.Name)
+	updatedTeam, err := h.repository.Update(c.Request.Context(), team)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewTeamResponse(updatedTeam))
+}
+
+// DeleteTeam deletes a team
+// @DELETE /teams/:id
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid team ID"))
+		return
+	}
+
+	err = h.repository.Remove(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetTeamMembers retrieves the users belonging to a team
+// @GET /teams/:id/members
+func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
+	id, ok := h.visibleTeamID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.repository.GetMembers(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewUserResponses(members))
+}
+
+// AddTeamMember adds a user to a team
+// @POST /teams/:id/members
+func (h *TeamHandler) AddTeamMember(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid team ID"))
+		return
+	}
+
+	var request dto.TeamMemberRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	if _, err := h.repository.GetByID(c.Request.Context(), id); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	err = h.repository.AddMember(c.Request.Context(), id, request.UserID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RemoveTeamMember removes a user from a team
+// @DELETE /teams/:id/members/:userID
+func (h *TeamHandler) RemoveTeamMember(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid team ID"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("userID"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("userID", "Invalid user ID"))
+		return
+	}
+
+	err = h.repository.RemoveMember(c.Request.Context(), id, userID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// visibleTeamID parses the team ID of the path and answers 404 when the caller is neither
+// an admin nor a member of the team, so the existence of other teams is not disclosed
+func (h *TeamHandler) visibleTeamID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid team ID"))
+		return 0, false
+	}
+
+	if isAdmin(c) {
+		return id, true
+	}
+	if _, ok := resolveCallerTeam(c, id); !ok {
+		abortWithError(c, domain.NewNotFoundError("team", id))
+		return 0, false
+	}
+
+	return id, true
+}
@@ -1 +1,137 @@
 package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"todo-api/internal/domain"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	repository domain.UserRepository
+}
+
+func NewUserHandler(repository domain.UserRepository) *UserHandler {
+	return &UserHandler{
+		repository: repository,
+	}
+}
+
+// CreateUser creates a new user
+// @POST /users
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var request dto.CreateUserRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	user, err := request.User()
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	createdUser, err := h.repository.Create(c.Request.Context(), user)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusCreated, dto.NewUserResponse(createdUser))
+}
+
+// GetUser retrieves a user by ID
+// @GET /users/:id
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid user ID"))
+		return
+	}
+
+	user, err := h.repository.GetByID(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewUserResponse(user))
+}
+
+// GetAllUsers retrieves all users
+// @GET /users
+func (h *UserHandler) GetAllUsers(c *gin.Context) {
+	users, err := h.repository.GetAll(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewUserResponses(users))
+}
+
+// UpdateUser updates the profile fields of a user
+// @PUT /users/:id
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid user ID"))
+		return
+	}
+
+	var request dto.UpdateUserRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	user, err := h.repository.GetByID(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	user.Name = request.Name
+	user.Username = request.Username
+	user.Email = request.Email
+	if request.Role != "" {
+		user.Role = request.Role
+	}
+
+	updatedUser, err := h.repository.Update(c.Request.Context(), user)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, dto.NewUserResponse(updatedUser))
+}
+
+// DeactivateUser deactivates a user, keeping the row so authored tasks and workflows stay valid
+// @DELETE /users/:id
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid user ID"))
+		return
+	}
+
+	err = h.repository.Deactivate(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -1,47 +1,71 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"todo-api/internal/domain"
 	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
 
 	"github.com/gin-gonic/gin"
 )
 
 type WorkflowHandler struct {
-	repository domain.WorkflowRepository
+	service *domain.WorkflowService
 }
 
-func NewWorkflowHandler(repository domain.WorkflowRepository) *WorkflowHandler {
+func NewWorkflowHandler(service *domain.WorkflowService) *WorkflowHandler {
 	return &WorkflowHandler{
-		repository: repository,
+		service: service,
 	}
 }
 
+// abortWorkflowWrite answers a write of the workflow id the service rejected. A stale version is answered with 412
+// and the workflow as it is now, the other errors by the problem they map to.
+func (h *WorkflowHandler) abortWorkflowWrite(c *gin.Context, id int64, err error) {
+	if errors.Is(err, domain.ErrVersionConflict) {
+		if current, getErr := h.service.Get(c.Request.Context(), id, callerTeamIDs(c)); getErr == nil {
+			abortVersionConflict(c, dto.NewWorkflowResponse(current), current.Version)
+			return
+		}
+	}
+	abortWithError(c, err)
+}
+
 // CreateWorkflow creates a new workflow
 // @POST /workflows
 func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
-	var workflow entities.Workflow
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		abortUnauthenticated(c)
+		return
+	}
 
-	if err := c.ShouldBindJSON(&workflow); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request dto.WorkflowRequest
+	if !bindRequest(c, &request) {
 		return
 	}
 
-	createdWorkflow, err := h.repository.Create(workflow)
+	workflow := request.Workflow()
+	teamID, ok := resolveCallerTeam(c, workflow.TeamID)
+	if !ok {
+		abortForbiddenTeam(c)
+		return
+	}
+	workflow.TeamID = teamID
+
+	createdWorkflow, err := h.service.Create(c.Request.Context(), workflow, userID)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusCreated, createdWorkflow)
+	setETag(c, createdWorkflow.Version)
+	c.JSON(http.StatusCreated, dto.NewWorkflowResponse(createdWorkflow))
 }
 
 // GetWorkflow retrieves a workflow by ID
@@ -49,90 +73,109 @@ func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
 func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid workflow ID"))
 		return
 	}
 
-	workflow, err := h.repository.GetByID(id)
+	workflow, err := h.service.Get(c.Request.Context(), id, callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, workflow)
+	setETag(c, workflow.Version)
+	c.JSON(http.StatusOK, dto.NewWorkflowResponse(workflow))
 }
 
 // GetAllWorkflows retrieves all workflows
 // @GET /workflows
 func (h *WorkflowHandler) GetAllWorkflows(c *gin.Context) {
-	workflows, err := h.repository.GetAll()
+	workflows, err := h.service.GetAll(c.Request.Context(), callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, workflows)
+	c.JSON(http.StatusOK, dto.NewWorkflowResponses(workflows))
 }
 
-// UpdateWorkflow updates a workflow
+// UpdateWorkflow updates a workflow. If-Match must hold the ETag of the workflow, a stale one is answered
+// with 412 and the workflow as it is now.
 // @PUT /workflows/:id
 func (h *WorkflowHandler) UpdateWorkflow(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid workflow ID"))
 		return
 	}
 
-	var workflow entities.Workflow
-	if err := c.ShouldBindJSON(&workflow); err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	workflow.ID = id
-	updatedWorkflow, err := h.repository.Update(workflow)
+	var request dto.UpdateWorkflowRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	// Moving a workflow to another team requires membership of that team too
+	workflow := request.Workflow()
+	if workflow.TeamID != 0 {
+		if _, ok := resolveCallerTeam(c, workflow.TeamID); !ok {
+			abortForbiddenTeam(c)
+			return
+		}
+	}
+
+	// Tasks in statuses the update removes must be remapped, otherwise the update is rejected
+	workflow.Version = version
+	updatedWorkflow, err := h.service.Update(c.Request.Context(), id, workflow, request.StatusMapping, c.GetInt64("user_id"), callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortWorkflowWrite(c, id, err)
 		return
 	}
 
 	addSuccessHeaders(c)
 	addValidationHeaders(c)
-	c.JSON(http.StatusOK, updatedWorkflow)
+	setETag(c, updatedWorkflow.Version)
+	c.JSON(http.StatusOK, dto.NewWorkflowResponse(updatedWorkflow))
 }
 
-// DeleteWorkflow deletes a workflow
+// DeleteWorkflow deletes a workflow. A workflow still used by tasks is only deleted
+// when the body moves them to another workflow, as MigrateWorkflow does. If-Match is handled as by UpdateWorkflow.
 // @DELETE /workflows/:id
 func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+		abortWithError(c, domain.NewValidationError("id", "Invalid workflow ID"))
 		return
 	}
 
-	err = h.repository.Remove(id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var migration *entities.WorkflowMigration
+	if c.Request.Body != nil {
+		var request dto.WorkflowMigrationRequest
+		if err := c.ShouldBindJSON(&request); err == nil {
+			move := request.Migration(id, c.GetInt64("user_id"))
+			migration = &move
+		} else if !errors.Is(err, io.EOF) {
+			abortWithError(c, invalidBody(err))
+			return
+		}
+	}
+
+	err = h.service.Remove(c.Request.Context(), id, version, migration, callerTeamIDs(c))
 	if err != nil {
-		addErrorHeaders(c)
-		c.Header("Content-Type", "application/json; charset=utf-8")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.abortWorkflowWrite(c, id, err)
 		return
 	}
 
@@ -140,3 +183,28 @@ func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
 	addValidationHeaders(c)
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// MigrateWorkflow moves the tasks of a workflow to other statuses, possibly of another workflow, in one transaction
+// @POST /workflows/:id/migrate
+func (h *WorkflowHandler) MigrateWorkflow(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		abortWithError(c, domain.NewValidationError("id", "Invalid workflow ID"))
+		return
+	}
+
+	var request dto.WorkflowMigrationRequest
+	if !bindRequest(c, &request) {
+		return
+	}
+
+	migrated, err := h.service.Migrate(c.Request.Context(), request.Migration(id, c.GetInt64("user_id")), callerTeamIDs(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	addSuccessHeaders(c)
+	addValidationHeaders(c)
+	c.JSON(http.StatusOK, gin.H{"migrated_tasks": migrated})
+}
@@ -0,0 +1,178 @@
+// Package problems maps the errors of request handling to the problem details (RFC 7807) answering them
+package problems
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+)
+
+// ContentType is the media type of error responses (RFC 7807)
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest answers requests cancelled by the client before they completed, as nginx does
+const StatusClientClosedRequest = 499
+
+// Problem is the body of every error response, a problem details object (RFC 7807). Code is stable across
+// releases and is what clients should branch on, Detail is meant for humans. Extensions are members
+// specific to the problem, such as the rejected fields of a request.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       string
+	RequestID  string
+	Extensions map[string]interface{}
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+7)
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	members["detail"] = p.Detail
+	members["code"] = p.Code
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		members["request_id"] = p.RequestID
+	}
+	return json.Marshal(members)
+}
+
+// RequestError is a request a handler rejects before reaching the domain, answered with Status and Code as they are
+type RequestError struct {
+	Status int
+	Code   string
+	Err    error
+}
+
+func NewRequestError(status int, code string, message string) *RequestError {
+	return &RequestError{Status: status, Code: code, Err: errors.New(message)}
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrUnauthenticated rejects requests without a valid access token, and handlers reached without an authenticated caller
+	ErrUnauthenticated error = NewRequestError(http.StatusUnauthorized, api.ERROR_CODE_UNAUTHORIZED, "authentication is required")
+	// ErrIfMatchRequired is returned by versioned writes sent without an If-Match header
+	ErrIfMatchRequired error = NewRequestError(http.StatusPreconditionRequired, api.ERROR_CODE_PRECONDITION_REQUIRED,
+		"If-Match header is required, send the ETag of the resource")
+)
+
+// New maps an error returned while handling a request to the problem answering it:
+//
+//	*domain.ValidationError, *dto.ValidationError   400 ERR-VL040
+//	errors matching domain.ErrValidation            400 ERR-VL040
+//	*domain.ForbiddenError                          403 ERR-FB043
+//	*domain.NotFoundError                           404 ERR-NF044
+//	*domain.ConflictError                           409 ERR-CF049
+//	*entities.OrphanedTasksError                    409 ERR-OT049
+//	domain.ErrVersionConflict                       412 ERR-VC412
+//	*domain.InvalidReferenceError                   422 ERR-RF422
+//	*entities.GuardError                            422 ERR-GD422
+//	entities.ErrStatusNotInWorkflow, ErrTransitionNotAllowed 422 ERR-TR422
+//	*entities.DependencyCycleError                  422 ERR-CY422
+//	*entities.BlockedTaskError                      422 ERR-BK422
+//	entities.ErrInvalidMigration                    422 ERR-MG422
+//	*RequestError                                   its own status and code
+//	context.Canceled                                499 ERR-CC499
+//	context.DeadlineExceeded                        504 ERR-TO504
+//
+// Any other error is answered with 500 ERR-IN500, without its message.
+func New(err error) Problem {
+	problem := Problem{Detail: err.Error(), Extensions: map[string]interface{}{}}
+
+	var request *RequestError
+	var fieldsErr *dto.ValidationError
+	var validation *domain.ValidationError
+	var invalid *domain.InvalidReferenceError
+	var orphaned *entities.OrphanedTasksError
+	var guardErr *entities.GuardError
+	var cycleErr *entities.DependencyCycleError
+	var blockedErr *entities.BlockedTaskError
+	switch {
+	case errors.As(err, &request):
+		problem.Status, problem.Code = request.Status, request.Code
+	case errors.As(err, &fieldsErr):
+		problem.Status, problem.Code = http.StatusBadRequest, api.ERROR_CODE_VALIDATION
+	case errors.As(err, &validation):
+		problem.Status, problem.Code = http.StatusBadRequest, api.ERROR_CODE_VALIDATION
+		if validation.Field != "" {
+			problem.Extensions["field"] = validation.Field
+		}
+	case errors.Is(err, domain.ErrValidation):
+		// Errors only matching the kind, such as those of an invalid workflow, keep their own message
+		problem.Status, problem.Code = http.StatusBadRequest, api.ERROR_CODE_VALIDATION
+	case errors.Is(err, domain.ErrForbidden):
+		problem.Status, problem.Code = http.StatusForbidden, api.ERROR_CODE_FORBIDDEN
+	case errors.Is(err, domain.ErrNotFound):
+		problem.Status, problem.Code = http.StatusNotFound, api.ERROR_CODE_NOT_FOUND
+	case errors.Is(err, domain.ErrConflict):
+		problem.Status, problem.Code = http.StatusConflict, api.ERROR_CODE_CONFLICT
+	case errors.As(err, &orphaned):
+		problem.Status, problem.Code = http.StatusConflict, api.ERROR_CODE_ORPHANED_TASKS
+		problem.Extensions["orphaned_status_ids"] = orphaned.StatusIDs
+	case errors.Is(err, domain.ErrVersionConflict):
+		problem.Status, problem.Code = http.StatusPreconditionFailed, api.ERROR_CODE_VERSION_CONFLICT
+	case errors.As(err, &invalid):
+		problem.Status, problem.Code = http.StatusUnprocessableEntity, api.ERROR_CODE_INVALID_REFERENCE
+		if invalid.Field != "" {
+			problem.Extensions["field"] = invalid.Field
+		}
+	case errors.As(err, &guardErr):
+		problem.Status, problem.Code = http.StatusUnprocessableEntity, api.ERROR_CODE_TRANSITION_BLOCKED
+		problem.Extensions["failed_guards"] = dto.NewGuardFailureResponses(guardErr)
+	case errors.Is(err, entities.ErrStatusNotInWorkflow), errors.Is(err, entities.ErrTransitionNotAllowed):
+		problem.Status, problem.Code = http.StatusUnprocessableEntity, api.ERROR_CODE_TRANSITION_NOT_ALLOWED
+	case errors.As(err, &cycleErr):
+		problem.Status, problem.Code = http.StatusUnprocessableEntity, api.ERROR_CODE_DEPENDENCY_CYCLE
+		problem.Extensions["cycle"] = cycleErr.TaskIDs
+	case errors.As(err, &blockedErr):
+		problem.Status, problem.Code = http.StatusUnprocessableEntity, api.ERROR_CODE_TASK_BLOCKED
+		problem.Extensions["open_blocker_ids"] = blockedErr.BlockerIDs
+	case errors.Is(err, entities.ErrInvalidMigration):
+		problem.Status, problem.Code = http.StatusUnprocessableEntity, api.ERROR_CODE_INVALID_MIGRATION
+	case errors.Is(err, context.Canceled):
+		// Nobody reads the answer of a request the client gave up on, it only shows in the logs
+		problem.Status, problem.Code = StatusClientClosedRequest, api.ERROR_CODE_CLIENT_CLOSED_REQUEST
+		problem.Detail = "the client closed the request"
+	case errors.Is(err, context.DeadlineExceeded):
+		problem.Status, problem.Code = http.StatusGatewayTimeout, api.ERROR_CODE_TIMEOUT
+		problem.Detail = "the request took too long to complete"
+	default:
+		// The message of an unexpected error can leak queries or internals, it is only logged
+		problem.Status, problem.Code = http.StatusInternalServerError, api.ERROR_CODE_INTERNAL
+		problem.Detail = "an unexpected error occurred"
+	}
+
+	// Rejected request bodies list each rejected field, whatever status they are answered with
+	if errors.As(err, &fieldsErr) {
+		problem.Extensions["fields"] = fieldsErr.Fields
+	}
+
+	problem.Type = "about:blank"
+	problem.Title = http.StatusText(problem.Status)
+	if problem.Status == StatusClientClosedRequest {
+		problem.Title = "Client Closed Request"
+	}
+	return problem
+}
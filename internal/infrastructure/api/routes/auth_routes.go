@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"todo-api/internal/domain"
+	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetAuthRoutes(router *gin.RouterGroup, users domain.UserRepository, tokens domain.RefreshTokenRepository, config utils.JWTConfig) {
+	handler := handlers.NewAuthHandler(users, tokens, config)
+
+	auth := router.Group("/auth")
+	{
+		auth.POST("/login", handler.Login)
+		auth.POST("/refresh", handler.Refresh)
+		auth.POST("/logout", handler.Logout)
+	}
+}
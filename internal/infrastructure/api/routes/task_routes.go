@@ -2,26 +2,45 @@ package routes
 
 import (
 	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
 	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetTaskRoutes(router *gin.RouterGroup, repository domain.TaskRepository) {
-	handler := handlers.NewTaskHandler(repository)
+func SetTaskRoutes(router *gin.RouterGroup, service *domain.TaskService) {
+	handler := handlers.NewTaskHandler(service)
 
 	tasks := router.Group("/todo")
 	{
-		// CRUD operations
-		tasks.POST("", handler.CreateTask)
+		// Read operations, open to every role
 		tasks.GET("", handler.GetAllTasks)
 		tasks.GET("/:id", handler.GetTask)
-		tasks.PUT("/:id", handler.UpdateTask)
-		tasks.DELETE("/:id", handler.DeleteTask)
+		tasks.GET("/:id/subtasks", handler.GetSubtasks)
+		tasks.GET("/:id/history", handler.GetTaskHistory)
+		tasks.GET("/:id/action-failures", handler.GetActionFailures)
+		tasks.GET("/:id/dependencies", handler.GetDependencies)
 
 		// Special queries
 		tasks.GET("/responsible/:userID", handler.GetTasksByResponsible)
 		tasks.GET("/author/:userID", handler.GetTasksByAuthor)
 		tasks.GET("/overdue", handler.GetOverdueTasks)
+		tasks.GET("/search", handler.SearchTasks)
+		tasks.GET("/status/:statusID", handler.GetTasksByStatus)
+		tasks.GET("/board/:workflowID", handler.GetBoard)
+		tasks.GET("/plan", handler.GetPlan)
+	}
+
+	editors := tasks.Group("", middleware.RequireRole(entities.RoleAdmin, entities.RoleMember))
+	{
+		editors.POST("", handler.CreateTask)
+		editors.PUT("/:id", handler.UpdateTask)
+		editors.PATCH("/:id", handler.PatchTask)
+		editors.DELETE("/:id", handler.DeleteTask)
+		editors.POST("/:id/transition", handler.TransitionTask)
+		editors.PUT("/:id/parent", handler.ReparentTask)
+		editors.POST("/:id/dependencies", handler.AddDependency)
+		editors.DELETE("/:id/dependencies/:blockerID", handler.RemoveDependency)
 	}
 }
@@ -2,7 +2,9 @@ package routes
 
 import (
 	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
 	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,10 +14,14 @@ func SetTaskStatusRoutes(router *gin.RouterGroup, repository domain.TaskStatusRe
 
 	statuses := router.Group("/statuses")
 	{
-		statuses.POST("", handler.CreateTaskStatus)
 		statuses.GET("", handler.GetAllTaskStatuses)
 		statuses.GET("/:id", handler.GetTaskStatus)
-		statuses.PUT("/:id", handler.UpdateTaskStatus)
-		statuses.DELETE("/:id", handler.DeleteTaskStatus)
+	}
+
+	admins := statuses.Group("", middleware.RequireRole(entities.RoleAdmin))
+	{
+		admins.POST("", handler.CreateTaskStatus)
+		admins.PUT("/:id", handler.UpdateTaskStatus)
+		admins.DELETE("/:id", handler.DeleteTaskStatus)
 	}
 }
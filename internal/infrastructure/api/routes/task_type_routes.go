@@ -2,7 +2,9 @@ package routes
 
 import (
 	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
 	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,10 +14,14 @@ func SetTaskTypeRoutes(router *gin.RouterGroup, repository domain.TaskTypeReposi
 
 	types := router.Group("/task-type")
 	{
-		types.POST("", handler.CreateTaskType)
 		types.GET("", handler.GetAllTaskTypes)
 		types.GET("/:id", handler.GetTaskType)
-		types.PUT("/:id", handler.UpdateTaskType)
-		types.DELETE("/:id", handler.DeleteTaskType)
+	}
+
+	admins := types.Group("", middleware.RequireRole(entities.RoleAdmin))
+	{
+		admins.POST("", handler.CreateTaskType)
+		admins.PUT("/:id", handler.UpdateTaskType)
+		admins.DELETE("/:id", handler.DeleteTaskType)
 	}
 }
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetTeamRoutes(router *gin.RouterGroup, repository domain.TeamRepository) {
+	handler := handlers.NewTeamHandler(repository)
+
+	teams := router.Group("/teams")
+	{
+		teams.GET("", handler.GetAllTeams)
+		teams.GET("/:id", handler.GetTeam)
+		teams.GET("/:id/members", handler.GetTeamMembers)
+	}
+
+	admins := teams.Group("", middleware.RequireRole(entities.RoleAdmin))
+	{
+		admins.POST("", handler.CreateTeam)
+		admins.PUT("/:id", handler.UpdateTeam)
+		admins.DELETE("/:id", handler.DeleteTeam)
+		admins.POST("/:id/members", handler.AddTeamMember)
+		admins.DELETE("/:id/members/:userID", handler.RemoveTeamMember)
+	}
+}
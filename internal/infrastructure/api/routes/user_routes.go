@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetUserRoutes(router *gin.RouterGroup, repository domain.UserRepository) {
+	handler := handlers.NewUserHandler(repository)
+
+	users := router.Group("/users")
+	{
+		users.GET("", handler.GetAllUsers)
+		users.GET("/:id", handler.GetUser)
+	}
+
+	admins := users.Group("", middleware.RequireRole(entities.RoleAdmin))
+	{
+		admins.POST("", handler.CreateUser)
+		admins.PUT("/:id", handler.UpdateUser)
+		admins.DELETE("/:id", handler.DeactivateUser)
+	}
+}
@@ -2,20 +2,27 @@ package routes
 
 import (
 	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
 	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetWorkflowRoutes(router *gin.RouterGroup, repository domain.WorkflowRepository) {
-	handler := handlers.NewWorkflowHandler(repository)
+func SetWorkflowRoutes(router *gin.RouterGroup, service *domain.WorkflowService) {
+	handler := handlers.NewWorkflowHandler(service)
 
 	workflows := router.Group("/workflows")
 	{
-		workflows.POST("", handler.CreateWorkflow)
 		workflows.GET("", handler.GetAllWorkflows)
 		workflows.GET("/:id", handler.GetWorkflow)
-		workflows.PUT("/:id", handler.UpdateWorkflow)
-		workflows.DELETE("/:id", handler.DeleteWorkflow)
+	}
+
+	admins := workflows.Group("", middleware.RequireRole(entities.RoleAdmin))
+	{
+		admins.POST("", handler.CreateWorkflow)
+		admins.PUT("/:id", handler.UpdateWorkflow)
+		admins.DELETE("/:id", handler.DeleteWorkflow)
+		admins.POST("/:id/migrate", handler.MigrateWorkflow)
 	}
 }
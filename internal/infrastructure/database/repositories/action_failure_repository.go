@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+type ActionFailureRepository struct {
+	db *sql.DB
+}
+
+func NewActionFailureRepository(db *sql.DB) domain.ActionFailureRepository {
+	return &ActionFailureRepository{db: db}
+}
+
+func (r *ActionFailureRepository) Record(ctx context.Context, failure entities.ActionFailure) (entities.ActionFailure, error) {
+	query := `INSERT INTO task_action_failures (task_id, from_status_id, to_status_id, action, error, created_at) 
+              VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query,
+		failure.TaskID, failure.FromStatusID, failure.ToStatusID, failure.Action, failure.Error, failure.CreatedAt,
+	)
+	if err != nil {
+		return entities.ActionFailure{}, fmt.Errorf("failed to record action failure: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return entities.ActionFailure{}, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	failure.ID = id
+	return failure, nil
+}
+
+func (r *ActionFailureRepository) GetAllByTask(ctx context.Context, taskID int64) ([]entities.ActionFailure, error) {
+	query := `SELECT id, task_id, from_status_id, to_status_id, action, error, created_at 
+              FROM task_action_failures WHERE task_id = ? ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action failures: %w", err)
+	}
+	defer rows.Close()
+
+	failures := []entities.ActionFailure{}
+	for rows.Next() {
+		var failure entities.ActionFailure
+		err := rows.Scan(
+			&failure.ID, &failure.TaskID, &failure.FromStatusID, &failure.ToStatusID,
+			&failure.Action, &failure.Error, &failure.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan action failure: %w", err)
+		}
+		failures = append(failures, failure)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating action failures: %w", err)
+	}
+
+	return failures, nil
+}
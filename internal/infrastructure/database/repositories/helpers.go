@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"todo-api/internal/domain"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// isDuplicateEntry reports whether err is a unique constraint violation on the given column.
+// MySQL reports error 1062 naming the key, SQLite reports "UNIQUE constraint failed: table.column".
+func isDuplicateEntry(err error, column string) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, column)
+	}
+
+	message := err.Error()
+	return strings.Contains(message, "UNIQUE constraint failed") && strings.Contains(message, "."+column)
+}
+
+// teamFilter builds a "column IN (?, ...)" condition and its arguments for the given team IDs.
+// An empty list matches no rows, so a caller outside every team sees nothing.
+func teamFilter(column string, teamIDs []int64) (string, []interface{}) {
+	if len(teamIDs) == 0 {
+		return "1 = 0", nil
+	}
+
+	placeholders := make([]string, len(teamIDs))
+	args := make([]interface{}, len(teamIDs))
+	for i, teamID := range teamIDs {
+		placeholders[i] = "?"
+		args[i] = teamID
+	}
+
+	return column + " IN (" + strings.Join(placeholders, ", ") + ")", args
+}
+
+// isMySQL reports whether db talks to MySQL, every other connection is the SQLite one used in tests
+func isMySQL(db *sql.DB) bool {
+	_, ok := db.Driver().(*mysql.MySQLDriver)
+	return ok
+}
+
+// nullableID returns the value stored for an optional reference, NULL when the ID is not set
+func nullableID(id int64) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// foreignKeyColumn matches the referencing column named in MySQL foreign key errors
+var foreignKeyColumn = regexp.MustCompile("FOREIGN KEY \\(`(\\w+)`\\)")
+
+// foreignKeyViolation converts a foreign key violation into a *domain.InvalidReferenceError.
+// MySQL reports error 1452 naming the column, which is given as the field without its "_id" suffix;
+// SQLite only reports "FOREIGN KEY constraint failed", so the field is left empty.
+func foreignKeyViolation(err error) (*domain.InvalidReferenceError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if mysqlErr.Number != 1452 {
+			return nil, false
+		}
+		if match := foreignKeyColumn.FindStringSubmatch(mysqlErr.Message); match != nil {
+			field := strings.TrimSuffix(match[1], "_id")
+			return &domain.InvalidReferenceError{Field: field, Message: field + " refers to an entity that does not exist"}, true
+		}
+		return domain.NewMissingReferenceError("", 0), true
+	}
+
+	if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+		return domain.NewMissingReferenceError("", 0), true
+	}
+	return nil, false
+}
+
+// checkVersion rejects a write based on another version than the stored one, a zero version is based on any of them
+func checkVersion(version int64, stored int64) error {
+	if version != 0 && version != stored {
+		return domain.ErrVersionConflict
+	}
+	return nil
+}
+
+// versionWritten reports ErrVersionConflict when a write guarded by the version read before it matched no row,
+// which means another write came first
+func versionWritten(result sql.Result) error {
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.ErrVersionConflict
+	}
+	return nil
+}
+
+// lockVersion reads the version of a row in a transaction, keeping the row locked until a MySQL transaction ends.
+// A missing row fails with sql.ErrNoRows.
+func lockVersion(ctx context.Context, tx *sql.Tx, db *sql.DB, table string, id int64) (int64, error) {
+	query := "SELECT version FROM " + table + " WHERE id = ?"
+	if isMySQL(db) {
+		query += " FOR UPDATE"
+	}
+
+	var version int64
+	err := tx.QueryRowContext(ctx, query, id).Scan(&version)
+	return version, err
+}
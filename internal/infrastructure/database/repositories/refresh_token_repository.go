@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) domain.RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token entities.RefreshToken) (entities.RefreshToken, error) {
+	query := "INSERT INTO refresh_tokens (id, user_id, expires_at, revoked, created_at) VALUES (?, ?, ?, ?, ?)"
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.UserID, token.ExpiresAt, token.Revoked, token.CreatedAt)
+	if err != nil {
+		return entities.RefreshToken{}, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) GetByID(ctx context.Context, id string) (entities.RefreshToken, error) {
+	query := "SELECT id, user_id, expires_at, revoked, replaced_by, created_at FROM refresh_tokens WHERE id = ?"
+	var token entities.RefreshToken
+	var replacedBy sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.Revoked, &replacedBy, &token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.RefreshToken{}, domain.NewNotFoundError("refresh token", 0)
+		}
+		return entities.RefreshToken{}, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	token.ReplacedBy = replacedBy.String
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id string, replacedBy string) error {
+	var successor *string
+	if replacedBy != "" {
+		successor = &replacedBy
+	}
+
+	query := "UPDATE refresh_tokens SET revoked = ?, replaced_by = ? WHERE id = ?"
+	_, err := r.db.ExecContext(ctx, query, true, successor, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, id string, successor entities.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Claiming the token is the check: of concurrent rotations only one updates the row
+	query := "UPDATE refresh_tokens SET revoked = ?, replaced_by = ? WHERE id = ? AND revoked = ?"
+	result, err := tx.ExecContext(ctx, query, true, successor.ID, id, false)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if claimed == 0 {
+		return domain.ErrTokenReused
+	}
+
+	query = "INSERT INTO refresh_tokens (id, user_id, expires_at, revoked, created_at) VALUES (?, ?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, successor.ID, successor.UserID, successor.ExpiresAt, successor.Revoked, successor.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	query := "UPDATE refresh_tokens SET revoked = ? WHERE user_id = ? AND revoked = ?"
+	_, err := r.db.ExecContext(ctx, query, true, userID, false)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+type TaskDependencyRepository struct {
+	db *sql.DB
+}
+
+func NewTaskDependencyRepository(db *sql.DB) domain.TaskDependencyRepository {
+	return &TaskDependencyRepository{db: db}
+}
+
+func (r *TaskDependencyRepository) Add(ctx context.Context, dependency entities.TaskDependency) (entities.TaskDependency, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.TaskDependency{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Every dependency is read locked, so concurrent additions are checked one after the other
+	query := `SELECT blocker_id, blocked_id, created_at FROM task_dependencies`
+	if isMySQL(r.db) {
+		query += ` FOR UPDATE`
+	}
+	existing, err := scanDependencies(tx.QueryContext(ctx, query))
+	if err != nil {
+		return entities.TaskDependency{}, err
+	}
+	if err := dependency.CheckCycle(existing); err != nil {
+		return entities.TaskDependency{}, err
+	}
+
+	query = `INSERT INTO task_dependencies (blocker_id, blocked_id, created_at) VALUES (?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, dependency.BlockerID, dependency.BlockedID, dependency.CreatedAt); err != nil {
+		if isDuplicateEntry(err, "PRIMARY") || isDuplicateEntry(err, "blocker_id") {
+			return entities.TaskDependency{}, domain.ErrDependencyExists
+		}
+		if violation, ok := foreignKeyViolation(err); ok {
+			return entities.TaskDependency{}, violation
+		}
+		return entities.TaskDependency{}, fmt.Errorf("failed to add task dependency: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.TaskDependency{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return dependency, nil
+}
+
+func (r *TaskDependencyRepository) Remove(ctx context.Context, blockerID int64, blockedID int64) error {
+	query := "DELETE FROM task_dependencies WHERE blocker_id = ? AND blocked_id = ?"
+	result, err := r.db.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to remove task dependency: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.NewNotFoundError("task dependency", 0)
+	}
+
+	return nil
+}
+
+func (r *TaskDependencyRepository) GetBlockers(ctx context.Context, taskID int64, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("t.team_id", teamIDs)
+	query := `SELECT ` + qualifiedTaskColumns("t") + `
+              FROM tasks t JOIN task_dependencies d ON d.blocker_id = t.id
+              WHERE d.blocked_id = ? AND ` + condition + ` ORDER BY t.id`
+
+	return scanTasks(r.db.QueryContext(ctx, query, append([]interface{}{taskID}, args...)...))
+}
+
+func (r *TaskDependencyRepository) GetBlockerStates(ctx context.Context, taskID int64) ([]entities.Task, error) {
+	query := `SELECT t.id, t.completed FROM tasks t JOIN task_dependencies d ON d.blocker_id = t.id
+              WHERE d.blocked_id = ? ORDER BY t.id`
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blockers: %w", err)
+	}
+	defer rows.Close()
+
+	var blockers []entities.Task
+	for rows.Next() {
+		var blocker entities.Task
+		if err := rows.Scan(&blocker.ID, &blocker.Completed); err != nil {
+			return nil, fmt.Errorf("failed to scan blocker: %w", err)
+		}
+		blockers = append(blockers, blocker)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blockers: %w", err)
+	}
+
+	return blockers, nil
+}
+
+func (r *TaskDependencyRepository) GetBlocked(ctx context.Context, taskID int64, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("t.team_id", teamIDs)
+	query := `SELECT ` + qualifiedTaskColumns("t") + `
+              FROM tasks t JOIN task_dependencies d ON d.blocked_id = t.id
+              WHERE d.blocker_id = ? AND ` + condition + ` ORDER BY t.id`
+
+	return scanTasks(r.db.QueryContext(ctx, query, append([]interface{}{taskID}, args...)...))
+}
+
+func (r *TaskDependencyRepository) GetAll(ctx context.Context, teamIDs []int64) ([]entities.TaskDependency, error) {
+	blockerCondition, blockerArgs := teamFilter("blocker.team_id", teamIDs)
+	blockedCondition, blockedArgs := teamFilter("blocked.team_id", teamIDs)
+	query := `SELECT d.blocker_id, d.blocked_id, d.created_at
+              FROM task_dependencies d
+              JOIN tasks blocker ON blocker.id = d.blocker_id
+              JOIN tasks blocked ON blocked.id = d.blocked_id
+              WHERE ` + blockerCondition + ` AND ` + blockedCondition + ` ORDER BY d.blocker_id, d.blocked_id`
+
+	return scanDependencies(r.db.QueryContext(ctx, query, append(blockerArgs, blockedArgs...)...))
+}
+
+// scanDependencies reads the dependencies of a query selecting blocker_id, blocked_id and created_at, closing its rows
+func scanDependencies(rows *sql.Rows, err error) ([]entities.TaskDependency, error) {
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	dependencies := []entities.TaskDependency{}
+	for rows.Next() {
+		var dependency entities.TaskDependency
+		if err := rows.Scan(&dependency.BlockerID, &dependency.BlockedID, &dependency.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task dependencies: %w", err)
+	}
+
+	return dependencies, nil
+}
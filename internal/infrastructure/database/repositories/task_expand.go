@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// expandedTaskSelect returns the SELECT ... FROM clause reading tasks aliased t,
+// LEFT JOINed with the related tables requested by expansion, and the arguments of its placeholders.
+// Workflows, types and parents outside teamIDs are not joined, leaving only their ID set.
+func expandedTaskSelect(expansion domain.TaskExpansion, teamIDs []int64) (string, []interface{}) {
+	columns := []string{qualifiedTaskColumns("t")}
+	var joins []string
+	var args []interface{}
+
+	if expansion.Status {
+		columns = append(columns, "s.label", "s.active", "s.version")
+		joins = append(joins, "LEFT JOIN task_statuses s ON s.id = t.status_id")
+	}
+	if expansion.Type {
+		condition, teamArgs := teamFilter("ty.team_id", teamIDs)
+		columns = append(columns, "ty.name", "ty.team_id", "ty.version")
+		joins = append(joins, "LEFT JOIN task_types ty ON ty.id = t.type_id AND (ty.team_id IS NULL OR "+condition+")")
+		args = append(args, teamArgs...)
+	}
+	if expansion.Workflow {
+		condition, teamArgs := teamFilter("w.team_id", teamIDs)
+		columns = append(columns, "w.name", "w.statuses", "w.sequential", "w.graph", "w.guards", "w.actions", "w.author_id", "w.team_id", "w.created_at", "w.version")
+		joins = append(joins, "LEFT JOIN workflows w ON w.id = t.workflow_id AND "+condition)
+		args = append(args, teamArgs...)
+	}
+	if expansion.Parent {
+		condition, teamArgs := teamFilter("p.team_id", teamIDs)
+		columns = append(columns, "p.title", "p.description", "p.status_id", "p.deadline", "p.completed")
+		joins = append(joins, "LEFT JOIN tasks p ON p.id = t.parent_id AND "+condition)
+		args = append(args, teamArgs...)
+	}
+	if expansion.Author {
+		columns = append(columns, "a.name", "a.username", "a.email", "a.role", "a.active", "a.created_at")
+		joins = append(joins, "LEFT JOIN users a ON a.id = t.author_id")
+	}
+	if expansion.Responsible {
+		columns = append(columns, "r.name", "r.username", "r.email", "r.role", "r.active", "r.created_at")
+		joins = append(joins, "LEFT JOIN users r ON r.id = t.responsible_id")
+	}
+
+	return "SELECT " + strings.Join(columns, ", ") + " FROM tasks t " + strings.Join(joins, " "), args
+}
+
+// scanExpandedTask scans a row selected by expandedTaskSelect with the same expansion.
+// Relations whose row is missing are left with only their ID set.
+func scanExpandedTask(row rowScanner, expansion domain.TaskExpansion) (entities.Task, error) {
+	var task entities.Task
+	var parentID, responsibleID sql.NullInt64
+	var statusID, workflowID, typeID int64
+
+	dest := []interface{}{
+		&task.ID, &task.Title, &task.Description, &statusID, &parentID, &task.AuthorID,
+		&task.Deadline, &task.CreatedAt, &task.UpdatedAt, &responsibleID,
+		&workflowID, &typeID, &task.TeamID, &task.Completed, &task.Version,
+	}
+
+	var statusLabel sql.NullString
+	var statusActive sql.NullBool
+	var statusVersion sql.NullInt64
+	if expansion.Status {
+		dest = append(dest, &statusLabel, &statusActive, &statusVersion)
+	}
+
+	var typeName sql.NullString
+	var typeTeamID, typeVersion sql.NullInt64
+	if expansion.Type {
+		dest = append(dest, &typeName, &typeTeamID, &typeVersion)
+	}
+
+	var workflowName sql.NullString
+	var workflowStatuses, workflowGraph, workflowGuards, workflowActions []byte
+	var workflowSequential sql.NullBool
+	var workflowAuthorID, workflowTeamID, workflowVersion sql.NullInt64
+	var workflowCreatedAt entities.DateTime
+	if expansion.Workflow {
+		dest = append(dest, &workflowName, &workflowStatuses, &workflowSequential, &workflowGraph, &workflowGuards, &workflowActions, &workflowAuthorID, &workflowTeamID, &workflowCreatedAt, &workflowVersion)
+	}
+
+	var parentTitle, parentDescription sql.NullString
+	var parentStatusID sql.NullInt64
+	var parentDeadline entities.DateTime
+	var parentCompleted sql.NullBool
+	if expansion.Parent {
+		dest = append(dest, &parentTitle, &parentDescription, &parentStatusID, &parentDeadline, &parentCompleted)
+	}
+
+	var author, responsible joinedUser
+	if expansion.Author {
+		dest = append(dest, author.dest()...)
+	}
+	if expansion.Responsible {
+		dest = append(dest, responsible.dest()...)
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return entities.Task{}, err
+	}
+
+	task.ResponsibleID = responsibleID.Int64
+	task.Status = entities.TaskStatus{ID: statusID}
+	task.Workflow = entities.Workflow{ID: workflowID}
+	task.Type = entities.TaskType{ID: typeID}
+	if parentID.Valid {
+		task.Parent = &entities.Task{ID: parentID.Int64}
+	}
+
+	if statusLabel.Valid {
+		task.Status.Label = statusLabel.String
+		task.Status.Active = statusActive.Bool
+		task.Status.Version = statusVersion.Int64
+	}
+	if typeName.Valid {
+		task.Type.Name = typeName.String
+		task.Type.TeamID = typeTeamID.Int64
+		task.Type.Version = typeVersion.Int64
+	}
+	if workflowName.Valid {
+		task.Workflow.Name = workflowName.String
+		task.Workflow.Sequential = workflowSequential.Bool
+		task.Workflow.Author = entities.User{ID: workflowAuthorID.Int64}
+		task.Workflow.TeamID = workflowTeamID.Int64
+		task.Workflow.CreatedAt = workflowCreatedAt
+		task.Workflow.Version = workflowVersion.Int64
+		if err := json.Unmarshal(workflowStatuses, &task.Workflow.Statuses); err != nil {
+			return entities.Task{}, fmt.Errorf("failed to unmarshal statuses: %w", err)
+		}
+		if err := scanWorkflowOptionalColumns(&task.Workflow, workflowGraph, workflowGuards, workflowActions); err != nil {
+			return entities.Task{}, err
+		}
+	}
+	if task.Parent != nil && parentTitle.Valid {
+		task.Parent.Title = parentTitle.String
+		task.Parent.Description = parentDescription.String
+		task.Parent.Status = entities.TaskStatus{ID: parentStatusID.Int64}
+		task.Parent.Deadline = parentDeadline
+		task.Parent.Completed = parentCompleted.Bool
+	}
+	task.Author = author.user(task.AuthorID)
+	task.Responsible = responsible.user(task.ResponsibleID)
+
+	return task, nil
+}
+
+// joinedUser holds the columns of a LEFT JOINed users row, all NULL when no user matched
+type joinedUser struct {
+	name, username, email, role sql.NullString
+	active                      sql.NullBool
+	createdAt                   entities.DateTime
+}
+
+func (self *joinedUser) dest() []interface{} {
+	return []interface{}{&self.name, &self.username, &self.email, &self.role, &self.active, &self.createdAt}
+}
+
+// user returns the joined user, or nil when no row matched
+func (self *joinedUser) user(id int64) *entities.User {
+	if !self.username.Valid {
+		return nil
+	}
+	return &entities.User{
+		ID:        id,
+		Name:      self.name.String,
+		Username:  self.username.String,
+		Email:     self.email.String,
+		Role:      entities.Role(self.role.String),
+		Active:    self.active.Bool,
+		CreatedAt: self.createdAt,
+	}
+}
@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 	"todo-api/internal/domain"
 	"todo-api/internal/domain/entities"
@@ -16,22 +18,37 @@ func NewTaskRepository(db *sql.DB) domain.TaskRepository {
 	return &TaskRepository{db: db}
 }
 
-func (self *TaskRepository) Create(task entities.Task) (entities.Task, error) {
+const taskColumns = `id, title, description, status_id, parent_id, author_id, deadline, 
+              created_at, updated_at, responsible_id, workflow_id, type_id, team_id, completed, version`
+
+// qualifiedTaskColumns prefixes every column of taskColumns with the table alias
+func qualifiedTaskColumns(alias string) string {
+	columns := strings.Split(taskColumns, ",")
+	for i, column := range columns {
+		columns[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(columns, ", ")
+}
+
+func (self *TaskRepository) Create(ctx context.Context, task entities.Task) (entities.Task, error) {
 	query := `INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, 
-              created_at, updated_at, responsible_id, workflow_id, type_id, completed) 
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+              created_at, updated_at, responsible_id, workflow_id, type_id, team_id, completed) 
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	var parentID *int64
 	if task.Parent != nil {
 		parentID = &task.Parent.ID
 	}
 
-	result, err := self.db.Exec(query,
+	result, err := self.db.ExecContext(ctx, query,
 		task.Title, task.Description, task.Status.ID, parentID, task.AuthorID,
-		task.Deadline, task.CreatedAt, task.UpdatedAt, task.ResponsibleID,
-		task.Workflow.ID, task.Type.ID, task.Completed,
+		task.Deadline, task.CreatedAt, task.UpdatedAt, nullableID(task.ResponsibleID),
+		task.Workflow.ID, task.Type.ID, task.TeamID, task.Completed,
 	)
 	if err != nil {
+		if violation, ok := foreignKeyViolation(err); ok {
+			return entities.Task{}, violation
+		}
 		return entities.Task{}, fmt.Errorf("failed to create task: %w", err)
 	}
 
@@ -41,114 +58,508 @@ func (self *TaskRepository) Create(task entities.Task) (entities.Task, error) {
 	}
 
 	task.ID = id
+	task.Version = 1
 	return task, nil
 }
 
-func (self *TaskRepository) GetByID(id int64) (entities.Task, error) {
-	query := `SELECT id, title, description, status_id, parent_id, author_id, deadline, 
-              created_at, updated_at, responsible_id, workflow_id, type_id, completed 
-              FROM tasks WHERE id = ?`
+func (self *TaskRepository) GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.Task, error) {
+	return self.GetExpanded(ctx, id, domain.TaskExpansion{}, teamIDs)
+}
 
-	var task entities.Task
-	var parentID sql.NullInt64
-	var statusID, workflowID, typeID int64
+// GetExpanded returns a task with the related entities requested by expansion loaded in the same query
+func (self *TaskRepository) GetExpanded(ctx context.Context, id int64, expansion domain.TaskExpansion, teamIDs []int64) (entities.Task, error) {
+	query, args := expandedTaskSelect(expansion, teamIDs)
+	condition, teamArgs := teamFilter("t.team_id", teamIDs)
+	query += ` WHERE t.id = ? AND ` + condition
+	args = append(append(args, id), teamArgs...)
 
-	err := self.db.QueryRow(query, id).Scan(
-		&task.ID, &task.Title, &task.Description, &statusID, &parentID, &task.AuthorID,
-		&task.Deadline, &task.CreatedAt, &task.UpdatedAt, &task.ResponsibleID,
-		&workflowID, &typeID, &task.Completed,
-	)
+	task, err := scanExpandedTask(self.db.QueryRowContext(ctx, query, args...), expansion)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return entities.Task{}, fmt.Errorf("task not found")
+			return entities.Task{}, domain.NewNotFoundError("task", id)
 		}
 		return entities.Task{}, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	if parentID.Valid {
-		task.Parent = &entities.Task{ID: parentID.Int64}
-	}
-
-	task.Status = entities.TaskStatus{ID: statusID}
-	task.Workflow = entities.Workflow{ID: workflowID}
-	task.Type = entities.TaskType{ID: typeID}
-
 	return task, nil
 }
 
-func (self *TaskRepository) Update(task entities.Task) (entities.Task, error) {
-	query := `UPDATE tasks SET title = ?, description = ?, status_id = ?, parent_id = ?, 
-              deadline = ?, updated_at = ?, responsible_id = ?, workflow_id = ?, type_id = ?, completed = ? 
-              WHERE id = ?`
+// Update stores the task and an event for every field it changes, in one transaction.
+// The task must hold the stored version, or none.
+func (self *TaskRepository) Update(ctx context.Context, task entities.Task, actorID int64, teamIDs []int64) (entities.Task, error) {
+	tx, err := self.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.Task{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	condition, args := teamFilter("t.team_id", teamIDs)
+	query, _ := expandedTaskSelect(domain.TaskExpansion{}, nil)
+	query += ` WHERE t.id = ? AND ` + condition
+	if isMySQL(self.db) {
+		query += ` FOR UPDATE`
+	}
+	current, err := scanExpandedTask(tx.QueryRowContext(ctx, query, append([]interface{}{task.ID}, args...)...), domain.TaskExpansion{})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.Task{}, domain.NewNotFoundError("task", task.ID)
+		}
+		return entities.Task{}, fmt.Errorf("failed to get task: %w", err)
+	}
+	if err := checkVersion(task.Version, current.Version); err != nil {
+		return entities.Task{}, err
+	}
+	if task.Parent != nil {
+		if err := checkAncestors(ctx, tx, task.ID, task.Parent.ID, isMySQL(self.db)); err != nil {
+			return entities.Task{}, err
+		}
+	}
+
+	query = `UPDATE tasks SET title = ?, description = ?, status_id = ?, parent_id = ?, 
+              deadline = ?, updated_at = ?, responsible_id = ?, workflow_id = ?, type_id = ?, team_id = ?, completed = ?, 
+              version = ? WHERE id = ? AND version = ?`
 
 	var parentID *int64
 	if task.Parent != nil {
 		parentID = &task.Parent.ID
 	}
 
-	_, err := self.db.Exec(query,
+	result, err := tx.ExecContext(ctx, query,
 		task.Title, task.Description, task.Status.ID, parentID,
-		task.Deadline, time.Now(), task.ResponsibleID, task.Workflow.ID, task.Type.ID,
-		task.Completed, task.ID,
+		task.Deadline, time.Now(), nullableID(task.ResponsibleID), task.Workflow.ID, task.Type.ID,
+		task.TeamID, task.Completed, current.Version+1, task.ID, current.Version,
 	)
 	if err != nil {
+		if violation, ok := foreignKeyViolation(err); ok {
+			return entities.Task{}, violation
+		}
 		return entities.Task{}, fmt.Errorf("failed to update task: %w", err)
 	}
+	if err := versionWritten(result); err != nil {
+		return entities.Task{}, err
+	}
 
+	for _, event := range current.Changes(task, actorID) {
+		if err := insertTaskEvent(ctx, tx, event); err != nil {
+			return entities.Task{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.Task{}, fmt.Errorf("failed to commit task update: %w", err)
+	}
+
+	task.Version = current.Version + 1
 	return task, nil
 }
 
-func (self *TaskRepository) GetAll() ([]entities.Task, error) {
-	query := `SELECT id, title, description, status_id, parent_id, author_id, deadline, 
-              created_at, updated_at, responsible_id, workflow_id, type_id, completed 
-              FROM tasks`
+// checkAncestors walks up from the new parent of a task, locking every ancestor on MySQL so that no
+// concurrent move can close a loop, and rejects the parent when the task is among them.
+// Ancestors of other teams count too, a loop through them is a loop all the same.
+func checkAncestors(ctx context.Context, tx *sql.Tx, taskID int64, parentID int64, lock bool) error {
+	query := `SELECT parent_id FROM tasks WHERE id = ?`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+
+	visited := map[int64]bool{}
+	for id := parentID; !visited[id]; {
+		if id == taskID {
+			return &domain.InvalidReferenceError{
+				Field:   "parent",
+				ID:      parentID,
+				Message: fmt.Sprintf("task %d is a subtask of task %d, it cannot become its parent", parentID, taskID),
+			}
+		}
+		visited[id] = true
+
+		var next sql.NullInt64
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&next); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return fmt.Errorf("failed to get the parent of task %d: %w", id, err)
+		}
+		if !next.Valid {
+			return nil
+		}
+		id = next.Int64
+	}
+	return nil
+}
+
+func insertTaskEvent(ctx context.Context, tx *sql.Tx, event entities.TaskEvent) error {
+	query := `INSERT INTO task_events (task_id, actor_id, field, old_value, new_value, created_at) 
+              VALUES (?, ?, ?, ?, ?, ?)`
+
+	var actorID *int64
+	if event.ActorID != 0 {
+		actorID = &event.ActorID
+	}
+
+	if _, err := tx.ExecContext(ctx, query, event.TaskID, actorID, event.Field, event.OldValue, event.NewValue, event.CreatedAt); err != nil {
+		return fmt.Errorf("failed to record task event: %w", err)
+	}
+	return nil
+}
+
+// GetHistory returns the field changes of a task, oldest first
+func (self *TaskRepository) GetHistory(ctx context.Context, id int64, teamIDs []int64) ([]entities.TaskEvent, error) {
+	condition, args := teamFilter("t.team_id", teamIDs)
+	query := `SELECT e.id, e.task_id, e.actor_id, e.field, e.old_value, e.new_value, e.created_at 
+              FROM task_events e JOIN tasks t ON t.id = e.task_id 
+              WHERE e.task_id = ? AND ` + condition + ` ORDER BY e.created_at, e.id`
+
+	rows, err := self.db.QueryContext(ctx, query, append([]interface{}{id}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+	defer rows.Close()
+
+	events := []entities.TaskEvent{}
+	for rows.Next() {
+		var event entities.TaskEvent
+		var actorID sql.NullInt64
+		var oldValue, newValue sql.NullString
+		err := rows.Scan(&event.ID, &event.TaskID, &actorID, &event.Field, &oldValue, &newValue, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task event: %w", err)
+		}
+		event.ActorID = actorID.Int64
+		if oldValue.Valid {
+			event.OldValue = &oldValue.String
+		}
+		if newValue.Valid {
+			event.NewValue = &newValue.String
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task history: %w", err)
+	}
+
+	return events, nil
+}
+
+func (self *TaskRepository) GetAll(ctx context.Context, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `SELECT ` + taskColumns + ` 
+              FROM tasks WHERE ` + condition
+
+	return scanTasks(self.db.QueryContext(ctx, query, args...))
+}
+
+func (self *TaskRepository) Find(ctx context.Context, query domain.TaskQuery, teamIDs []int64) (domain.TaskPage, error) {
+	condition, args := teamFilter("t.team_id", teamIDs)
+	conditions := []string{condition}
+
+	if query.StatusID != 0 {
+		conditions = append(conditions, "t.status_id = ?")
+		args = append(args, query.StatusID)
+	}
+	if query.TypeID != 0 {
+		conditions = append(conditions, "t.type_id = ?")
+		args = append(args, query.TypeID)
+	}
+	if query.WorkflowID != 0 {
+		conditions = append(conditions, "t.workflow_id = ?")
+		args = append(args, query.WorkflowID)
+	}
+	if query.ResponsibleID != 0 {
+		conditions = append(conditions, "t.responsible_id = ?")
+		args = append(args, query.ResponsibleID)
+	}
+	if query.AuthorID != 0 {
+		conditions = append(conditions, "t.author_id = ?")
+		args = append(args, query.AuthorID)
+	}
+	if query.Completed != nil {
+		conditions = append(conditions, "t.completed = ?")
+		args = append(args, *query.Completed)
+	}
+	if query.DeadlineFrom != nil {
+		conditions = append(conditions, "t.deadline >= ?")
+		args = append(args, entities.NewDateTime(*query.DeadlineFrom))
+	}
+	if query.DeadlineTo != nil {
+		conditions = append(conditions, "t.deadline <= ?")
+		args = append(args, entities.NewDateTime(*query.DeadlineTo))
+	}
+
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := self.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks t WHERE "+where, args...).Scan(&total); err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	// Sort columns come from a fixed list, never from the request, and id keeps pages stable
+	column, ok := domain.TaskSortFields[query.Sort]
+	if !ok {
+		column = "id"
+	}
+	column = "t." + column
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+
+	selectQuery, selectArgs := expandedTaskSelect(query.Expand, teamIDs)
+	selectQuery += ` 
+              WHERE ` + where + ` 
+              ORDER BY ` + column + ` ` + direction + `, t.id ASC LIMIT ? OFFSET ?`
+	selectArgs = append(append(selectArgs, args...), query.Limit, query.Offset)
+
+	rows, err := self.db.QueryContext(ctx, selectQuery, selectArgs...)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []entities.Task{}
+	for rows.Next() {
+		task, err := scanExpandedTask(rows, query.Expand)
+		if err != nil {
+			return domain.TaskPage{}, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return domain.TaskPage{}, fmt.Errorf("error iterating tasks: %w", err)
+	}
+
+	return domain.TaskPage{Tasks: tasks, Total: total}, nil
+}
+
+// Search ranks the tasks whose title or description match every word of the query, best match first.
+// Words match as prefixes on both databases: MySQL relies on the FULLTEXT index of tasks in boolean mode,
+// SQLite on the tasks_fts FTS5 table.
+func (self *TaskRepository) Search(ctx context.Context, query string, limit int, teamIDs []int64) ([]domain.TaskSearchResult, error) {
+	terms := domain.SearchTerms(query)
+	if len(terms) == 0 {
+		return []domain.TaskSearchResult{}, nil
+	}
+
+	condition, args := teamFilter("t.team_id", teamIDs)
+	columns := qualifiedTaskColumns("t")
+
+	var searchQuery string
+	if isMySQL(self.db) {
+		match := "MATCH(t.title, t.description) AGAINST (? IN BOOLEAN MODE)"
+		searchQuery = `SELECT ` + columns + `, ` + match + ` AS score 
+              FROM tasks t WHERE ` + match + ` AND ` + condition + ` 
+              ORDER BY score DESC, t.id ASC LIMIT ?`
+		required := make([]string, len(terms))
+		for i, term := range terms {
+			required[i] = "+" + term + "*"
+		}
+		text := strings.Join(required, " ")
+		args = append([]interface{}{text, text}, args...)
+	} else {
+		// bm25 is lower for better matches, negate it so scores grow with relevance like MySQL's
+		searchQuery = `SELECT ` + columns + `, -bm25(tasks_fts) AS score 
+              FROM tasks_fts JOIN tasks t ON t.id = tasks_fts.rowid 
+              WHERE tasks_fts MATCH ? AND ` + condition + ` 
+              ORDER BY score DESC, t.id ASC LIMIT ?`
+		quoted := make([]string, len(terms))
+		for i, term := range terms {
+			quoted[i] = `"` + term + `"*`
+		}
+		args = append([]interface{}{strings.Join(quoted, " AND ")}, args...)
+	}
+
+	rows, err := self.db.QueryContext(ctx, searchQuery, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+	defer rows.Close()
+
+	results := []domain.TaskSearchResult{}
+	for rows.Next() {
+		var result domain.TaskSearchResult
+		var parentID, responsibleID sql.NullInt64
+		var statusID, workflowID, typeID int64
+		task := &result.Task
+
+		err := rows.Scan(
+			&task.ID, &task.Title, &task.Description, &statusID, &parentID, &task.AuthorID,
+			&task.Deadline, &task.CreatedAt, &task.UpdatedAt, &responsibleID,
+			&workflowID, &typeID, &task.TeamID, &task.Completed, &task.Version, &result.Score,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+
+		if parentID.Valid {
+			task.Parent = &entities.Task{ID: parentID.Int64}
+		}
+
+		task.ResponsibleID = responsibleID.Int64
+		task.Status = entities.TaskStatus{ID: statusID}
+		task.Workflow = entities.Workflow{ID: workflowID}
+		task.Type = entities.TaskType{ID: typeID}
+
+		result.TitleSnippet = domain.Highlight(task.Title, terms)
+		result.Snippet = domain.Snippet(task.Description, terms)
+		results = append(results, result)
+	}
 
-	return self.scanTasks(self.db.Query(query))
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tasks: %w", err)
+	}
+
+	return results, nil
 }
 
-func (self *TaskRepository) GetAllByResponsible(userID int64) ([]entities.Task, error) {
-	query := `SELECT id, title, description, status_id, parent_id, author_id, deadline, 
-              created_at, updated_at, responsible_id, workflow_id, type_id, completed 
-              FROM tasks WHERE responsible_id = ?`
+func (self *TaskRepository) GetAllByResponsible(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `SELECT ` + taskColumns + ` 
+              FROM tasks WHERE responsible_id = ? AND ` + condition
 
-	return self.scanTasks(self.db.Query(query, userID))
+	return scanTasks(self.db.QueryContext(ctx, query, append([]interface{}{userID}, args...)...))
 }
 
-func (self *TaskRepository) GetAllByAuthor(userID int64) ([]entities.Task, error) {
-	query := `SELECT id, title, description, status_id, parent_id, author_id, deadline, 
-              created_at, updated_at, responsible_id, workflow_id, type_id, completed 
-              FROM tasks WHERE author_id = ?`
+func (self *TaskRepository) GetAllByAuthor(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `SELECT ` + taskColumns + ` 
+              FROM tasks WHERE author_id = ? AND ` + condition
 
-	return self.scanTasks(self.db.Query(query, userID))
+	return scanTasks(self.db.QueryContext(ctx, query, append([]interface{}{userID}, args...)...))
 }
 
-func (self *TaskRepository) GetAllByStatus(status entities.TaskStatus) ([]entities.Task, error) {
-	query := `SELECT id, title, description, status_id, parent_id, author_id, deadline, 
-              created_at, updated_at, responsible_id, workflow_id, type_id, completed 
-              FROM tasks WHERE status_id = ?`
+func (self *TaskRepository) GetAllByStatus(ctx context.Context, status entities.TaskStatus, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `SELECT ` + taskColumns + ` 
+              FROM tasks WHERE status_id = ? AND ` + condition
 
-	return self.scanTasks(self.db.Query(query, status.ID))
+	return scanTasks(self.db.QueryContext(ctx, query, append([]interface{}{status.ID}, args...)...))
 }
 
-func (self *TaskRepository) GetAllOverdue() ([]entities.Task, error) {
-	query := `SELECT id, title, description, status_id, parent_id, author_id, deadline, 
-              created_at, updated_at, responsible_id, workflow_id, type_id, completed 
-              FROM tasks WHERE completed = false AND deadline < NOW()`
+func (self *TaskRepository) GetAllByWorkflow(ctx context.Context, workflowID int64, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `SELECT ` + taskColumns + ` 
+              FROM tasks WHERE workflow_id = ? AND ` + condition
 
-	return self.scanTasks(self.db.Query(query))
+	return scanTasks(self.db.QueryContext(ctx, query, append([]interface{}{workflowID}, args...)...))
 }
 
-func (self *TaskRepository) Remove(id int64) error {
-	query := "DELETE FROM tasks WHERE id = ?"
-	_, err := self.db.Exec(query, id)
+func (self *TaskRepository) GetAllByParent(ctx context.Context, parentID int64, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `SELECT ` + taskColumns + ` 
+              FROM tasks WHERE parent_id = ? AND ` + condition
+
+	return scanTasks(self.db.QueryContext(ctx, query, append([]interface{}{parentID}, args...)...))
+}
+
+// GetDescendants walks the subtask tree of a task with a recursive query.
+// UNION drops the rows already found, so that parents looping on themselves end the walk.
+func (self *TaskRepository) GetDescendants(ctx context.Context, id int64, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `WITH RECURSIVE subtree (id) AS (
+                  SELECT id FROM tasks WHERE parent_id = ?
+                  UNION
+                  SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+              )
+              SELECT ` + taskColumns + ` 
+              FROM tasks WHERE id IN (SELECT id FROM subtree) AND ` + condition + ` ORDER BY id`
+
+	return scanTasks(self.db.QueryContext(ctx, query, append([]interface{}{id}, args...)...))
+}
+
+func (self *TaskRepository) GetAllOverdue(ctx context.Context, teamIDs []int64) ([]entities.Task, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := `SELECT ` + taskColumns + ` 
+              FROM tasks WHERE completed = false AND deadline < NOW() AND ` + condition
+
+	return scanTasks(self.db.QueryContext(ctx, query, args...))
+}
+
+// Remove deletes a task in a transaction, after rejecting or promoting its subtasks as policy says.
+// Subtasks left in place are deleted by the cascade of parent_id.
+func (self *TaskRepository) Remove(ctx context.Context, id int64, version int64, policy domain.SubtaskPolicy, actorID int64, teamIDs []int64) error {
+	tx, err := self.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	condition, args := teamFilter("t.team_id", teamIDs)
+	query, _ := expandedTaskSelect(domain.TaskExpansion{}, nil)
+	query += ` WHERE t.id = ? AND ` + condition
+	if isMySQL(self.db) {
+		query += ` FOR UPDATE`
+	}
+	current, err := scanExpandedTask(tx.QueryRowContext(ctx, query, append([]interface{}{id}, args...)...), domain.TaskExpansion{})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.NewNotFoundError("task", id)
+		}
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	if err := checkVersion(version, current.Version); err != nil {
+		return err
+	}
+
+	if policy != domain.SubtasksCascade {
+		query = `SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = ?`
+		if isMySQL(self.db) {
+			query += ` FOR UPDATE`
+		}
+		subtasks, err := scanTasks(tx.QueryContext(ctx, query, id))
+		if err != nil {
+			return err
+		}
+
+		if policy == domain.SubtasksReject && len(subtasks) > 0 {
+			return domain.ErrHasSubtasks
+		}
+		for _, subtask := range subtasks {
+			if err := promoteSubtask(ctx, tx, subtask, current.Parent, actorID); err != nil {
+				return err
+			}
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM tasks WHERE id = ? AND version = ?", id, current.Version)
 	if err != nil {
 		return fmt.Errorf("failed to remove task: %w", err)
 	}
+	if err := versionWritten(result); err != nil {
+		return err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task removal: %w", err)
+	}
+	return nil
+}
+
+// promoteSubtask moves a subtask to a new parent, nil for the top level, and records the change
+func promoteSubtask(ctx context.Context, tx *sql.Tx, subtask entities.Task, parent *entities.Task, actorID int64) error {
+	promoted := subtask
+	promoted.Parent = parent
+	promoted.UpdatedAt = entities.Now()
+
+	var parentID *int64
+	if parent != nil {
+		parentID = &parent.ID
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE tasks SET parent_id = ?, updated_at = ?, version = version + 1 WHERE id = ?", parentID, promoted.UpdatedAt, subtask.ID); err != nil {
+		return fmt.Errorf("failed to promote subtask %d: %w", subtask.ID, err)
+	}
+
+	for _, event := range subtask.Changes(promoted, actorID) {
+		if err := insertTaskEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (self *TaskRepository) scanTasks(rows *sql.Rows, err error) ([]entities.Task, error) {
+// scanTasks reads the tasks of a query selecting the task columns, closing its rows
+func scanTasks(rows *sql.Rows, err error) ([]entities.Task, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tasks: %w", err)
 	}
@@ -157,13 +568,13 @@ func (self *TaskRepository) scanTasks(rows *sql.Rows, err error) ([]entities.Tas
 	var tasks []entities.Task
 	for rows.Next() {
 		var task entities.Task
-		var parentID sql.NullInt64
+		var parentID, responsibleID sql.NullInt64
 		var statusID, workflowID, typeID int64
 
 		err := rows.Scan(
 			&task.ID, &task.Title, &task.Description, &statusID, &parentID, &task.AuthorID,
-			&task.Deadline, &task.CreatedAt, &task.UpdatedAt, &task.ResponsibleID,
-			&workflowID, &typeID, &task.Completed,
+			&task.Deadline, &task.CreatedAt, &task.UpdatedAt, &responsibleID,
+			&workflowID, &typeID, &task.TeamID, &task.Completed, &task.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
@@ -173,6 +584,7 @@ func (self *TaskRepository) scanTasks(rows *sql.Rows, err error) ([]entities.Tas
 			task.Parent = &entities.Task{ID: parentID.Int64}
 		}
 
+		task.ResponsibleID = responsibleID.Int64
 		task.Status = entities.TaskStatus{ID: statusID}
 		task.Workflow = entities.Workflow{ID: workflowID}
 		task.Type = entities.TaskType{ID: typeID}
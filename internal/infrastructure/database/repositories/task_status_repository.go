@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"todo-api/internal/domain"
@@ -15,9 +16,9 @@ func NewTaskStatusRepository(db *sql.DB) domain.TaskStatusRepository {
 	return &TaskStatusRepository{db: db}
 }
 
-func (r *TaskStatusRepository) Create(status entities.TaskStatus) (entities.TaskStatus, error) {
+func (r *TaskStatusRepository) Create(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error) {
 	query := "INSERT INTO task_statuses (label, active) VALUES (?, ?)"
-	result, err := r.db.Exec(query, status.Label, status.Active)
+	result, err := r.db.ExecContext(ctx, query, status.Label, status.Active)
 	if err != nil {
 		return entities.TaskStatus{}, fmt.Errorf("failed to create task status: %w", err)
 	}
@@ -28,17 +29,18 @@ func (r *TaskStatusRepository) Create(status entities.TaskStatus) (entities.Task
 	}
 
 	status.ID = id
+	status.Version = 1
 	return status, nil
 }
 
-func (r *TaskStatusRepository) GetByID(id int64) (entities.TaskStatus, error) {
-	query := "SELECT id, label, active FROM task_statuses WHERE id = ?"
+func (r *TaskStatusRepository) GetByID(ctx context.Context, id int64) (entities.TaskStatus, error) {
+	query := "SELECT id, label, active, version FROM task_statuses WHERE id = ?"
 	var status entities.TaskStatus
 
-	err := r.db.QueryRow(query, id).Scan(&status.ID, &status.Label, &status.Active)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&status.ID, &status.Label, &status.Active, &status.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return entities.TaskStatus{}, fmt.Errorf("task status not found")
+			return entities.TaskStatus{}, domain.NewNotFoundError("task status", id)
 		}
 		return entities.TaskStatus{}, fmt.Errorf("failed to get task status: %w", err)
 	}
@@ -46,29 +48,64 @@ func (r *TaskStatusRepository) GetByID(id int64) (entities.TaskStatus, error) {
 	return status, nil
 }
 
-func (r *TaskStatusRepository) Update(status entities.TaskStatus) (entities.TaskStatus, error) {
-	query := "UPDATE task_statuses SET label = ?, active = ? WHERE id = ?"
-	_, err := r.db.Exec(query, status.Label, status.Active, status.ID)
+// Update stores the status, which must hold the stored version or none
+func (r *TaskStatusRepository) Update(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.TaskStatus{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := lockVersion(ctx, tx, r.db, "task_statuses", status.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.TaskStatus{}, domain.NewNotFoundError("task status", status.ID)
+		}
+		return entities.TaskStatus{}, fmt.Errorf("failed to get task status: %w", err)
+	}
+	if err := checkVersion(status.Version, current); err != nil {
+		return entities.TaskStatus{}, err
+	}
+
+	query := "UPDATE task_statuses SET label = ?, active = ?, version = ? WHERE id = ? AND version = ?"
+	result, err := tx.ExecContext(ctx, query, status.Label, status.Active, current+1, status.ID, current)
 	if err != nil {
 		return entities.TaskStatus{}, fmt.Errorf("failed to update task status: %w", err)
 	}
+	if err := versionWritten(result); err != nil {
+		return entities.TaskStatus{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.TaskStatus{}, fmt.Errorf("failed to commit task status update: %w", err)
+	}
 
+	status.Version = current + 1
 	return status, nil
 }
 
-func (r *TaskStatusRepository) Remove(id int64) error {
-	query := "DELETE FROM task_statuses WHERE id = ?"
-	_, err := r.db.Exec(query, id)
+// Remove deletes the status if it still has the given version, any version when it is 0
+func (r *TaskStatusRepository) Remove(ctx context.Context, id int64, version int64) error {
+	query := "DELETE FROM task_statuses WHERE id = ? AND (? = 0 OR version = ?)"
+	result, err := r.db.ExecContext(ctx, query, id, version, version)
 	if err != nil {
 		return fmt.Errorf("failed to remove task status: %w", err)
 	}
 
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		var exists int64
+		if err := r.db.QueryRowContext(ctx, "SELECT id FROM task_statuses WHERE id = ?", id).Scan(&exists); err == nil {
+			return domain.ErrVersionConflict
+		}
+		return domain.NewNotFoundError("task status", id)
+	}
+
 	return nil
 }
 
-func (r *TaskStatusRepository) GetAll() ([]entities.TaskStatus, error) {
-	query := "SELECT id, label, active FROM task_statuses"
-	rows, err := r.db.Query(query)
+func (r *TaskStatusRepository) GetAll(ctx context.Context) ([]entities.TaskStatus, error) {
+	query := "SELECT id, label, active, version FROM task_statuses"
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all task statuses: %w", err)
 	}
@@ -77,7 +114,7 @@ func (r *TaskStatusRepository) GetAll() ([]entities.TaskStatus, error) {
 	var statuses []entities.TaskStatus
 	for rows.Next() {
 		var status entities.TaskStatus
-		err := rows.Scan(&status.ID, &status.Label, &status.Active)
+		err := rows.Scan(&status.ID, &status.Label, &status.Active, &status.Version)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task status: %w", err)
 		}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"todo-api/internal/domain"
@@ -15,10 +16,13 @@ func NewTaskTypeRepository(db *sql.DB) domain.TaskTypeRepository {
 	return &TaskTypeRepository{db: db}
 }
 
-func (r *TaskTypeRepository) Create(taskType entities.TaskType) (entities.TaskType, error) {
-	query := "INSERT INTO task_types (name) VALUES (?)"
-	result, err := r.db.Exec(query, taskType.Name)
+func (r *TaskTypeRepository) Create(ctx context.Context, taskType entities.TaskType) (entities.TaskType, error) {
+	query := "INSERT INTO task_types (name, team_id) VALUES (?, ?)"
+	result, err := r.db.ExecContext(ctx, query, taskType.Name, nullableTeamID(taskType.TeamID))
 	if err != nil {
+		if isDuplicateEntry(err, "name") {
+			return entities.TaskType{}, domain.ErrTaskTypeNameTaken
+		}
 		return entities.TaskType{}, fmt.Errorf("failed to create task type: %w", err)
 	}
 
@@ -28,47 +32,90 @@ func (r *TaskTypeRepository) Create(taskType entities.TaskType) (entities.TaskTy
 	}
 
 	taskType.ID = id
+	taskType.Version = 1
 	return taskType, nil
 }
 
-func (r *TaskTypeRepository) GetByID(id int64) (entities.TaskType, error) {
-	query := "SELECT id, name FROM task_types WHERE id = ?"
+func (r *TaskTypeRepository) GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.TaskType, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := "SELECT id, name, team_id, version FROM task_types WHERE id = ? AND (team_id IS NULL OR " + condition + ")"
 	var taskType entities.TaskType
+	var teamID sql.NullInt64
 
-	err := r.db.QueryRow(query, id).Scan(&taskType.ID, &taskType.Name)
+	err := r.db.QueryRowContext(ctx, query, append([]interface{}{id}, args...)...).Scan(&taskType.ID, &taskType.Name, &teamID, &taskType.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return entities.TaskType{}, fmt.Errorf("task type not found")
+			return entities.TaskType{}, domain.NewNotFoundError("task type", id)
 		}
 		return entities.TaskType{}, fmt.Errorf("failed to get task type: %w", err)
 	}
 
+	taskType.TeamID = teamID.Int64
 	return taskType, nil
 }
 
-func (r *TaskTypeRepository) Update(taskType entities.TaskType) (entities.TaskType, error) {
-	query := "UPDATE task_types SET name = ? WHERE id = ?"
-	_, err := r.db.Exec(query, taskType.Name, taskType.ID)
+// Update stores the task type, which must hold the stored version or none
+func (r *TaskTypeRepository) Update(ctx context.Context, taskType entities.TaskType) (entities.TaskType, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.TaskType{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := lockVersion(ctx, tx, r.db, "task_types", taskType.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.TaskType{}, domain.NewNotFoundError("task type", taskType.ID)
+		}
+		return entities.TaskType{}, fmt.Errorf("failed to get task type: %w", err)
+	}
+	if err := checkVersion(taskType.Version, current); err != nil {
+		return entities.TaskType{}, err
+	}
+
+	query := "UPDATE task_types SET name = ?, team_id = ?, version = ? WHERE id = ? AND version = ?"
+	result, err := tx.ExecContext(ctx, query, taskType.Name, nullableTeamID(taskType.TeamID), current+1, taskType.ID, current)
 	if err != nil {
+		if isDuplicateEntry(err, "name") {
+			return entities.TaskType{}, domain.ErrTaskTypeNameTaken
+		}
 		return entities.TaskType{}, fmt.Errorf("failed to update task type: %w", err)
 	}
+	if err := versionWritten(result); err != nil {
+		return entities.TaskType{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.TaskType{}, fmt.Errorf("failed to commit task type update: %w", err)
+	}
 
+	taskType.Version = current + 1
 	return taskType, nil
 }
 
-func (r *TaskTypeRepository) Remove(id int64) error {
-	query := "DELETE FROM task_types WHERE id = ?"
-	_, err := r.db.Exec(query, id)
+// Remove deletes the task type if it still has the given version, any version when it is 0
+func (r *TaskTypeRepository) Remove(ctx context.Context, id int64, version int64) error {
+	query := "DELETE FROM task_types WHERE id = ? AND (? = 0 OR version = ?)"
+	result, err := r.db.ExecContext(ctx, query, id, version, version)
 	if err != nil {
 		return fmt.Errorf("failed to remove task type: %w", err)
 	}
 
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		var exists int64
+		if err := r.db.QueryRowContext(ctx, "SELECT id FROM task_types WHERE id = ?", id).Scan(&exists); err == nil {
+			return domain.ErrVersionConflict
+		}
+		return domain.NewNotFoundError("task type", id)
+	}
+
 	return nil
 }
 
-func (r *TaskTypeRepository) GetAll() ([]entities.TaskType, error) {
-	query := "SELECT id, name FROM task_types"
-	rows, err := r.db.Query(query)
+func (r *TaskTypeRepository) GetAll(ctx context.Context, teamIDs []int64) ([]entities.TaskType, error) {
+	condition, args := teamFilter("team_id", teamIDs)
+	query := "SELECT id, name, team_id, version FROM task_types WHERE team_id IS NULL OR " + condition
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all task types: %w", err)
 	}
@@ -77,10 +124,12 @@ func (r *TaskTypeRepository) GetAll() ([]entities.TaskType, error) {
 	var taskTypes []entities.TaskType
 	for rows.Next() {
 		var taskType entities.TaskType
-		err := rows.Scan(&taskType.ID, &taskType.Name)
+		var teamID sql.NullInt64
+		err := rows.Scan(&taskType.ID, &taskType.Name, &teamID, &taskType.Version)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task type: %w", err)
 		}
+		taskType.TeamID = teamID.Int64
 		taskTypes = append(taskTypes, taskType)
 	}
 
@@ -90,3 +139,8 @@ func (r *TaskTypeRepository) GetAll() ([]entities.TaskType, error) {
 
 	return taskTypes, nil
 }
+
+// nullableTeamID stores shared task types with a NULL team_id
+func nullableTeamID(teamID int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: teamID, Valid: teamID != 0}
+}
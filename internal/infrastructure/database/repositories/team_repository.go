@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+type TeamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) domain.TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team entities.Team) (entities.Team, error) {
+	query := "INSERT INTO teams (name, created_at) VALUES (?, ?)"
+	result, err := r.db.ExecContext(ctx, query, team.Name, team.CreatedAt)
+	if err != nil {
+		if isDuplicateEntry(err, "name") {
+			return entities.Team{}, domain.ErrTeamNameTaken
+		}
+		return entities.Team{}, fmt.Errorf("failed to create team: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return entities.Team{}, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	team.ID = id
+	return team, nil
+}
+
+func (r *TeamRepository) GetByID(ctx context.Context, id int64) (entities.Team, error) {
+	query := "SELECT id, name, created_at FROM teams WHERE id = ?"
+	var team entities.Team
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&team.ID, &team.Name, &team.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.Team{}, domain.NewNotFoundError("team", id)
+		}
+		return entities.Team{}, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	return team, nil
+}
+
+func (r *TeamRepository) Update(ctx context.Context, team entities.Team) (entities.Team, error) {
+	query := "UPDATE teams SET name = ? WHERE id = ?"
+	_, err := r.db.ExecContext(ctx, query, team.Name, team.ID)
+	if err != nil {
+		if isDuplicateEntry(err, "name") {
+			return entities.Team{}, domain.ErrTeamNameTaken
+		}
+		return entities.Team{}, fmt.Errorf("failed to update team: %w", err)
+	}
+
+	return team, nil
+}
+
+func (r *TeamRepository) Remove(ctx context.Context, id int64) error {
+	query := "DELETE FROM teams WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to remove team: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.NewNotFoundError("team", id)
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) GetAll(ctx context.Context) ([]entities.Team, error) {
+	query := "SELECT id, name, created_at FROM teams"
+	return r.scanTeams(r.db.QueryContext(ctx, query))
+}
+
+func (r *TeamRepository) GetAllByMember(ctx context.Context, userID int64) ([]entities.Team, error) {
+	query := `SELECT t.id, t.name, t.created_at 
+              FROM teams t 
+              JOIN team_members m ON m.team_id = t.id 
+              WHERE m.user_id = ?`
+	return r.scanTeams(r.db.QueryContext(ctx, query, userID))
+}
+
+func (r *TeamRepository) GetTeamIDsByMember(ctx context.Context, userID int64) ([]int64, error) {
+	query := "SELECT team_id FROM team_members WHERE user_id = ?"
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team ids: %w", err)
+	}
+	defer rows.Close()
+
+	var teamIDs []int64
+	for rows.Next() {
+		var teamID int64
+		if err := rows.Scan(&teamID); err != nil {
+			return nil, fmt.Errorf("failed to scan team id: %w", err)
+		}
+		teamIDs = append(teamIDs, teamID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating team ids: %w", err)
+	}
+
+	return teamIDs, nil
+}
+
+func (r *TeamRepository) AddMember(ctx context.Context, teamID int64, userID int64) error {
+	query := "INSERT INTO team_members (team_id, user_id, created_at) VALUES (?, ?, ?)"
+	_, err := r.db.ExecContext(ctx, query, teamID, userID, entities.Now())
+	if err != nil {
+		// MySQL names the violated key PRIMARY, SQLite lists the key columns
+		if isDuplicateEntry(err, "PRIMARY") || isDuplicateEntry(err, "team_id") {
+			return domain.ErrAlreadyTeamMember
+		}
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) RemoveMember(ctx context.Context, teamID int64, userID int64) error {
+	query := "DELETE FROM team_members WHERE team_id = ? AND user_id = ?"
+	result, err := r.db.ExecContext(ctx, query, teamID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove team member: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.NewNotFoundError("team member", userID)
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) GetMembers(ctx context.Context, teamID int64) ([]entities.User, error) {
+	query := `SELECT u.id, u.name, u.username, u.email, u.role, u.active, u.created_at 
+              FROM users u 
+              JOIN team_members m ON m.user_id = u.id 
+              WHERE m.team_id = ?`
+	rows, err := r.db.QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	defer rows.Close()
+
+	var users []entities.User
+	for rows.Next() {
+		var user entities.User
+		err := rows.Scan(&user.ID, &user.Name, &user.Username, &user.Email, &user.Role, &user.Active, &user.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating team members: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *TeamRepository) scanTeams(rows *sql.Rows, err error) ([]entities.Team, error) {
+	if err != nil {
+		return nil, fmt.Errorf("failed to query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []entities.Team
+	for rows.Next() {
+		var team entities.Team
+		if err := rows.Scan(&team.ID, &team.Name, &team.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating teams: %w", err)
+	}
+
+	return teams, nil
+}
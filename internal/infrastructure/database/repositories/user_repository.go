@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) domain.UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user entities.User) (entities.User, error) {
+	query := "INSERT INTO users (name, username, email, password_hash, role, active, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := r.db.ExecContext(ctx, query, user.Name, user.Username, user.Email, user.PasswordHash, user.Role, user.Active, user.CreatedAt)
+	if err != nil {
+		return entities.User{}, r.mapWriteError(err, "create")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return entities.User{}, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	user.ID = id
+	return user, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (entities.User, error) {
+	query := "SELECT id, name, username, email, role, active, created_at FROM users WHERE id = ?"
+	var user entities.User
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Name, &user.Username, &user.Email, &user.Role, &user.Active, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.User{}, domain.NewNotFoundError("user", id)
+		}
+		return entities.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (entities.User, error) {
+	query := "SELECT id, name, username, email, password_hash, role, active, created_at FROM users WHERE username = ?"
+	var user entities.User
+	var passwordHash sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, username).Scan(&user.ID, &user.Name, &user.Username, &user.Email, &passwordHash, &user.Role, &user.Active, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.User{}, domain.NewNotFoundError("user", 0)
+		}
+		return entities.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.PasswordHash = passwordHash.String
+	return user, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user entities.User) (entities.User, error) {
+	query := "UPDATE users SET name = ?, username = ?, email = ?, role = ?, active = ? WHERE id = ?"
+	_, err := r.db.ExecContext(ctx, query, user.Name, user.Username, user.Email, user.Role, user.Active, user.ID)
+	if err != nil {
+		return entities.User{}, r.mapWriteError(err, "update")
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) Deactivate(ctx context.Context, id int64) error {
+	query := "UPDATE users SET active = ? WHERE id = ?"
+	result, err := r.db.ExecContext(ctx, query, false, id)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate user: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domain.NewNotFoundError("user", id)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GetAll(ctx context.Context) ([]entities.User, error) {
+	query := "SELECT id, name, username, email, role, active, created_at FROM users"
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []entities.User
+	for rows.Next() {
+		var user entities.User
+		err := rows.Scan(&user.ID, &user.Name, &user.Username, &user.Email, &user.Role, &user.Active, &user.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+// mapWriteError translates unique constraint violations into domain errors
+func (r *UserRepository) mapWriteError(err error, operation string) error {
+	if isDuplicateEntry(err, "username") {
+		return domain.ErrUsernameTaken
+	}
+	if isDuplicateEntry(err, "email") {
+		return domain.ErrEmailTaken
+	}
+	return fmt.Errorf("failed to %s user: %w", operation, err)
+}
@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 	"todo-api/internal/domain"
 	"todo-api/internal/domain/entities"
 )
@@ -16,14 +19,20 @@ func NewWorkflowRepository(db *sql.DB) domain.WorkflowRepository {
 	return &WorkflowRepository{db: db}
 }
 
-func (r *WorkflowRepository) Create(workflow entities.Workflow) (entities.Workflow, error) {
+func (r *WorkflowRepository) Create(ctx context.Context, workflow entities.Workflow) (entities.Workflow, error) {
 	statusesJSON, err := json.Marshal(workflow.Statuses)
 	if err != nil {
 		return entities.Workflow{}, fmt.Errorf("failed to marshal statuses: %w", err)
 	}
 
-	query := "INSERT INTO workflows (name, statuses, author_id, created_at) VALUES (?, ?, ?, ?)"
-	result, err := r.db.Exec(query, workflow.Name, statusesJSON, workflow.Author.ID, workflow.CreatedAt)
+	graphJSON, guardsJSON, actionsJSON, err := workflowOptionalColumns(workflow)
+	if err != nil {
+		return entities.Workflow{}, err
+	}
+
+	query := "INSERT INTO workflows (name, statuses, sequential, graph, guards, actions, author_id, team_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := r.db.ExecContext(ctx, query, workflow.Name, statusesJSON, workflow.Sequential, graphJSON, guardsJSON, actionsJSON,
+		workflow.Author.ID, workflow.TeamID, workflow.CreatedAt)
 	if err != nil {
 		return entities.Workflow{}, fmt.Errorf("failed to create workflow: %w", err)
 	}
@@ -34,26 +43,41 @@ func (r *WorkflowRepository) Create(workflow entities.Workflow) (entities.Workfl
 	}
 
 	workflow.ID = id
+	workflow.Version = 1
 	return workflow, nil
 }
 
-func (r *WorkflowRepository) GetByID(id int64) (entities.Workflow, error) {
-	query := `SELECT w.id, w.name, w.statuses, w.author_id, w.created_at, u.id, u.name, u.username, u.email 
+func (r *WorkflowRepository) GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.Workflow, error) {
+	return r.getByID(ctx, r.db, id, teamIDs, false)
+}
+
+// queryRower is the part of *sql.DB and *sql.Tx needed to read a single row
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getByID reads a workflow with db or a transaction, lock keeps its row locked until a MySQL transaction ends
+func (r *WorkflowRepository) getByID(ctx context.Context, q queryRower, id int64, teamIDs []int64, lock bool) (entities.Workflow, error) {
+	condition, args := teamFilter("w.team_id", teamIDs)
+	query := `SELECT w.id, w.name, w.statuses, w.sequential, w.graph, w.guards, w.actions, w.author_id, w.team_id, w.created_at, w.version, u.id, u.name, u.username, u.email 
               FROM workflows w 
               JOIN users u ON w.author_id = u.id 
-              WHERE w.id = ?`
+              WHERE w.id = ? AND ` + condition
+	if lock && isMySQL(r.db) {
+		query += ` FOR UPDATE`
+	}
 
 	var workflow entities.Workflow
 	var user entities.User
-	var statusesJSON []byte
+	var statusesJSON, graphJSON, guardsJSON, actionsJSON []byte
 
-	err := r.db.QueryRow(query, id).Scan(
-		&workflow.ID, &workflow.Name, &statusesJSON, &user.ID, &workflow.CreatedAt,
+	err := q.QueryRowContext(ctx, query, append([]interface{}{id}, args...)...).Scan(
+		&workflow.ID, &workflow.Name, &statusesJSON, &workflow.Sequential, &graphJSON, &guardsJSON, &actionsJSON, &user.ID, &workflow.TeamID, &workflow.CreatedAt, &workflow.Version,
 		&user.ID, &user.Name, &user.Username, &user.Email,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return entities.Workflow{}, fmt.Errorf("workflow not found")
+			return entities.Workflow{}, domain.NewNotFoundError("workflow", id)
 		}
 		return entities.Workflow{}, fmt.Errorf("failed to get workflow: %w", err)
 	}
@@ -63,43 +87,291 @@ func (r *WorkflowRepository) GetByID(id int64) (entities.Workflow, error) {
 		return entities.Workflow{}, fmt.Errorf("failed to unmarshal statuses: %w", err)
 	}
 
+	if err := scanWorkflowOptionalColumns(&workflow, graphJSON, guardsJSON, actionsJSON); err != nil {
+		return entities.Workflow{}, err
+	}
+
 	workflow.Author = user
 	workflow.Statuses = statuses
 
 	return workflow, nil
 }
 
-func (r *WorkflowRepository) Update(workflow entities.Workflow) (entities.Workflow, error) {
+// Update stores the workflow and moves its tasks as the migration asks, in one transaction.
+// The migration can only remap statuses: the tasks stay in the workflow. The workflow must hold the stored version, or none.
+func (r *WorkflowRepository) Update(ctx context.Context, workflow entities.Workflow, migration *entities.WorkflowMigration, teamIDs []int64) (entities.Workflow, error) {
 	statusesJSON, err := json.Marshal(workflow.Statuses)
 	if err != nil {
 		return entities.Workflow{}, fmt.Errorf("failed to marshal statuses: %w", err)
 	}
 
-	query := "UPDATE workflows SET name = ?, statuses = ?, author_id = ? WHERE id = ?"
-	_, err = r.db.Exec(query, workflow.Name, statusesJSON, workflow.Author.ID, workflow.ID)
+	graphJSON, guardsJSON, actionsJSON, err := workflowOptionalColumns(workflow)
+	if err != nil {
+		return entities.Workflow{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.Workflow{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := r.getByID(ctx, tx, workflow.ID, teamIDs, true)
+	if err != nil {
+		return entities.Workflow{}, err
+	}
+	if err := checkVersion(workflow.Version, current.Version); err != nil {
+		return entities.Workflow{}, err
+	}
+
+	var remap entities.WorkflowMigration
+	if migration != nil {
+		remap = *migration
+	}
+	remap.SourceWorkflowID = workflow.ID
+	remap.TargetWorkflowID = workflow.ID
+	if _, err := migrateTasks(ctx, tx, remap, &workflow, r.lockClause()); err != nil {
+		return entities.Workflow{}, err
+	}
+
+	query := "UPDATE workflows SET name = ?, statuses = ?, sequential = ?, graph = ?, guards = ?, actions = ?, author_id = ?, team_id = ?, version = ? WHERE id = ? AND version = ?"
+	result, err := tx.ExecContext(ctx, query,
+		workflow.Name, statusesJSON, workflow.Sequential, graphJSON, guardsJSON, actionsJSON, workflow.Author.ID, workflow.TeamID,
+		current.Version+1, workflow.ID, current.Version,
+	)
 	if err != nil {
 		return entities.Workflow{}, fmt.Errorf("failed to update workflow: %w", err)
 	}
+	if err := versionWritten(result); err != nil {
+		return entities.Workflow{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.Workflow{}, fmt.Errorf("failed to commit workflow update: %w", err)
+	}
 
+	workflow.Version = current.Version + 1
 	return workflow, nil
 }
 
-func (r *WorkflowRepository) Remove(id int64) error {
-	query := "DELETE FROM workflows WHERE id = ?"
-	_, err := r.db.Exec(query, id)
+// Remove deletes the workflow after moving its tasks to another workflow as the migration asks, in one transaction
+func (r *WorkflowRepository) Remove(ctx context.Context, id int64, version int64, migration *entities.WorkflowMigration, teamIDs []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := r.getByID(ctx, tx, id, teamIDs, true)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(version, current.Version); err != nil {
+		return err
+	}
+
+	if migration == nil {
+		// No status belongs to an empty workflow, so any task left fails the migration as orphaned
+		if _, err := migrateTasks(ctx, tx, entities.WorkflowMigration{SourceWorkflowID: id}, &entities.Workflow{}, r.lockClause()); err != nil {
+			return err
+		}
+	} else {
+		move := *migration
+		move.SourceWorkflowID = id
+		if move.Target() == id {
+			return fmt.Errorf("%w: the tasks of a removed workflow must move to another workflow", entities.ErrInvalidMigration)
+		}
+		if _, err := r.migrate(ctx, tx, move, teamIDs); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM workflows WHERE id = ? AND version = ?", id, current.Version)
 	if err != nil {
 		return fmt.Errorf("failed to remove workflow: %w", err)
 	}
+	if err := versionWritten(result); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit workflow removal: %w", err)
+	}
+
+	return nil
+}
+
+// Migrate moves the tasks of a workflow in one transaction and returns how many of them changed
+func (r *WorkflowRepository) Migrate(ctx context.Context, migration entities.WorkflowMigration, teamIDs []int64) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := r.getByID(ctx, tx, migration.SourceWorkflowID, teamIDs, true); err != nil {
+		return 0, err
+	}
+
+	moved, err := r.migrate(ctx, tx, migration, teamIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit workflow migration: %w", err)
+	}
+
+	return moved, nil
+}
+
+// migrate loads the target workflow of the migration, which must be visible to the teams, and moves the tasks to it
+func (r *WorkflowRepository) migrate(ctx context.Context, tx *sql.Tx, migration entities.WorkflowMigration, teamIDs []int64) (int64, error) {
+	target, err := r.getByID(ctx, tx, migration.Target(), teamIDs, true)
+	if err != nil {
+		return 0, fmt.Errorf("%w: target %s", entities.ErrInvalidMigration, err)
+	}
+	return migrateTasks(ctx, tx, migration, &target, r.lockClause())
+}
+
+// lockClause returns the suffix locking the rows read in a transaction until it ends, which SQLite does not need
+func (r *WorkflowRepository) lockClause() string {
+	if isMySQL(r.db) {
+		return ` FOR UPDATE`
+	}
+	return ""
+}
+
+// migrateTasks moves the tasks of the source workflow of the migration to the target workflow,
+// recording the changes in their history, and returns how many tasks changed.
+// Nothing is written when some tasks would be left in a status outside the target,
+// or completed while one of their blockers stays open.
+func migrateTasks(ctx context.Context, tx *sql.Tx, migration entities.WorkflowMigration, target *entities.Workflow, lock string) (int64, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, status_id, completed FROM tasks WHERE workflow_id = ?`+lock, migration.SourceWorkflowID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get workflow tasks: %w", err)
+	}
+
+	var tasks []entities.Task
+	for rows.Next() {
+		task := entities.Task{Workflow: entities.Workflow{ID: migration.SourceWorkflowID}}
+		if err := rows.Scan(&task.ID, &task.Status.ID, &task.Completed); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan workflow task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating workflow tasks: %w", err)
+	}
+
+	var used []int64
+	for _, task := range tasks {
+		if !slices.Contains(used, task.Status.ID) {
+			used = append(used, task.Status.ID)
+		}
+	}
+	plan, err := migration.Plan(target, used)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := make([]entities.Task, len(tasks))
+	for i, task := range tasks {
+		migrated[i] = task
+		migrated[i].Workflow.ID = migration.Target()
+		migrated[i].Status.ID = plan[task.Status.ID]
+		migrated[i].Completed = target.IsTerminal(migrated[i].Status.ID)
+	}
+	if err := checkMigratedBlockers(ctx, tx, migration.SourceWorkflowID, tasks, migrated, lock); err != nil {
+		return 0, err
+	}
+
+	var moved int64
+	for i, task := range tasks {
+		migrated := migrated[i]
+		events := task.Changes(migrated, migration.ActorID)
+		if len(events) == 0 {
+			continue
+		}
+
+		_, err := tx.ExecContext(ctx, `UPDATE tasks SET workflow_id = ?, status_id = ?, completed = ?, updated_at = ?, version = version + 1 WHERE id = ?`,
+			migrated.Workflow.ID, migrated.Status.ID, migrated.Completed, time.Now(), task.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to migrate task %d: %w", task.ID, err)
+		}
+		for _, event := range events {
+			if err := insertTaskEvent(ctx, tx, event); err != nil {
+				return 0, err
+			}
+		}
+		moved++
+	}
 
+	return moved, nil
+}
+
+// checkMigratedBlockers returns a *entities.BlockedTaskError when the migration completes a task, of the source
+// workflow, that one of its blockers, whatever its team, keeps open. Blockers migrated along count as migrated.
+func checkMigratedBlockers(ctx context.Context, tx *sql.Tx, sourceWorkflowID int64, tasks []entities.Task, migrated []entities.Task, lock string) error {
+	completed := make(map[int64]bool)
+	completing := make(map[int64]bool)
+	for i, task := range tasks {
+		completed[task.ID] = migrated[i].Completed
+		if migrated[i].Completed && !task.Completed {
+			completing[task.ID] = true
+		}
+	}
+	if len(completing) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT d.blocked_id, d.blocker_id, blocker.completed FROM task_dependencies d
+		JOIN tasks blocked ON blocked.id = d.blocked_id
+		JOIN tasks blocker ON blocker.id = d.blocker_id
+		WHERE blocked.workflow_id = ? ORDER BY d.blocked_id, d.blocker_id`+lock, sourceWorkflowID)
+	if err != nil {
+		return fmt.Errorf("failed to get blockers of workflow tasks: %w", err)
+	}
+	defer rows.Close()
+
+	open := make(map[int64][]int64)
+	var blockedIDs []int64
+	for rows.Next() {
+		var blockedID, blockerID int64
+		var blockerCompleted bool
+		if err := rows.Scan(&blockedID, &blockerID, &blockerCompleted); err != nil {
+			return fmt.Errorf("failed to scan blocker: %w", err)
+		}
+		if migratedCompleted, ok := completed[blockerID]; ok {
+			blockerCompleted = migratedCompleted
+		}
+		if completing[blockedID] && !blockerCompleted {
+			if len(open[blockedID]) == 0 {
+				blockedIDs = append(blockedIDs, blockedID)
+			}
+			open[blockedID] = append(open[blockedID], blockerID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating blockers: %w", err)
+	}
+
+	if len(blockedIDs) > 0 {
+		return fmt.Errorf("migration would complete task %d: %w", blockedIDs[0], &entities.BlockedTaskError{BlockerIDs: open[blockedIDs[0]]})
+	}
 	return nil
 }
 
-func (r *WorkflowRepository) GetAll() ([]entities.Workflow, error) {
-	query := `SELECT w.id, w.name, w.statuses, w.author_id, w.created_at, u.id, u.name, u.username, u.email 
+func (r *WorkflowRepository) GetAll(ctx context.Context, teamIDs []int64) ([]entities.Workflow, error) {
+	condition, args := teamFilter("w.team_id", teamIDs)
+	query := `SELECT w.id, w.name, w.statuses, w.sequential, w.graph, w.guards, w.actions, w.author_id, w.team_id, w.created_at, w.version, u.id, u.name, u.username, u.email 
               FROM workflows w 
-              JOIN users u ON w.author_id = u.id`
+              JOIN users u ON w.author_id = u.id 
+              WHERE ` + condition
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all workflows: %w", err)
 	}
@@ -109,10 +381,10 @@ func (r *WorkflowRepository) GetAll() ([]entities.Workflow, error) {
 	for rows.Next() {
 		var workflow entities.Workflow
 		var user entities.User
-		var statusesJSON []byte
+		var statusesJSON, graphJSON, guardsJSON, actionsJSON []byte
 
 		err := rows.Scan(
-			&workflow.ID, &workflow.Name, &statusesJSON, &user.ID, &workflow.CreatedAt,
+			&workflow.ID, &workflow.Name, &statusesJSON, &workflow.Sequential, &graphJSON, &guardsJSON, &actionsJSON, &user.ID, &workflow.TeamID, &workflow.CreatedAt, &workflow.Version,
 			&user.ID, &user.Name, &user.Username, &user.Email,
 		)
 		if err != nil {
@@ -124,6 +396,10 @@ func (r *WorkflowRepository) GetAll() ([]entities.Workflow, error) {
 			return nil, fmt.Errorf("failed to unmarshal statuses: %w", err)
 		}
 
+		if err := scanWorkflowOptionalColumns(&workflow, graphJSON, guardsJSON, actionsJSON); err != nil {
+			return nil, err
+		}
+
 		workflow.Author = user
 		workflow.Statuses = statuses
 		workflows = append(workflows, workflow)
@@ -135,3 +411,49 @@ func (r *WorkflowRepository) GetAll() ([]entities.Workflow, error) {
 
 	return workflows, nil
 }
+
+// marshalOptional returns the JSON stored in an optional column, NULL when the value is empty
+func marshalOptional(value interface{}, empty bool, name string) (interface{}, error) {
+	if empty {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// unmarshalOptional decodes an optional column into target, which is left untouched when the column is NULL
+func unmarshalOptional(data []byte, target interface{}, name string) error {
+	if data == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", name, err)
+	}
+	return nil
+}
+
+// workflowOptionalColumns returns the values stored in the graph, guards and actions columns
+func workflowOptionalColumns(workflow entities.Workflow) (graph, guards, actions interface{}, err error) {
+	if graph, err = marshalOptional(workflow.Graph, workflow.Graph == nil, "graph"); err != nil {
+		return
+	}
+	if guards, err = marshalOptional(workflow.Guards, len(workflow.Guards) == 0, "guards"); err != nil {
+		return
+	}
+	actions, err = marshalOptional(workflow.Actions, len(workflow.Actions) == 0, "actions")
+	return
+}
+
+// scanWorkflowOptionalColumns decodes the graph, guards and actions columns into the workflow
+func scanWorkflowOptionalColumns(workflow *entities.Workflow, graph, guards, actions []byte) error {
+	if err := unmarshalOptional(graph, &workflow.Graph, "graph"); err != nil {
+		return err
+	}
+	if err := unmarshalOptional(guards, &workflow.Guards, "guards"); err != nil {
+		return err
+	}
+	return unmarshalOptional(actions, &workflow.Actions, "actions")
+}
@@ -0,0 +1,19 @@
+package notifications
+
+import (
+	"log"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+// LogNotifier writes notifications to the application log, until messages are delivered by mail or chat
+type LogNotifier struct{}
+
+func NewLogNotifier() domain.Notifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(userIDs []int64, task entities.Task, message string) error {
+	log.Printf("Notify users %v about task %d: %s", userIDs, task.ID, message)
+	return nil
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api/problems"
+	"todo-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authentication middleware validates the bearer access token of the request
+// and stores the authenticated user ID and role in the context under "user_id" and "user_role".
+// The user is loaded on every request, so that deactivated users lose access before their tokens expire.
+func Authentication(secret []byte, users domain.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || strings.TrimSpace(token) == "" {
+			rejectUnauthenticated(c)
+			return
+		}
+
+		claims, err := utils.ParseToken(strings.TrimSpace(token), secret)
+		if err != nil || claims.Type != utils.AccessTokenType {
+			rejectUnauthenticated(c)
+			return
+		}
+
+		userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+		if err != nil {
+			rejectUnauthenticated(c)
+			return
+		}
+
+		user, err := users.GetByID(c.Request.Context(), userID)
+		if errors.Is(err, domain.ErrNotFound) || (err == nil && !user.Active) {
+			rejectUnauthenticated(c)
+			return
+		}
+		if err != nil {
+			abortWithError(c, fmt.Errorf("failed to load the caller: %w", err))
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("user_role", entities.Role(claims.Role))
+
+		c.Next()
+	}
+}
+
+// rejectUnauthenticated aborts the request with the same 401 problem for every failure reason
+func rejectUnauthenticated(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer realm="todo-api"`)
+	abortWithError(c, problems.ErrUnauthenticated)
+}
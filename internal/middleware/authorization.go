@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errRoleForbidden error = &domain.ForbiddenError{Message: "caller's role is not allowed to make this request"}
+
+// RequireRole middleware only lets through callers whose role, set by Authentication, is one of the given roles
+func RequireRole(roles ...entities.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("user_role")
+		callerRole, _ := value.(entities.Role)
+
+		for _, role := range roles {
+			if callerRole == role {
+				c.Next()
+				return
+			}
+		}
+
+		abortWithError(c, errRoleForbidden)
+	}
+}
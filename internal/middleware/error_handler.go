@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"todo-api/internal/infrastructure/api/problems"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler answers the requests a handler or middleware aborted with an error (c.Error) with the problem
+// the error maps to (see problems.New), as application/problem+json. Responses already written are left alone.
+// It must come before every middleware that can reject a request.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		requestID := c.GetString("request_id")
+		problem := problems.New(err)
+		problem.Instance = c.Request.URL.Path
+		problem.RequestID = requestID
+		if problem.Status == http.StatusInternalServerError {
+			log.Printf("Request %s failed: %v", requestID, err)
+		}
+
+		c.Header("X-Error-Response", "true")
+		c.Header("Cache-Control", "no-cache, no-store, must-revalidate, max-age=0")
+		c.Header("X-Request-ID", requestID)
+		c.Header("Content-Type", problems.ContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// abortWithError aborts the request with the error rejecting it, for ErrorHandler to answer
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
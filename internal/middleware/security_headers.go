@@ -47,7 +47,7 @@ func CORSHeaders() gin.HandlerFunc {
 
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Accept-Language, Content-Language")
 
-		c.Header("Access-Control-Expose-Headers", "Content-Length, Content-Type, X-API-Version")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, Content-Type, X-API-Version, WWW-Authenticate")
 
 		c.Header("Access-Control-Allow-Credentials", "false")
 
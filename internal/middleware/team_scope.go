@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"fmt"
+	"todo-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamScope middleware loads the teams of the authenticated caller and stores their IDs
+// in the context under "team_ids", repositories use them to filter tasks, workflows and task types
+func TeamScope(teams domain.TeamRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teamIDs, err := teams.GetTeamIDsByMember(c.Request.Context(), c.GetInt64("user_id"))
+		if err != nil {
+			abortWithError(c, fmt.Errorf("failed to load the teams of the caller: %w", err))
+			return
+		}
+
+		c.Set("team_ids", teamIDs)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout middleware gives each request a deadline. Handlers pass the context of the request down to the
+// repositories, so queries still running when it expires, or when the client disconnects, are cancelled
+// and the request fails with 504 Gateway Timeout, or 499 when the client went away.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -24,6 +24,9 @@ USE `todo-database`;
 --   name: Full name of the user
 --   username: Unique username for login
 --   email: Email address (should be unique)
+--   password_hash: bcrypt hash of the user's password (NULL means the user can't log in)
+--   role: Access level of the user (admin, member or viewer)
+--   active: Boolean indicating if the user can still sign in (users are deactivated, never deleted)
 --   created_at: Timestamp when user was created
 DROP TABLE IF EXISTS `users`;
 CREATE TABLE `users` (
@@ -31,11 +34,71 @@ CREATE TABLE `users` (
     name VARCHAR(255) NOT NULL,
     username VARCHAR(100) NOT NULL UNIQUE,
     email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NULL,
+    role ENUM('admin', 'member', 'viewer') NOT NULL DEFAULT 'member',
+    active BOOLEAN NOT NULL DEFAULT true,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     INDEX idx_username (username),
     INDEX idx_email (email)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- ============================================================================
+-- REFRESH_TOKENS TABLE
+-- ============================================================================
+-- Issued refresh tokens, used for rotation and revocation
+-- Fields:
+--   id: The token's jti claim
+--   user_id: Owner of the token (foreign key to users)
+--   expires_at: Expiry of the token, mirrors the exp claim
+--   revoked: Boolean indicating the token was used, logged out or revoked
+--   replaced_by: jti of the token issued when this one was rotated
+--   created_at: Timestamp when the token was issued
+DROP TABLE IF EXISTS `refresh_tokens`;
+CREATE TABLE `refresh_tokens` (
+    id VARCHAR(64) PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked BOOLEAN NOT NULL DEFAULT false,
+    replaced_by VARCHAR(64) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================================================
+-- TEAMS TABLE
+-- ============================================================================
+-- Teams are the tenancy boundary: tasks and workflows are only visible to the members of their team
+-- Fields:
+--   id: Unique identifier (auto-increment)
+--   name: Unique name of the team
+--   created_at: Timestamp when team was created
+DROP TABLE IF EXISTS `teams`;
+CREATE TABLE `teams` (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================================================
+-- TEAM_MEMBERS TABLE
+-- ============================================================================
+-- Membership of users in teams, a user can belong to several teams
+-- Fields:
+--   team_id: Team the user belongs to (foreign key to teams)
+--   user_id: Member of the team (foreign key to users)
+--   created_at: Timestamp when the user joined the team
+DROP TABLE IF EXISTS `team_members`;
+CREATE TABLE `team_members` (
+    team_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, user_id),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- ============================================================================
 -- TASK_STATUSES TABLE
 -- ============================================================================
@@ -44,11 +107,13 @@ CREATE TABLE `users` (
 --   id: Unique identifier (auto-increment)
 --   label: Human-readable status label
 --   active: Boolean indicating if status is active/available for use
+--   version: Incremented by every update, writes based on an older version are rejected
 DROP TABLE IF EXISTS `task_statuses`;
 CREATE TABLE `task_statuses` (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     label VARCHAR(100) NOT NULL UNIQUE,
     active BOOLEAN NOT NULL DEFAULT true,
+    version BIGINT NOT NULL DEFAULT 1,
     INDEX idx_active (active)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
@@ -58,11 +123,18 @@ CREATE TABLE `task_statuses` (
 -- Task type definitions (e.g., Bug, Feature, Enhancement)
 -- Fields:
 --   id: Unique identifier (auto-increment)
---   name: Name of the task type
+--   name: Name of the task type, unique within its team
+--   team_id: Team owning the task type (NULL means shared by every team)
+--   version: Incremented by every update, writes based on an older version are rejected
 DROP TABLE IF EXISTS `task_types`;
 CREATE TABLE `task_types` (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    name VARCHAR(100) NOT NULL UNIQUE
+    name VARCHAR(100) NOT NULL,
+    team_id BIGINT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_team_name (team_id, name),
+    INDEX idx_team_id (team_id),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- ============================================================================
@@ -73,16 +145,32 @@ CREATE TABLE `task_types` (
 --   id: Unique identifier (auto-increment)
 --   name: Name of the workflow
 --   statuses: JSON field storing the mapping of status order to status IDs
+--   sequential: Whether tasks may only move to the previous or next status of the order
+--   graph: Optional JSON field storing the initial status, the terminal statuses and the allowed
+--          transitions; when set it replaces the order of statuses
+--   guards: Optional JSON field storing the conditions tasks must meet to enter some statuses
+--   actions: Optional JSON field storing the side effects run after tasks entered some statuses
 --   author_id: Foreign key to the user who created the workflow
+--   team_id: Team owning the workflow (foreign key to teams)
 --   created_at: Timestamp when workflow was created
+--   version: Incremented by every update, writes based on an older version are rejected
 DROP TABLE IF EXISTS `workflows`;
 CREATE TABLE `workflows` (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
     statuses JSON NOT NULL,
+    sequential BOOLEAN NOT NULL DEFAULT FALSE,
+    graph JSON NULL,
+    guards JSON NULL,
+    actions JSON NULL,
     author_id BIGINT NOT NULL,
+    team_id BIGINT NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    INDEX idx_author_id (author_id)
+    version BIGINT NOT NULL DEFAULT 1,
+    INDEX idx_author_id (author_id),
+    INDEX idx_team_id (team_id),
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE RESTRICT ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- ============================================================================
@@ -102,7 +190,9 @@ CREATE TABLE `workflows` (
 --   responsible_id: User assigned to complete the task (foreign key to users)
 --   workflow_id: Workflow template for this task (foreign key to workflows)
 --   type_id: Type of task (foreign key to task_types)
+--   team_id: Team owning the task (foreign key to teams)
 --   completed: Boolean indicating if task is completed
+--   version: Incremented by every update, writes based on an older version are rejected
 DROP TABLE IF EXISTS `tasks`;
 CREATE TABLE `tasks` (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
@@ -117,7 +207,9 @@ CREATE TABLE `tasks` (
     responsible_id BIGINT NULL,
     workflow_id BIGINT NOT NULL,
     type_id BIGINT NOT NULL,
+    team_id BIGINT NOT NULL,
     completed BOOLEAN NOT NULL DEFAULT false,
+    version BIGINT NOT NULL DEFAULT 1,
     
     INDEX idx_status_id (status_id),
     INDEX idx_parent_id (parent_id),
@@ -125,12 +217,91 @@ CREATE TABLE `tasks` (
     INDEX idx_responsible_id (responsible_id),
     INDEX idx_workflow_id (workflow_id),
     INDEX idx_type_id (type_id),
+    INDEX idx_team_id (team_id),
     INDEX idx_deadline (deadline),
     INDEX idx_completed (completed),
     INDEX idx_created_at (created_at),
     INDEX idx_author_status (author_id, status_id),
     INDEX idx_responsible_status (responsible_id, status_id),
-    INDEX idx_overdue (completed, deadline)
+    INDEX idx_overdue (completed, deadline),
+    FULLTEXT INDEX ft_title_description (title, description),
+    FOREIGN KEY (status_id) REFERENCES task_statuses(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (responsible_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (workflow_id) REFERENCES workflows(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (type_id) REFERENCES task_types(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE RESTRICT ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================================================
+-- TASK_ACTION_FAILURES TABLE
+-- ============================================================================
+-- Workflow actions that failed after a task changed status
+-- Fields:
+--   id: Unique identifier (auto-increment)
+--   task_id: Task whose transition triggered the action (foreign key to tasks)
+--   from_status_id: Status the task left
+--   to_status_id: Status the task entered
+--   action: Kind of the failed action
+--   error: Why the action failed
+--   created_at: Timestamp when the failure happened
+DROP TABLE IF EXISTS `task_action_failures`;
+CREATE TABLE `task_action_failures` (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    task_id BIGINT NOT NULL,
+    from_status_id BIGINT NOT NULL,
+    to_status_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    error TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_task_id (task_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================================================
+-- TASK_EVENTS TABLE
+-- ============================================================================
+-- History of the changes of tasks, one row per changed field, written with each task update
+-- Fields:
+--   id: Unique identifier (auto-increment)
+--   task_id: Changed task (foreign key to tasks)
+--   actor_id: User who made the change, NULL for changes not made on behalf of a user (foreign key to users)
+--   field: Name of the changed field
+--   old_value: Value before the change, NULL when the field was not set
+--   new_value: Value after the change, NULL when the field was cleared
+--   created_at: Timestamp of the change
+DROP TABLE IF EXISTS `task_events`;
+CREATE TABLE `task_events` (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    task_id BIGINT NOT NULL,
+    actor_id BIGINT NULL,
+    field VARCHAR(32) NOT NULL,
+    old_value TEXT NULL,
+    new_value TEXT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE,
+    INDEX idx_task_created (task_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================================================
+-- TASK_DEPENDENCIES TABLE
+-- ============================================================================
+-- Tasks that cannot be completed before other tasks are, one row per blocking relation
+-- Fields:
+--   blocker_id: Task that must be completed first (foreign key to tasks)
+--   blocked_id: Task waiting for the blocker (foreign key to tasks)
+--   created_at: Timestamp when the dependency was added
+DROP TABLE IF EXISTS `task_dependencies`;
+CREATE TABLE `task_dependencies` (
+    blocker_id BIGINT NOT NULL,
+    blocked_id BIGINT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_blocked_id (blocked_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- ============================================================================
@@ -141,13 +312,33 @@ CREATE TABLE `tasks` (
 -- INSERT USERS
 -- ============================================================================
 -- Users with different roles and responsibilities
-INSERT INTO users (name, username, email) VALUES
-('John Doe', 'johndoe', 'john@example.com'),
-('Jane Smith', 'janesmith', 'jane@example.com'),
-('Bob Johnson', 'bjohnson', 'bob@example.com'),
-('Alice Williams', 'awilliams', 'alice@example.com'),
-('Carlos Rodriguez', 'crodriguez', 'carlos@example.com'),
-('Emma Thompson', 'ethompson', 'emma@example.com');
+-- All sample users share the development password "password123"
+-- John Doe is the administrator and Emma Thompson has read-only access
+INSERT INTO users (name, username, email, password_hash, role) VALUES
+('John Doe', 'johndoe', 'john@example.com', '$2a$07$LSrXGuLmgDRnu/VmuKc4BO4oWjeXCd4GDxV2esD.3ansC6/jTjv9y', 'admin'),
+('Jane Smith', 'janesmith', 'jane@example.com', '$2a$07$LSrXGuLmgDRnu/VmuKc4BO4oWjeXCd4GDxV2esD.3ansC6/jTjv9y', 'member'),
+('Bob Johnson', 'bjohnson', 'bob@example.com', '$2a$07$LSrXGuLmgDRnu/VmuKc4BO4oWjeXCd4GDxV2esD.3ansC6/jTjv9y', 'member'),
+('Alice Williams', 'awilliams', 'alice@example.com', '$2a$07$LSrXGuLmgDRnu/VmuKc4BO4oWjeXCd4GDxV2esD.3ansC6/jTjv9y', 'member'),
+('Carlos Rodriguez', 'crodriguez', 'carlos@example.com', '$2a$07$LSrXGuLmgDRnu/VmuKc4BO4oWjeXCd4GDxV2esD.3ansC6/jTjv9y', 'member'),
+('Emma Thompson', 'ethompson', 'emma@example.com', '$2a$07$LSrXGuLmgDRnu/VmuKc4BO4oWjeXCd4GDxV2esD.3ansC6/jTjv9y', 'viewer');
+
+-- ============================================================================
+-- INSERT TEAMS
+-- ============================================================================
+-- Platform builds the product, Support handles customer tickets
+-- Bob Johnson works in both teams
+INSERT INTO teams (name) VALUES
+('Platform'),
+('Support');
+
+INSERT INTO team_members (team_id, user_id) VALUES
+(1, 1),
+(1, 2),
+(1, 3),
+(1, 4),
+(1, 6),
+(2, 3),
+(2, 5);
 
 -- ============================================================================
 -- INSERT TASK STATUSES
@@ -181,42 +372,60 @@ INSERT INTO task_types (name) VALUES
 -- ============================================================================
 -- Workflow 1: Default Workflow (Kanban style)
 -- Mapping: 0->Backlog, 1->Todo, 2->In Progress, 3->In Review, 4->Done
-INSERT INTO workflows (name, statuses, author_id) VALUES
+-- Guards: a task needs a responsible user to go to review, and completed subtasks to be done
+-- Actions: reviews are due two days after the task went to review
+INSERT INTO workflows (name, statuses, guards, actions, author_id, team_id) VALUES
 ('Default Workflow', '{
   "0": {"id": 1, "label": "Backlog", "active": true},
   "1": {"id": 2, "label": "Todo", "active": true},
   "2": {"id": 3, "label": "In Progress", "active": true},
   "3": {"id": 4, "label": "In Review", "active": true},
   "4": {"id": 5, "label": "Done", "active": true}
-}', 1);
+}', '[
+  {"ToStatusID": 4, "Kind": "required_field", "Field": "responsible"},
+  {"ToStatusID": 5, "Kind": "subtasks_completed"}
+]', '[
+  {"ToStatusID": 4, "Kind": "set_deadline", "DeadlineOffsetHours": 48}
+]', 1, 1);
 
 -- Workflow 2: Agile Development
 -- Mapping: 0->Backlog, 1->Todo, 2->In Progress, 3->In Review, 4->Testing, 5->Done
-INSERT INTO workflows (name, statuses, author_id) VALUES
+INSERT INTO workflows (name, statuses, author_id, team_id) VALUES
 ('Agile Development', '{
   "0": {"id": 1, "label": "Backlog", "active": true},
   "1": {"id": 2, "label": "Todo", "active": true},
   "2": {"id": 3, "label": "In Progress", "active": true},
   "3": {"id": 4, "label": "In Review", "active": true},
   "4": {"id": 5, "label": "Done", "active": true}
-}', 2);
+}', 2, 1);
 
 -- Workflow 3: Support Ticket Workflow
--- Mapping: 0->Backlog, 1->Todo, 2->In Progress, 3->Blocked, 4->Done
-INSERT INTO workflows (name, statuses, author_id) VALUES
+-- Mapping: 0->Backlog, 1->Todo, 2->In Progress, 3->Blocked, 4->Done, 5->Cancelled
+-- Graph: tickets go back and forth between In Progress and Blocked, and can be cancelled
+-- until they are done; Done and Cancelled are terminal
+INSERT INTO workflows (name, statuses, graph, author_id, team_id) VALUES
 ('Support Ticket Workflow', '{
   "0": {"id": 1, "label": "Backlog", "active": true},
   "1": {"id": 2, "label": "Todo", "active": true},
   "2": {"id": 3, "label": "In Progress", "active": true},
   "3": {"id": 7, "label": "Blocked", "active": true},
-  "4": {"id": 5, "label": "Done", "active": true}
-}', 3);
+  "4": {"id": 5, "label": "Done", "active": true},
+  "5": {"id": 6, "label": "Cancelled", "active": true}
+}', '{
+  "InitialStatusID": 1,
+  "TerminalStatusIDs": [5, 6],
+  "Transitions": [
+    {"From": 1, "To": 2}, {"From": 2, "To": 3}, {"From": 3, "To": 7}, {"From": 7, "To": 3},
+    {"From": 3, "To": 5}, {"From": 1, "To": 6}, {"From": 2, "To": 6}, {"From": 3, "To": 6},
+    {"From": 7, "To": 6}
+  ]
+}', 3, 2);
 
 -- ============================================================================
 -- INSERT TASKS
 -- ============================================================================
 -- Task 1: Implement User Authentication
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Implement User Authentication',
   'Create a complete user authentication system with JWT tokens, password hashing, and session management. Must include login, logout, and refresh token functionality.',
@@ -226,11 +435,12 @@ VALUES (
   2,
   1,
   2,
+  1,
   false
 );
 
 -- Task 2: Fix Login Bug
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Fix Login Bug on Mobile Devices',
   'Users are reporting that login is failing on mobile browsers. The issue seems to be related to cookie handling on iOS Safari.',
@@ -240,11 +450,12 @@ VALUES (
   3,
   1,
   1,
+  1,
   false
 );
 
 -- Task 3: Database Optimization
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Optimize Database Queries',
   'Review and optimize slow database queries. Add appropriate indexes and consider query refactoring for better performance.',
@@ -254,11 +465,12 @@ VALUES (
   4,
   1,
   4,
+  1,
   false
 );
 
 -- Task 4: API Documentation
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Write API Documentation',
   'Create comprehensive API documentation including endpoint descriptions, request/response examples, and authentication requirements.',
@@ -268,11 +480,12 @@ VALUES (
   5,
   2,
   4,
+  1,
   false
 );
 
 -- Task 5: Complete Task (Done)
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Setup Development Environment',
   'Configure and document the complete development environment setup for new team members.',
@@ -282,11 +495,12 @@ VALUES (
   2,
   1,
   4,
+  1,
   true
 );
 
 -- Task 6: Implement Email Notifications
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Implement Email Notifications',
   'Add email notification system for task assignments, deadline reminders, and status updates. Should support multiple email templates.',
@@ -296,11 +510,12 @@ VALUES (
   6,
   1,
   2,
+  1,
   false
 );
 
 -- Task 7: Subtask of Task 1
-INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Implement JWT Token Generation',
   'Create JWT token generation and validation logic. Include expiration handling and refresh token mechanism.',
@@ -311,11 +526,12 @@ VALUES (
   2,
   1,
   2,
+  1,
   false
 );
 
 -- Task 8: Subtask of Task 1
-INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Implement Password Hashing',
   'Set up secure password hashing using bcrypt. Create password validation and reset functionality.',
@@ -326,11 +542,12 @@ VALUES (
   2,
   1,
   2,
+  1,
   false
 );
 
 -- Task 9: Research Task
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Research API Caching Strategies',
   'Research and evaluate different caching strategies (Redis, Memcached, etc.) for API responses to improve performance.',
@@ -340,11 +557,12 @@ VALUES (
   4,
   1,
   5,
+  1,
   false
 );
 
 -- Task 10: Blocked Task
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Implement Payment Processing',
   'Integrate payment processing gateway (Stripe/PayPal). Blocked waiting for business requirements clarification.',
@@ -354,11 +572,12 @@ VALUES (
   5,
   3,
   2,
+  2,
   false
 );
 
 -- Task 11: Testing Task
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Unit Tests for Authentication Module',
   'Write comprehensive unit tests for the authentication module covering all edge cases and error scenarios.',
@@ -368,11 +587,12 @@ VALUES (
   3,
   1,
   6,
+  1,
   false
 );
 
 -- Task 12: Refactoring Task
-INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 VALUES (
   'Refactor Task Repository Layer',
   'Refactor the task repository to improve code organization and reduce duplication. Consider implementing repository pattern.',
@@ -382,6 +602,7 @@ VALUES (
   2,
   1,
   7,
+  1,
   false
 );
 
@@ -395,6 +616,7 @@ VALUES (
 -- - idx_author_id on tasks(author_id) - for finding tasks by author
 -- - idx_responsible_id on tasks(responsible_id) - for finding assigned tasks
 -- - idx_workflow_id on tasks(workflow_id) - for finding tasks by workflow
+-- - idx_team_id on tasks(team_id) - for scoping tasks to the caller's teams
 -- - idx_type_id on tasks(type_id) - for finding tasks by type
 -- - idx_deadline on tasks(deadline) - for sorting by deadline
 -- - idx_completed on tasks(completed) - for filtering incomplete tasks
@@ -402,3 +624,4 @@ VALUES (
 -- - idx_author_status on tasks(author_id, status_id) - composite for author+status queries
 -- - idx_responsible_status on tasks(responsible_id, status_id) - composite for responsible+status
 -- - idx_overdue on tasks(completed, deadline) - for finding overdue tasks
+-- - ft_title_description on tasks(title, description) - FULLTEXT index for /todo/search
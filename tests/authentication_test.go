@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api"
+	"todo-api/internal/middleware"
+	"todo-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var authenticationSecret = []byte("test-secret")
+
+// authenticationUsers stands in for the user repository of the authentication middleware,
+// only looking users up and deactivating them
+type authenticationUsers struct {
+	domain.UserRepository
+	users map[int64]entities.User
+}
+
+// newAuthenticationUsers knows the active users 1 and 3, who sign the test tokens
+func newAuthenticationUsers() *authenticationUsers {
+	return &authenticationUsers{users: map[int64]entities.User{
+		1: {ID: 1, Active: true},
+		3: {ID: 3, Active: true},
+	}}
+}
+
+func (m *authenticationUsers) GetByID(ctx context.Context, id int64) (entities.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return entities.User{}, domain.NewNotFoundError("user", id)
+	}
+	return user, nil
+}
+
+func (m *authenticationUsers) Deactivate(ctx context.Context, id int64) error {
+	user := m.users[id]
+	user.Deactivate()
+	m.users[id] = user
+	return nil
+}
+
+func newAuthenticatedRouter(users domain.UserRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	protected := router.Group("/api/v1")
+	protected.Use(middleware.Authentication(authenticationSecret, users))
+	protected.GET("/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetInt64("user_id")})
+	})
+
+	return router
+}
+
+func signTestToken(tokenType string, ttl time.Duration) string {
+	token, _ := utils.GenerateToken(utils.TokenClaims{
+		Subject:   "3",
+		TokenID:   "test",
+		Type:      tokenType,
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}, authenticationSecret)
+	return token
+}
+
+// TestAuthenticationRejectsMissingToken tests that protected routes require a bearer token
+func TestAuthenticationRejectsMissingToken(t *testing.T) {
+	router := newAuthenticatedRouter(newAuthenticationUsers())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/me", nil)
+
+	router.ServeHTTP(w, req)
+
+	assertProblem(t, w, http.StatusUnauthorized, api.ERROR_CODE_UNAUTHORIZED)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+}
+
+// assertProblem checks that the response is the problem with the given status and code, carrying the request ID
+func assertProblem(t *testing.T, w *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, status, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, code, body["code"])
+	assert.Equal(t, w.Header().Get("X-Request-ID"), body["request_id"])
+}
+
+// TestAuthenticationRejectsInvalidTokens tests that expired tokens and refresh tokens get the same 401 problem
+func TestAuthenticationRejectsInvalidTokens(t *testing.T) {
+	router := newAuthenticatedRouter(newAuthenticationUsers())
+
+	for _, token := range []string{
+		"not-a-jwt",
+		signTestToken(utils.AccessTokenType, -time.Minute),
+		signTestToken(utils.RefreshTokenType, time.Minute),
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		router.ServeHTTP(w, req)
+
+		assertProblem(t, w, http.StatusUnauthorized, api.ERROR_CODE_UNAUTHORIZED)
+	}
+}
+
+// TestAuthenticationSetsUserID tests that a valid access token exposes the user ID to handlers
+func TestAuthenticationSetsUserID(t *testing.T) {
+	router := newAuthenticatedRouter(newAuthenticationUsers())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(utils.AccessTokenType, time.Minute))
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id":3}`, w.Body.String())
+}
+
+// TestAuthenticationRejectsDeactivatedUsers tests that a deactivated user loses access before the token expires
+func TestAuthenticationRejectsDeactivatedUsers(t *testing.T) {
+	users := newAuthenticationUsers()
+	router := newAuthenticatedRouter(users)
+	token := signTestToken(utils.AccessTokenType, time.Minute)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, users.Deactivate(context.Background(), 3))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+	assertProblem(t, w, http.StatusUnauthorized, api.ERROR_CODE_UNAUTHORIZED)
+}
+
+// TestAuthenticationLeavesPublicRoutesOpen tests that routes outside the protected group need no token
+func TestAuthenticationLeavesPublicRoutesOpen(t *testing.T) {
+	router := newAuthenticatedRouter(newAuthenticationUsers())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
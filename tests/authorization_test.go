@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api"
+	"todo-api/internal/infrastructure/api/routes"
+	"todo-api/internal/middleware"
+	"todo-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func signRoleToken(role entities.Role) string {
+	token, _ := utils.GenerateToken(utils.TokenClaims{
+		Subject:   "1",
+		TokenID:   "test",
+		Type:      utils.AccessTokenType,
+		Role:      string(role),
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: time.Now().Add(time.Minute).Unix(),
+	}, authenticationSecret)
+	return token
+}
+
+// newRoleProtectedRouter wires the real route groups, handlers are never reached when access is denied
+func newRoleProtectedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+
+	protected := router.Group("/api/v1")
+	protected.Use(middleware.Authentication(authenticationSecret, newAuthenticationUsers()))
+
+	routes.SetTaskRoutes(protected, nil)
+	routes.SetTaskStatusRoutes(protected, nil)
+	routes.SetTaskTypeRoutes(protected, nil)
+	routes.SetWorkflowRoutes(protected, nil)
+	routes.SetUserRoutes(protected, nil)
+	routes.SetTeamRoutes(protected, nil)
+
+	return router
+}
+
+func requestAs(router *gin.Engine, role entities.Role, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+signRoleToken(role))
+
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestOnlyAdminsMutateSharedConfiguration tests that statuses, task types, workflows, users and teams are admin-only for writes
+func TestOnlyAdminsMutateSharedConfiguration(t *testing.T) {
+	router := newRoleProtectedRouter()
+
+	for _, path := range []string{"/api/v1/statuses", "/api/v1/task-type", "/api/v1/workflows", "/api/v1/users", "/api/v1/teams"} {
+		for _, role := range []entities.Role{entities.RoleMember, entities.RoleViewer} {
+			w := requestAs(router, role, http.MethodPost, path)
+			assertProblem(t, w, http.StatusForbidden, api.ERROR_CODE_FORBIDDEN)
+
+			w = requestAs(router, role, http.MethodPut, path+"/1")
+			assert.Equal(t, http.StatusForbidden, w.Code, "%s PUT %s", role, path)
+
+			w = requestAs(router, role, http.MethodDelete, path+"/1")
+			assert.Equal(t, http.StatusForbidden, w.Code, "%s DELETE %s", role, path)
+		}
+	}
+}
+
+// TestViewersAreReadOnlyOnTasks tests that viewers can't create, update, patch or delete tasks
+func TestViewersAreReadOnlyOnTasks(t *testing.T) {
+	router := newRoleProtectedRouter()
+
+	assert.Equal(t, http.StatusForbidden, requestAs(router, entities.RoleViewer, http.MethodPost, "/api/v1/todo").Code)
+	assert.Equal(t, http.StatusForbidden, requestAs(router, entities.RoleViewer, http.MethodPut, "/api/v1/todo/1").Code)
+	assert.Equal(t, http.StatusForbidden, requestAs(router, entities.RoleViewer, http.MethodPatch, "/api/v1/todo/1").Code)
+	assert.Equal(t, http.StatusForbidden, requestAs(router, entities.RoleViewer, http.MethodDelete, "/api/v1/todo/1").Code)
+	assert.Equal(t, http.StatusForbidden, requestAs(router, entities.RoleViewer, http.MethodPost, "/api/v1/todo/1/transition").Code)
+}
+
+// TestRequireRoleAllowsListedRoles tests that the middleware lets matching roles reach the handler
+func TestRequireRoleAllowsListedRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+
+	protected := router.Group("/api/v1")
+	protected.Use(middleware.Authentication(authenticationSecret, newAuthenticationUsers()))
+	protected.POST("/todo", middleware.RequireRole(entities.RoleAdmin, entities.RoleMember), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	assert.Equal(t, http.StatusCreated, requestAs(router, entities.RoleMember, http.MethodPost, "/api/v1/todo").Code)
+	assert.Equal(t, http.StatusCreated, requestAs(router, entities.RoleAdmin, http.MethodPost, "/api/v1/todo").Code)
+	assert.Equal(t, http.StatusForbidden, requestAs(router, entities.RoleViewer, http.MethodPost, "/api/v1/todo").Code)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+	"todo-api/internal/infrastructure/api/problems"
+	"todo-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveError answers a request whose handler aborts with err, through the error handler middleware
+func serveError(err error) (*httptest.ResponseRecorder, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+	router.GET("/api/v1/todo/5", func(c *gin.Context) {
+		_ = c.Error(err)
+		c.Abort()
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/todo/5", nil)
+	router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func TestErrorHandler_ProblemDetails(t *testing.T) {
+	w, body := serveError(domain.NewNotFoundError("task", 5))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get("X-Error-Response"))
+	assert.Equal(t, "about:blank", body["type"])
+	assert.Equal(t, "Not Found", body["title"])
+	assert.Equal(t, float64(http.StatusNotFound), body["status"])
+	assert.Equal(t, "task 5 not found", body["detail"])
+	assert.Equal(t, "/api/v1/todo/5", body["instance"])
+	assert.Equal(t, api.ERROR_CODE_NOT_FOUND, body["code"])
+	assert.Len(t, body["request_id"], 36)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), body["request_id"])
+}
+
+// invalidWorkflowError returns the error the workflow service rejects a workflow with a misplaced guard with
+func invalidWorkflowError() error {
+	workflow := entities.Workflow{
+		Statuses: map[uint8]entities.TaskStatus{0: {ID: 1, Active: true}},
+		Guards:   []entities.TransitionGuard{{ToStatusID: 9, Kind: entities.GuardSubtasksCompleted}},
+	}
+	_, err := domain.NewWorkflowService(nil).Create(context.Background(), workflow, 1)
+	return err
+}
+
+func TestErrorHandler_MapsDomainErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", domain.NewNotFoundError("workflow", 2), http.StatusNotFound, api.ERROR_CODE_NOT_FOUND},
+		{"wrapped not found", fmt.Errorf("loading task: %w", domain.NewNotFoundError("task", 5)), http.StatusNotFound, api.ERROR_CODE_NOT_FOUND},
+		{"conflict", domain.ErrUsernameTaken, http.StatusConflict, api.ERROR_CODE_CONFLICT},
+		{"validation", domain.NewValidationError("id", "Invalid task ID"), http.StatusBadRequest, api.ERROR_CODE_VALIDATION},
+		{"invalid workflow", invalidWorkflowError(), http.StatusBadRequest, api.ERROR_CODE_VALIDATION},
+		{"forbidden", &domain.ForbiddenError{Message: "caller is not a member of the requested team"}, http.StatusForbidden, api.ERROR_CODE_FORBIDDEN},
+		{"version conflict", domain.ErrVersionConflict, http.StatusPreconditionFailed, api.ERROR_CODE_VERSION_CONFLICT},
+		{"invalid reference", domain.NewMissingReferenceError("workflow", 3), http.StatusUnprocessableEntity, api.ERROR_CODE_INVALID_REFERENCE},
+		{"transition not allowed", entities.ErrTransitionNotAllowed, http.StatusUnprocessableEntity, api.ERROR_CODE_TRANSITION_NOT_ALLOWED},
+		{"invalid migration", fmt.Errorf("%w: status 4 is not part of workflow 2", entities.ErrInvalidMigration), http.StatusUnprocessableEntity, api.ERROR_CODE_INVALID_MIGRATION},
+		{"unauthenticated", problems.ErrUnauthenticated, http.StatusUnauthorized, api.ERROR_CODE_UNAUTHORIZED},
+		{"If-Match required", problems.ErrIfMatchRequired, http.StatusPreconditionRequired, api.ERROR_CODE_PRECONDITION_REQUIRED},
+	}
+
+	for _, tc := range cases {
+		w, body := serveError(tc.err)
+
+		assert.Equal(t, tc.status, w.Code, tc.name)
+		assert.Equal(t, tc.code, body["code"], tc.name)
+		assert.Equal(t, tc.err.Error(), body["detail"], tc.name)
+	}
+}
+
+func TestErrorHandler_Extensions(t *testing.T) {
+	_, body := serveError(domain.NewValidationError("limit", "limit must be between 1 and 100"))
+	assert.Equal(t, "limit", body["field"])
+
+	w, body := serveError(&dto.ValidationError{Fields: []dto.FieldError{{Field: "title", Message: "title is required"}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "title", "message": "title is required"}}, body["fields"])
+
+	w, body = serveError(&entities.DependencyCycleError{TaskIDs: []int64{1, 2, 1}})
+	assert.Equal(t, api.ERROR_CODE_DEPENDENCY_CYCLE, body["code"])
+	assert.Equal(t, []interface{}{float64(1), float64(2), float64(1)}, body["cycle"])
+
+	_, body = serveError(&entities.BlockedTaskError{BlockerIDs: []int64{7}})
+	assert.Equal(t, api.ERROR_CODE_TASK_BLOCKED, body["code"])
+	assert.Equal(t, []interface{}{float64(7)}, body["open_blocker_ids"])
+
+	w, body = serveError(&entities.OrphanedTasksError{StatusIDs: []int64{3}})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, api.ERROR_CODE_ORPHANED_TASKS, body["code"])
+	assert.Equal(t, []interface{}{float64(3)}, body["orphaned_status_ids"])
+
+	guard := entities.TransitionGuard{ToStatusID: 3, Kind: entities.GuardRequiredField, Field: "responsible"}
+	w, body = serveError(&entities.GuardError{Failures: []entities.GuardFailure{{Guard: guard, Message: "responsible is required"}}})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, api.ERROR_CODE_TRANSITION_BLOCKED, body["code"])
+	assert.Len(t, body["failed_guards"], 1)
+
+	patchErr := &problems.RequestError{Status: http.StatusUnprocessableEntity, Code: api.ERROR_CODE_INVALID_PATCH,
+		Err: &dto.ValidationError{Fields: []dto.FieldError{{Field: "parent_id", Message: "parent_id must be a positive ID"}}}}
+	w, body = serveError(patchErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, api.ERROR_CODE_INVALID_PATCH, body["code"])
+	assert.Len(t, body["fields"], 1)
+}
+
+func TestErrorHandler_CancelledRequests(t *testing.T) {
+	w, body := serveError(fmt.Errorf("listing tasks: %w", context.DeadlineExceeded))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, api.ERROR_CODE_TIMEOUT, body["code"])
+
+	w, body = serveError(fmt.Errorf("listing tasks: %w", context.Canceled))
+	assert.Equal(t, problems.StatusClientClosedRequest, w.Code)
+	assert.Equal(t, api.ERROR_CODE_CLIENT_CLOSED_REQUEST, body["code"])
+	assert.Equal(t, "Client Closed Request", body["title"])
+}
+
+func TestErrorHandler_HidesUnexpectedErrors(t *testing.T) {
+	w, body := serveError(errors.New("dial tcp 10.0.0.3:3306: connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, api.ERROR_CODE_INTERNAL, body["code"])
+	assert.NotContains(t, w.Body.String(), "10.0.0.3")
+}
+
+func TestErrorHandler_KeepsWrittenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/todo", func(c *gin.Context) {
+		_ = c.Error(errors.New("recorded after answering"))
+		c.JSON(http.StatusOK, gin.H{"data": []int{}})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/todo", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": []}`, w.Body.String())
+}
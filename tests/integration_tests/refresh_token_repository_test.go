@@ -0,0 +1,73 @@
+package integrationtests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/database/repositories"
+)
+
+func TestRefreshTokenRotation(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	tokenRepository := repositories.NewRefreshTokenRepository(db)
+
+	if _, err := tokenRepository.Create(context.Background(), entities.NewRefreshToken("first", 1, time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("Failed to create refresh token: %v", err)
+	}
+	if _, err := tokenRepository.Create(context.Background(), entities.NewRefreshToken("second", 1, time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("Failed to create refresh token: %v", err)
+	}
+
+	if err := tokenRepository.Revoke(context.Background(), "first", "second"); err != nil {
+		t.Fatalf("Failed to revoke refresh token: %v", err)
+	}
+
+	first, err := tokenRepository.GetByID(context.Background(), "first")
+	if err != nil {
+		t.Fatalf("Failed to get refresh token: %v", err)
+	}
+	if !first.Revoked || first.ReplacedBy != "second" {
+		t.Errorf("Expected token to be revoked and replaced by 'second', got %+v", first)
+	}
+
+	third := entities.NewRefreshToken("third", 1, time.Now().Add(time.Hour))
+	if err := tokenRepository.Rotate(context.Background(), "second", third); err != nil {
+		t.Fatalf("Failed to rotate refresh token: %v", err)
+	}
+	if err := tokenRepository.Rotate(context.Background(), "second", entities.NewRefreshToken("fourth", 1, time.Now().Add(time.Hour))); !errors.Is(err, domain.ErrTokenReused) {
+		t.Fatalf("Expected rotating a revoked token to fail with ErrTokenReused, got %v", err)
+	}
+	if _, err := tokenRepository.GetByID(context.Background(), "fourth"); err == nil {
+		t.Error("Expected the successor of a reused token not to be stored")
+	}
+	if stored, err := tokenRepository.GetByID(context.Background(), "third"); err != nil || !stored.IsUsable() {
+		t.Errorf("Expected the rotated token to be stored and usable, got %+v, %v", stored, err)
+	}
+
+	if err := tokenRepository.RevokeAllForUser(context.Background(), 1); err != nil {
+		t.Fatalf("Failed to revoke user tokens: %v", err)
+	}
+
+	second, err := tokenRepository.GetByID(context.Background(), "second")
+	if err != nil {
+		t.Fatalf("Failed to get refresh token: %v", err)
+	}
+	if second.IsUsable() {
+		t.Error("Expected every token of the user to be revoked")
+	}
+}
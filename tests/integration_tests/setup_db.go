@@ -38,6 +38,9 @@ func CreateUsersTable(ctx context.Context, db *sql.DB) error {
 		"    name VARCHAR(255) NOT NULL,\n" +
 		"    username VARCHAR(100) NOT NULL UNIQUE,\n" +
 		"    email VARCHAR(255) NOT NULL UNIQUE,\n" +
+		"    password_hash VARCHAR(255) NULL,\n" +
+		"    role ENUM('admin', 'member', 'viewer') NOT NULL DEFAULT 'member',\n" +
+		"    active BOOLEAN NOT NULL DEFAULT true,\n" +
 		"    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n" +
 		"    INDEX idx_username (username),\n" +
 		"    INDEX idx_email (email)\n" +
@@ -46,6 +49,63 @@ func CreateUsersTable(ctx context.Context, db *sql.DB) error {
 	return err
 }
 
+func DropRefreshTokensTable(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS `refresh_tokens`;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func CreateRefreshTokensTable(ctx context.Context, db *sql.DB) error {
+	query := "CREATE TABLE `refresh_tokens` (\n" +
+		"    id VARCHAR(64) PRIMARY KEY,\n" +
+		"    user_id BIGINT NOT NULL,\n" +
+		"    expires_at DATETIME NOT NULL,\n" +
+		"    revoked BOOLEAN NOT NULL DEFAULT false,\n" +
+		"    replaced_by VARCHAR(64) NULL,\n" +
+		"    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    INDEX idx_user_id (user_id)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func DropTeamsTable(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS `teams`;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func CreateTeamsTable(ctx context.Context, db *sql.DB) error {
+	query := "CREATE TABLE `teams` (\n" +
+		"    id BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
+		"    name VARCHAR(255) NOT NULL UNIQUE,\n" +
+		"    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func DropTeamMembersTable(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS `team_members`;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func CreateTeamMembersTable(ctx context.Context, db *sql.DB) error {
+	query := "CREATE TABLE `team_members` (\n" +
+		"    team_id BIGINT NOT NULL,\n" +
+		"    user_id BIGINT NOT NULL,\n" +
+		"    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    PRIMARY KEY (team_id, user_id),\n" +
+		"    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    INDEX idx_user_id (user_id)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
 func DropTaskStatusesTable(ctx context.Context, db *sql.DB) error {
 	query := "DROP TABLE IF EXISTS `task_statuses`;"
 	_, err := db.ExecContext(ctx, query)
@@ -57,6 +117,7 @@ func CreateTaskStatusesTable(ctx context.Context, db *sql.DB) error {
 		"    id BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
 		"    label VARCHAR(100) NOT NULL UNIQUE,\n" +
 		"    active BOOLEAN NOT NULL DEFAULT true,\n" +
+		"    version BIGINT NOT NULL DEFAULT 1,\n" +
 		"    INDEX idx_active (active)\n" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
 	_, err := db.ExecContext(ctx, query)
@@ -72,7 +133,12 @@ func DropTaskTypesTable(ctx context.Context, db *sql.DB) error {
 func CreateTaskTypesTable(ctx context.Context, db *sql.DB) error {
 	query := "CREATE TABLE `task_types` (\n" +
 		"    id BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
-		"    name VARCHAR(100) NOT NULL UNIQUE\n" +
+		"    name VARCHAR(100) NOT NULL,\n" +
+		"    team_id BIGINT NULL,\n" +
+		"    version BIGINT NOT NULL DEFAULT 1,\n" +
+		"    UNIQUE KEY uq_team_name (team_id, name),\n" +
+		"    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    INDEX idx_team_id (team_id)\n" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
 	_, err := db.ExecContext(ctx, query)
 	return err
@@ -89,10 +155,18 @@ func CreateWorkflowsTable(ctx context.Context, db *sql.DB) error {
 		"    id BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
 		"    name VARCHAR(255) NOT NULL,\n" +
 		"    statuses JSON NOT NULL,\n" +
+		"    sequential BOOLEAN NOT NULL DEFAULT FALSE,\n" +
+		"    graph JSON NULL,\n" +
+		"    guards JSON NULL,\n" +
+		"    actions JSON NULL,\n" +
 		"    author_id BIGINT NOT NULL,\n" +
+		"    team_id BIGINT NOT NULL,\n" +
 		"    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    version BIGINT NOT NULL DEFAULT 1,\n" +
 		"    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE RESTRICT ON UPDATE CASCADE,\n" +
-		"    INDEX idx_author_id (author_id)\n" +
+		"    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE RESTRICT ON UPDATE CASCADE,\n" +
+		"    INDEX idx_author_id (author_id),\n" +
+		"    INDEX idx_team_id (team_id)\n" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
 	_, err := db.ExecContext(ctx, query)
 	return err
@@ -118,7 +192,9 @@ func CreateTasksTable(ctx context.Context, db *sql.DB) error {
 		"    responsible_id BIGINT NULL,\n" +
 		"    workflow_id BIGINT NOT NULL,\n" +
 		"    type_id BIGINT NOT NULL,\n" +
+		"    team_id BIGINT NOT NULL,\n" +
 		"    completed BOOLEAN NOT NULL DEFAULT false,\n" +
+		"    version BIGINT NOT NULL DEFAULT 1,\n" +
 		"    \n" +
 		"    FOREIGN KEY (status_id) REFERENCES task_statuses(id) ON DELETE RESTRICT ON UPDATE CASCADE,\n" +
 		"    FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
@@ -126,6 +202,7 @@ func CreateTasksTable(ctx context.Context, db *sql.DB) error {
 		"    FOREIGN KEY (responsible_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE,\n" +
 		"    FOREIGN KEY (workflow_id) REFERENCES workflows(id) ON DELETE RESTRICT ON UPDATE CASCADE,\n" +
 		"    FOREIGN KEY (type_id) REFERENCES task_types(id) ON DELETE RESTRICT ON UPDATE CASCADE,\n" +
+		"    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE RESTRICT ON UPDATE CASCADE,\n" +
 		"    \n" +
 		"    INDEX idx_status_id (status_id),\n" +
 		"    INDEX idx_parent_id (parent_id),\n" +
@@ -133,12 +210,79 @@ func CreateTasksTable(ctx context.Context, db *sql.DB) error {
 		"    INDEX idx_responsible_id (responsible_id),\n" +
 		"    INDEX idx_workflow_id (workflow_id),\n" +
 		"    INDEX idx_type_id (type_id),\n" +
+		"    INDEX idx_team_id (team_id),\n" +
 		"    INDEX idx_deadline (deadline),\n" +
 		"    INDEX idx_completed (completed),\n" +
 		"    INDEX idx_created_at (created_at),\n" +
 		"    INDEX idx_author_status (author_id, status_id),\n" +
 		"    INDEX idx_responsible_status (responsible_id, status_id),\n" +
-		"    INDEX idx_overdue (completed, deadline)\n" +
+		"    INDEX idx_overdue (completed, deadline),\n" +
+		"    FULLTEXT INDEX ft_title_description (title, description)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func DropTaskActionFailuresTable(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS `task_action_failures`;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func CreateTaskActionFailuresTable(ctx context.Context, db *sql.DB) error {
+	query := "CREATE TABLE `task_action_failures` (\n" +
+		"    id BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
+		"    task_id BIGINT NOT NULL,\n" +
+		"    from_status_id BIGINT NOT NULL,\n" +
+		"    to_status_id BIGINT NOT NULL,\n" +
+		"    action VARCHAR(32) NOT NULL,\n" +
+		"    error TEXT NOT NULL,\n" +
+		"    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    INDEX idx_task_id (task_id)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func DropTaskEventsTable(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS `task_events`;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func CreateTaskEventsTable(ctx context.Context, db *sql.DB) error {
+	query := "CREATE TABLE `task_events` (\n" +
+		"    id BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
+		"    task_id BIGINT NOT NULL,\n" +
+		"    actor_id BIGINT NULL,\n" +
+		"    field VARCHAR(32) NOT NULL,\n" +
+		"    old_value TEXT NULL,\n" +
+		"    new_value TEXT NULL,\n" +
+		"    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE,\n" +
+		"    INDEX idx_task_created (task_id, created_at)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func DropTaskDependenciesTable(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS `task_dependencies`;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func CreateTaskDependenciesTable(ctx context.Context, db *sql.DB) error {
+	query := "CREATE TABLE `task_dependencies` (\n" +
+		"    blocker_id BIGINT NOT NULL,\n" +
+		"    blocked_id BIGINT NOT NULL,\n" +
+		"    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    PRIMARY KEY (blocker_id, blocked_id),\n" +
+		"    FOREIGN KEY (blocker_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    FOREIGN KEY (blocked_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,\n" +
+		"    INDEX idx_blocked_id (blocked_id)\n" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;"
 	_, err := db.ExecContext(ctx, query)
 	return err
@@ -146,13 +290,32 @@ func CreateTasksTable(ctx context.Context, db *sql.DB) error {
 
 // Sample data inserts
 func InsertSampleUsers(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO users (name, username, email) VALUES\n" +
-		"('John Doe', 'johndoe', 'john@example.com'),\n" +
-		"('Jane Smith', 'janesmith', 'jane@example.com'),\n" +
-		"('Bob Johnson', 'bjohnson', 'bob@example.com'),\n" +
-		"('Alice Williams', 'awilliams', 'alice@example.com'),\n" +
-		"('Carlos Rodriguez', 'crodriguez', 'carlos@example.com'),\n" +
-		"('Emma Thompson', 'ethompson', 'emma@example.com');"
+	query := "INSERT INTO users (name, username, email, role) VALUES\n" +
+		"('John Doe', 'johndoe', 'john@example.com', 'admin'),\n" +
+		"('Jane Smith', 'janesmith', 'jane@example.com', 'member'),\n" +
+		"('Bob Johnson', 'bjohnson', 'bob@example.com', 'member'),\n" +
+		"('Alice Williams', 'awilliams', 'alice@example.com', 'member'),\n" +
+		"('Carlos Rodriguez', 'crodriguez', 'carlos@example.com', 'member'),\n" +
+		"('Emma Thompson', 'ethompson', 'emma@example.com', 'viewer');"
+	return db.ExecContext(ctx, query)
+}
+
+func InsertSampleTeams(ctx context.Context, db *sql.DB) (sql.Result, error) {
+	query := "INSERT INTO teams (name) VALUES\n" +
+		"('Platform'),\n" +
+		"('Support');"
+	return db.ExecContext(ctx, query)
+}
+
+func InsertSampleTeamMembers(ctx context.Context, db *sql.DB) (sql.Result, error) {
+	query := "INSERT INTO team_members (team_id, user_id) VALUES\n" +
+		"(1, 1),\n" +
+		"(1, 2),\n" +
+		"(1, 3),\n" +
+		"(1, 4),\n" +
+		"(1, 6),\n" +
+		"(2, 3),\n" +
+		"(2, 5);"
 	return db.ExecContext(ctx, query)
 }
 
@@ -181,27 +344,37 @@ func InsertSampleTaskTypes(ctx context.Context, db *sql.DB) (sql.Result, error)
 	return db.ExecContext(ctx, query)
 }
 
+// defaultWorkflowGuards require a responsible user to go to review, and completed subtasks to be done
+const defaultWorkflowGuards = `[{"ToStatusID": 4, "Kind": "required_field", "Field": "responsible"}, {"ToStatusID": 5, "Kind": "subtasks_completed"}]`
+
+// defaultWorkflowActions make reviews due two days after the task went to review
+const defaultWorkflowActions = `[{"ToStatusID": 4, "Kind": "set_deadline", "DeadlineOffsetHours": 48}]`
+
 func InsertWorkflowDefault(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO workflows (name, statuses, author_id) VALUES\n" +
-		"('Default Workflow', '{\n  \"0\": {\"id\": 1, \"label\": \"Backlog\", \"active\": true},\n  \"1\": {\"id\": 2, \"label\": \"Todo\", \"active\": true},\n  \"2\": {\"id\": 3, \"label\": \"In Progress\", \"active\": true},\n  \"3\": {\"id\": 4, \"label\": \"In Review\", \"active\": true},\n  \"4\": {\"id\": 5, \"label\": \"Done\", \"active\": true}\n}', 1);"
+	query := "INSERT INTO workflows (name, statuses, guards, actions, author_id, team_id) VALUES\n" +
+		"('Default Workflow', '{\n  \"0\": {\"id\": 1, \"label\": \"Backlog\", \"active\": true},\n  \"1\": {\"id\": 2, \"label\": \"Todo\", \"active\": true},\n  \"2\": {\"id\": 3, \"label\": \"In Progress\", \"active\": true},\n  \"3\": {\"id\": 4, \"label\": \"In Review\", \"active\": true},\n  \"4\": {\"id\": 5, \"label\": \"Done\", \"active\": true}\n}', '" + defaultWorkflowGuards + "', '" + defaultWorkflowActions + "', 1, 1);"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertWorkflowAgile(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO workflows (name, statuses, author_id) VALUES\n" +
-		"('Agile Development', '{\n  \"0\": {\"id\": 1, \"label\": \"Backlog\", \"active\": true},\n  \"1\": {\"id\": 2, \"label\": \"Todo\", \"active\": true},\n  \"2\": {\"id\": 3, \"label\": \"In Progress\", \"active\": true},\n  \"3\": {\"id\": 4, \"label\": \"In Review\", \"active\": true},\n  \"4\": {\"id\": 5, \"label\": \"Done\", \"active\": true}\n}', 2);"
+	query := "INSERT INTO workflows (name, statuses, author_id, team_id) VALUES\n" +
+		"('Agile Development', '{\n  \"0\": {\"id\": 1, \"label\": \"Backlog\", \"active\": true},\n  \"1\": {\"id\": 2, \"label\": \"Todo\", \"active\": true},\n  \"2\": {\"id\": 3, \"label\": \"In Progress\", \"active\": true},\n  \"3\": {\"id\": 4, \"label\": \"In Review\", \"active\": true},\n  \"4\": {\"id\": 5, \"label\": \"Done\", \"active\": true}\n}', 2, 1);"
 	return db.ExecContext(ctx, query)
 }
 
+// supportWorkflowGraph lets support tickets go back and forth between In Progress and Blocked,
+// and be cancelled until they are done
+const supportWorkflowGraph = `{"InitialStatusID": 1, "TerminalStatusIDs": [5, 6], "Transitions": [{"From": 1, "To": 2}, {"From": 2, "To": 3}, {"From": 3, "To": 7}, {"From": 7, "To": 3}, {"From": 3, "To": 5}, {"From": 1, "To": 6}, {"From": 2, "To": 6}, {"From": 3, "To": 6}, {"From": 7, "To": 6}]}`
+
 func InsertWorkflowSupport(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO workflows (name, statuses, author_id) VALUES\n" +
-		"('Support Ticket Workflow', '{\n  \"0\": {\"id\": 1, \"label\": \"Backlog\", \"active\": true},\n  \"1\": {\"id\": 2, \"label\": \"Todo\", \"active\": true},\n  \"2\": {\"id\": 3, \"label\": \"In Progress\", \"active\": true},\n  \"3\": {\"id\": 7, \"label\": \"Blocked\", \"active\": true},\n  \"4\": {\"id\": 5, \"label\": \"Done\", \"active\": true}\n}', 3);"
+	query := "INSERT INTO workflows (name, statuses, graph, author_id, team_id) VALUES\n" +
+		"('Support Ticket Workflow', '{\n  \"0\": {\"id\": 1, \"label\": \"Backlog\", \"active\": true},\n  \"1\": {\"id\": 2, \"label\": \"Todo\", \"active\": true},\n  \"2\": {\"id\": 3, \"label\": \"In Progress\", \"active\": true},\n  \"3\": {\"id\": 7, \"label\": \"Blocked\", \"active\": true},\n  \"4\": {\"id\": 5, \"label\": \"Done\", \"active\": true},\n  \"5\": {\"id\": 6, \"label\": \"Cancelled\", \"active\": true}\n}', '" + supportWorkflowGraph + "', 3, 2);"
 	return db.ExecContext(ctx, query)
 }
 
 // Individual task inserts (one function per INSERT statement)
 func InsertTask1(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Implement User Authentication',\n" +
 		"  'Create a complete user authentication system with JWT tokens, password hashing, and session management. Must include login, logout, and refresh token functionality.',\n" +
@@ -211,13 +384,14 @@ func InsertTask1(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  2,\n" +
 		"  1,\n" +
 		"  2,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask2(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Fix Login Bug on Mobile Devices',\n" +
 		"  'Users are reporting that login is failing on mobile browsers. The issue seems to be related to cookie handling on iOS Safari.',\n" +
@@ -227,13 +401,14 @@ func InsertTask2(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  3,\n" +
 		"  1,\n" +
 		"  1,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask3(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Optimize Database Queries',\n" +
 		"  'Review and optimize slow database queries. Add appropriate indexes and consider query refactoring for better performance.',\n" +
@@ -243,13 +418,14 @@ func InsertTask3(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  4,\n" +
 		"  1,\n" +
 		"  4,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask4(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Write API Documentation',\n" +
 		"  'Create comprehensive API documentation including endpoint descriptions, request/response examples, and authentication requirements.',\n" +
@@ -259,13 +435,14 @@ func InsertTask4(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  5,\n" +
 		"  2,\n" +
 		"  4,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask5(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Setup Development Environment',\n" +
 		"  'Configure and document the complete development environment setup for new team members.',\n" +
@@ -275,13 +452,14 @@ func InsertTask5(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  2,\n" +
 		"  1,\n" +
 		"  4,\n" +
+		"  1,\n" +
 		"  true\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask6(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Implement Email Notifications',\n" +
 		"  'Add email notification system for task assignments, deadline reminders, and status updates. Should support multiple email templates.',\n" +
@@ -291,13 +469,14 @@ func InsertTask6(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  6,\n" +
 		"  1,\n" +
 		"  2,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask7(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Implement JWT Token Generation',\n" +
 		"  'Create JWT token generation and validation logic. Include expiration handling and refresh token mechanism.',\n" +
@@ -308,13 +487,14 @@ func InsertTask7(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  2,\n" +
 		"  1,\n" +
 		"  2,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask8(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Implement Password Hashing',\n" +
 		"  'Set up secure password hashing using bcrypt. Create password validation and reset functionality.',\n" +
@@ -325,13 +505,14 @@ func InsertTask8(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  2,\n" +
 		"  1,\n" +
 		"  2,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask9(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Research API Caching Strategies',\n" +
 		"  'Research and evaluate different caching strategies (Redis, Memcached, etc.) for API responses to improve performance.',\n" +
@@ -341,13 +522,14 @@ func InsertTask9(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  4,\n" +
 		"  1,\n" +
 		"  5,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask10(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Implement Payment Processing',\n" +
 		"  'Integrate payment processing gateway (Stripe/PayPal). Blocked waiting for business requirements clarification.',\n" +
@@ -357,13 +539,14 @@ func InsertTask10(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  5,\n" +
 		"  3,\n" +
 		"  2,\n" +
+		"  2,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask11(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Unit Tests for Authentication Module',\n" +
 		"  'Write comprehensive unit tests for the authentication module covering all edge cases and error scenarios.',\n" +
@@ -373,13 +556,14 @@ func InsertTask11(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  3,\n" +
 		"  1,\n" +
 		"  6,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask12(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)\n" +
+	query := "INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)\n" +
 		"VALUES (\n" +
 		"  'Refactor Task Repository Layer',\n" +
 		"  'Refactor the task repository to improve code organization and reduce duplication. Consider implementing repository pattern.',\n" +
@@ -389,6 +573,7 @@ func InsertTask12(ctx context.Context, db *sql.DB) (sql.Result, error) {
 		"  2,\n" +
 		"  1,\n" +
 		"  7,\n" +
+		"  1,\n" +
 		"  false\n" +
 		");"
 	return db.ExecContext(ctx, query)
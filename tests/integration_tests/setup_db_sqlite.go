@@ -3,6 +3,7 @@ package integrationtests
 import (
 	"context"
 	"database/sql"
+	"strings"
 )
 
 // SQLite versions of database setup functions
@@ -15,6 +16,9 @@ func CreateUsersTableSQLite(ctx context.Context, db *sql.DB) error {
 		name TEXT NOT NULL,
 		username TEXT NOT NULL UNIQUE,
 		email TEXT NOT NULL UNIQUE,
+		password_hash TEXT,
+		role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'member', 'viewer')),
+		active BOOLEAN NOT NULL DEFAULT 1,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);`
 	if _, err := db.ExecContext(ctx, query); err != nil {
@@ -41,12 +45,90 @@ func DropUsersTableSQLite(ctx context.Context, db *sql.DB) error {
 	return err
 }
 
+// CreateRefreshTokensTableSQLite creates the refresh_tokens table compatible with SQLite
+func CreateRefreshTokensTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id TEXT PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		expires_at DATETIME NOT NULL,
+		revoked BOOLEAN NOT NULL DEFAULT 0,
+		replaced_by TEXT,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+	);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	indexQuery := `CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);`
+	if _, err := db.ExecContext(ctx, indexQuery); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DropRefreshTokensTableSQLite drops the refresh_tokens table
+func DropRefreshTokensTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS refresh_tokens;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// CreateTeamsTableSQLite creates the teams table compatible with SQLite
+func CreateTeamsTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS teams (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// DropTeamsTableSQLite drops the teams table
+func DropTeamsTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS teams;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// CreateTeamMembersTableSQLite creates the team_members table compatible with SQLite
+func CreateTeamMembersTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS team_members (
+		team_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (team_id, user_id),
+		FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+	);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	indexQuery := `CREATE INDEX IF NOT EXISTS idx_team_members_user_id ON team_members(user_id);`
+	if _, err := db.ExecContext(ctx, indexQuery); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DropTeamMembersTableSQLite drops the team_members table
+func DropTeamMembersTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS team_members;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
 // CreateTaskStatusesTableSQLite creates the task_statuses table compatible with SQLite
 func CreateTaskStatusesTableSQLite(ctx context.Context, db *sql.DB) error {
 	query := `CREATE TABLE IF NOT EXISTS task_statuses (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		label TEXT NOT NULL UNIQUE,
-		active BOOLEAN NOT NULL DEFAULT 1
+		active BOOLEAN NOT NULL DEFAULT 1,
+		version INTEGER NOT NULL DEFAULT 1
 	);`
 	if _, err := db.ExecContext(ctx, query); err != nil {
 		return err
@@ -71,7 +153,11 @@ func DropTaskStatusesTableSQLite(ctx context.Context, db *sql.DB) error {
 func CreateTaskTypesTableSQLite(ctx context.Context, db *sql.DB) error {
 	query := `CREATE TABLE IF NOT EXISTS task_types (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		name TEXT NOT NULL UNIQUE
+		name TEXT NOT NULL,
+		team_id INTEGER,
+		version INTEGER NOT NULL DEFAULT 1,
+		UNIQUE (team_id, name),
+		FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE
 	);`
 	_, err := db.ExecContext(ctx, query)
 	return err
@@ -90,9 +176,16 @@ func CreateWorkflowsTableSQLite(ctx context.Context, db *sql.DB) error {
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		name TEXT NOT NULL,
 		statuses TEXT NOT NULL,
+		sequential BOOLEAN NOT NULL DEFAULT 0,
+		graph TEXT,
+		guards TEXT,
+		actions TEXT,
 		author_id INTEGER NOT NULL,
+		team_id INTEGER NOT NULL,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE RESTRICT ON UPDATE CASCADE
+		version INTEGER NOT NULL DEFAULT 1,
+		FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+		FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE RESTRICT ON UPDATE CASCADE
 	);`
 	if _, err := db.ExecContext(ctx, query); err != nil {
 		return err
@@ -128,13 +221,16 @@ func CreateTasksTableSQLite(ctx context.Context, db *sql.DB) error {
 		responsible_id INTEGER,
 		workflow_id INTEGER NOT NULL,
 		type_id INTEGER NOT NULL,
+		team_id INTEGER NOT NULL,
 		completed BOOLEAN NOT NULL DEFAULT 0,
+		version INTEGER NOT NULL DEFAULT 1,
 		FOREIGN KEY (status_id) REFERENCES task_statuses(id) ON DELETE RESTRICT ON UPDATE CASCADE,
 		FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
 		FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE RESTRICT ON UPDATE CASCADE,
 		FOREIGN KEY (responsible_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE,
 		FOREIGN KEY (workflow_id) REFERENCES workflows(id) ON DELETE RESTRICT ON UPDATE CASCADE,
-		FOREIGN KEY (type_id) REFERENCES task_types(id) ON DELETE RESTRICT ON UPDATE CASCADE
+		FOREIGN KEY (type_id) REFERENCES task_types(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+		FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE RESTRICT ON UPDATE CASCADE
 	);`
 	if _, err := db.ExecContext(ctx, query); err != nil {
 		return err
@@ -148,6 +244,7 @@ func CreateTasksTableSQLite(ctx context.Context, db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_responsible_id ON tasks(responsible_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_workflow_id ON tasks(workflow_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_type_id ON tasks(type_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_team_id ON tasks(team_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_deadline ON tasks(deadline);`,
 		`CREATE INDEX IF NOT EXISTS idx_completed ON tasks(completed);`,
 		`CREATE INDEX IF NOT EXISTS idx_created_at ON tasks(created_at);`,
@@ -172,16 +269,153 @@ func DropTasksTableSQLite(ctx context.Context, db *sql.DB) error {
 	return err
 }
 
+// CreateTaskActionFailuresTableSQLite creates the task_action_failures table compatible with SQLite
+func CreateTaskActionFailuresTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS task_action_failures (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id INTEGER NOT NULL,
+		from_status_id INTEGER NOT NULL,
+		to_status_id INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		error TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE
+	);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	indexQuery := `CREATE INDEX IF NOT EXISTS idx_action_failures_task_id ON task_action_failures(task_id);`
+	_, err := db.ExecContext(ctx, indexQuery)
+	return err
+}
+
+// DropTaskActionFailuresTableSQLite drops the task_action_failures table
+func DropTaskActionFailuresTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS task_action_failures;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// CreateTaskEventsTableSQLite creates the task_events table compatible with SQLite
+func CreateTaskEventsTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS task_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id INTEGER NOT NULL,
+		actor_id INTEGER NULL,
+		field TEXT NOT NULL,
+		old_value TEXT NULL,
+		new_value TEXT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
+		FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE
+	);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	indexQuery := `CREATE INDEX IF NOT EXISTS idx_task_events_task_created ON task_events(task_id, created_at);`
+	_, err := db.ExecContext(ctx, indexQuery)
+	return err
+}
+
+// DropTaskEventsTableSQLite drops the task_events table
+func DropTaskEventsTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS task_events;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// CreateTaskDependenciesTableSQLite creates the task_dependencies table compatible with SQLite
+func CreateTaskDependenciesTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS task_dependencies (
+		blocker_id INTEGER NOT NULL,
+		blocked_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (blocker_id, blocked_id),
+		FOREIGN KEY (blocker_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE,
+		FOREIGN KEY (blocked_id) REFERENCES tasks(id) ON DELETE CASCADE ON UPDATE CASCADE
+	);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	indexQuery := `CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_id ON task_dependencies(blocked_id);`
+	_, err := db.ExecContext(ctx, indexQuery)
+	return err
+}
+
+// DropTaskDependenciesTableSQLite drops the task_dependencies table
+func DropTaskDependenciesTableSQLite(ctx context.Context, db *sql.DB) error {
+	query := "DROP TABLE IF EXISTS task_dependencies;"
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// CreateTaskSearchIndexSQLite creates the tasks_fts FTS5 table backing full-text search, the SQLite
+// equivalent of the MySQL FULLTEXT index, kept in sync with tasks by triggers and filled with existing rows.
+// FTS5 is only compiled in with the sqlite_fts5 build tag, see IsFTS5Unavailable.
+func CreateTaskSearchIndexSQLite(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+			title, description, content='tasks', content_rowid='id'
+		);`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+			INSERT INTO tasks_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+		END;`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+			INSERT INTO tasks_fts(tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+		END;`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE ON tasks BEGIN
+			INSERT INTO tasks_fts(tasks_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+			INSERT INTO tasks_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+		END;`,
+		`INSERT INTO tasks_fts(tasks_fts) VALUES ('rebuild');`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsFTS5Unavailable reports whether err comes from a SQLite build without the FTS5 module
+func IsFTS5Unavailable(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no such module: fts5")
+}
+
 // Sample data inserts (compatible with SQLite)
 
 func InsertSampleUsersSQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO users (name, username, email) VALUES
-		('John Doe', 'johndoe', 'john@example.com'),
-		('Jane Smith', 'janesmith', 'jane@example.com'),
-		('Bob Johnson', 'bjohnson', 'bob@example.com'),
-		('Alice Williams', 'awilliams', 'alice@example.com'),
-		('Carlos Rodriguez', 'crodriguez', 'carlos@example.com'),
-		('Emma Thompson', 'ethompson', 'emma@example.com');`
+	query := `INSERT INTO users (name, username, email, role) VALUES
+		('John Doe', 'johndoe', 'john@example.com', 'admin'),
+		('Jane Smith', 'janesmith', 'jane@example.com', 'member'),
+		('Bob Johnson', 'bjohnson', 'bob@example.com', 'member'),
+		('Alice Williams', 'awilliams', 'alice@example.com', 'member'),
+		('Carlos Rodriguez', 'crodriguez', 'carlos@example.com', 'member'),
+		('Emma Thompson', 'ethompson', 'emma@example.com', 'viewer');`
+	return db.ExecContext(ctx, query)
+}
+
+func InsertSampleTeamsSQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
+	query := `INSERT INTO teams (name) VALUES
+		('Platform'),
+		('Support');`
+	return db.ExecContext(ctx, query)
+}
+
+func InsertSampleTeamMembersSQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
+	query := `INSERT INTO team_members (team_id, user_id) VALUES
+		(1, 1),
+		(1, 2),
+		(1, 3),
+		(1, 4),
+		(1, 6),
+		(2, 3),
+		(2, 5);`
 	return db.ExecContext(ctx, query)
 }
 
@@ -211,27 +445,27 @@ func InsertSampleTaskTypesSQLite(ctx context.Context, db *sql.DB) (sql.Result, e
 }
 
 func InsertWorkflowDefaultSQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO workflows (name, statuses, author_id) VALUES
-		('Default Workflow', '{"0": {"id": 1, "label": "Backlog", "active": true}, "1": {"id": 2, "label": "Todo", "active": true}, "2": {"id": 3, "label": "In Progress", "active": true}, "3": {"id": 4, "label": "In Review", "active": true}, "4": {"id": 5, "label": "Done", "active": true}}', 1);`
+	query := `INSERT INTO workflows (name, statuses, guards, actions, author_id, team_id) VALUES
+		('Default Workflow', '{"0": {"id": 1, "label": "Backlog", "active": true}, "1": {"id": 2, "label": "Todo", "active": true}, "2": {"id": 3, "label": "In Progress", "active": true}, "3": {"id": 4, "label": "In Review", "active": true}, "4": {"id": 5, "label": "Done", "active": true}}', '` + defaultWorkflowGuards + `', '` + defaultWorkflowActions + `', 1, 1);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertWorkflowAgileSQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO workflows (name, statuses, author_id) VALUES
-		('Agile Development', '{"0": {"id": 1, "label": "Backlog", "active": true}, "1": {"id": 2, "label": "Todo", "active": true}, "2": {"id": 3, "label": "In Progress", "active": true}, "3": {"id": 4, "label": "In Review", "active": true}, "4": {"id": 5, "label": "Done", "active": true}}', 2);`
+	query := `INSERT INTO workflows (name, statuses, author_id, team_id) VALUES
+		('Agile Development', '{"0": {"id": 1, "label": "Backlog", "active": true}, "1": {"id": 2, "label": "Todo", "active": true}, "2": {"id": 3, "label": "In Progress", "active": true}, "3": {"id": 4, "label": "In Review", "active": true}, "4": {"id": 5, "label": "Done", "active": true}}', 2, 1);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertWorkflowSupportSQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO workflows (name, statuses, author_id) VALUES
-		('Support Ticket Workflow', '{"0": {"id": 1, "label": "Backlog", "active": true}, "1": {"id": 2, "label": "Todo", "active": true}, "2": {"id": 3, "label": "In Progress", "active": true}, "3": {"id": 7, "label": "Blocked", "active": true}, "4": {"id": 5, "label": "Done", "active": true}}', 3);`
+	query := `INSERT INTO workflows (name, statuses, graph, author_id, team_id) VALUES
+		('Support Ticket Workflow', '{"0": {"id": 1, "label": "Backlog", "active": true}, "1": {"id": 2, "label": "Todo", "active": true}, "2": {"id": 3, "label": "In Progress", "active": true}, "3": {"id": 7, "label": "Blocked", "active": true}, "4": {"id": 5, "label": "Done", "active": true}, "5": {"id": 6, "label": "Cancelled", "active": true}}', '` + supportWorkflowGraph + `', 3, 2);`
 	return db.ExecContext(ctx, query)
 }
 
 // Task inserts (compatible with SQLite)
 
 func InsertTask1SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Implement User Authentication',
 			'Create a complete user authentication system with JWT tokens, password hashing, and session management. Must include login, logout, and refresh token functionality.',
@@ -241,13 +475,14 @@ func InsertTask1SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			2,
 			1,
 			2,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask2SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Fix Login Bug on Mobile Devices',
 			'Users are reporting that login is failing on mobile browsers. The issue seems to be related to cookie handling on iOS Safari.',
@@ -257,13 +492,14 @@ func InsertTask2SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			3,
 			1,
 			1,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask3SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Optimize Database Queries',
 			'Review and optimize slow database queries. Add appropriate indexes and consider query refactoring for better performance.',
@@ -273,13 +509,14 @@ func InsertTask3SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			4,
 			1,
 			4,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask4SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Write API Documentation',
 			'Create comprehensive API documentation including endpoint descriptions, request/response examples, and authentication requirements.',
@@ -289,13 +526,14 @@ func InsertTask4SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			5,
 			2,
 			4,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask5SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Setup Development Environment',
 			'Configure and document the complete development environment setup for new team members.',
@@ -305,13 +543,14 @@ func InsertTask5SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			2,
 			1,
 			4,
+			1,
 			1
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask6SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Implement Email Notifications',
 			'Add email notification system for task assignments, deadline reminders, and status updates. Should support multiple email templates.',
@@ -321,13 +560,14 @@ func InsertTask6SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			6,
 			1,
 			2,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask7SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Implement JWT Token Generation',
 			'Create JWT token generation and validation logic. Include expiration handling and refresh token mechanism.',
@@ -338,13 +578,14 @@ func InsertTask7SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			2,
 			1,
 			2,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask8SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, parent_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Implement Password Hashing',
 			'Set up secure password hashing using bcrypt. Create password validation and reset functionality.',
@@ -355,13 +596,14 @@ func InsertTask8SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			2,
 			1,
 			2,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask9SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Research API Caching Strategies',
 			'Research and evaluate different caching strategies (Redis, Memcached, etc.) for API responses to improve performance.',
@@ -371,13 +613,14 @@ func InsertTask9SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			4,
 			1,
 			5,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask10SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Implement Payment Processing',
 			'Integrate payment processing gateway (Stripe/PayPal). Blocked waiting for business requirements clarification.',
@@ -387,13 +630,14 @@ func InsertTask10SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			5,
 			3,
 			2,
+			2,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask11SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Unit Tests for Authentication Module',
 			'Write comprehensive unit tests for the authentication module covering all edge cases and error scenarios.',
@@ -403,13 +647,14 @@ func InsertTask11SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			3,
 			1,
 			6,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
 func InsertTask12SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
-	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, completed)
+	query := `INSERT INTO tasks (title, description, status_id, author_id, deadline, responsible_id, workflow_id, type_id, team_id, completed)
 		VALUES (
 			'Refactor Task Repository Layer',
 			'Refactor the task repository to improve code organization and reduce duplication. Consider implementing repository pattern.',
@@ -419,14 +664,22 @@ func InsertTask12SQLite(ctx context.Context, db *sql.DB) (sql.Result, error) {
 			2,
 			1,
 			7,
+			1,
 			0
 		);`
 	return db.ExecContext(ctx, query)
 }
 
-// CleanupTables drops all test tables
+// CleanupTables drops all test tables. The search triggers go first: dropping tasks deletes its rows,
+// which would otherwise fire them against the already dropped tasks_fts.
 func CleanupTablesSQLite(ctx context.Context, db *sql.DB) error {
-	tables := []string{"tasks", "workflows", "task_types", "task_statuses", "users"}
+	for _, trigger := range []string{"tasks_fts_insert", "tasks_fts_delete", "tasks_fts_update"} {
+		if _, err := db.ExecContext(ctx, "DROP TRIGGER IF EXISTS "+trigger+";"); err != nil {
+			return err
+		}
+	}
+
+	tables := []string{"task_dependencies", "task_events", "task_action_failures", "tasks_fts", "tasks", "workflows", "task_types", "task_statuses", "team_members", "teams", "refresh_tokens", "users"}
 	for _, table := range tables {
 		query := "DROP TABLE IF EXISTS " + table + ";"
 		if _, err := db.ExecContext(ctx, query); err != nil {
@@ -14,11 +14,19 @@ func SetupTests(db *sql.DB) {
 	CreateTodoDatabase(ctx, db)
 	UseTodoDatabase(ctx, db)
 	CreateUsersTable(ctx, db)
+	CreateRefreshTokensTable(ctx, db)
+	CreateTeamsTable(ctx, db)
+	CreateTeamMembersTable(ctx, db)
 	CreateTaskStatusesTable(ctx, db)
 	CreateTaskTypesTable(ctx, db)
 	CreateWorkflowsTable(ctx, db)
 	CreateTasksTable(ctx, db)
+	CreateTaskActionFailuresTable(ctx, db)
+	CreateTaskEventsTable(ctx, db)
+	CreateTaskDependenciesTable(ctx, db)
 	InsertSampleUsers(ctx, db)
+	InsertSampleTeams(ctx, db)
+	InsertSampleTeamMembers(ctx, db)
 	InsertSampleTaskStatuses(ctx, db)
 	InsertSampleTaskTypes(ctx, db)
 	InsertWorkflowDefault(ctx, db)
@@ -52,6 +60,15 @@ func SetupTestsSQLite(db *sql.DB) error {
 	if err := CreateUsersTableSQLite(ctx, db); err != nil {
 		return err
 	}
+	if err := CreateRefreshTokensTableSQLite(ctx, db); err != nil {
+		return err
+	}
+	if err := CreateTeamsTableSQLite(ctx, db); err != nil {
+		return err
+	}
+	if err := CreateTeamMembersTableSQLite(ctx, db); err != nil {
+		return err
+	}
 	if err := CreateTaskStatusesTableSQLite(ctx, db); err != nil {
 		return err
 	}
@@ -64,11 +81,26 @@ func SetupTestsSQLite(db *sql.DB) error {
 	if err := CreateTasksTableSQLite(ctx, db); err != nil {
 		return err
 	}
+	if err := CreateTaskActionFailuresTableSQLite(ctx, db); err != nil {
+		return err
+	}
+	if err := CreateTaskEventsTableSQLite(ctx, db); err != nil {
+		return err
+	}
+	if err := CreateTaskDependenciesTableSQLite(ctx, db); err != nil {
+		return err
+	}
 
 	// Insert sample data
 	if _, err := InsertSampleUsersSQLite(ctx, db); err != nil {
 		return err
 	}
+	if _, err := InsertSampleTeamsSQLite(ctx, db); err != nil {
+		return err
+	}
+	if _, err := InsertSampleTeamMembersSQLite(ctx, db); err != nil {
+		return err
+	}
 	if _, err := InsertSampleTaskStatusesSQLite(ctx, db); err != nil {
 		return err
 	}
@@ -123,5 +155,10 @@ func SetupTestsSQLite(db *sql.DB) error {
 		return err
 	}
 
+	// Full-text search needs the sqlite_fts5 build tag, search tests skip themselves without it
+	if err := CreateTaskSearchIndexSQLite(ctx, db); err != nil && !IsFTS5Unavailable(err) {
+		return err
+	}
+
 	return nil
 }
@@ -1,8 +1,15 @@
 package integrationtests
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"testing"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
 	"todo-api/internal/infrastructure/database/repositories"
+	"todo-api/internal/infrastructure/notifications"
 )
 
 // ExampleTestWithSQLiteInMemory demonstrates how to use SQLite in-memory for integration tests
@@ -23,7 +30,7 @@ func TestGetTaskById(t *testing.T) {
 
 	taskRepository := repositories.NewTaskRepository(db)
 
-	firstTask, err := taskRepository.GetByID(1)
+	firstTask, err := taskRepository.GetByID(context.Background(), 1, []int64{1})
 	if err != nil {
 		t.Fatalf("Failed to get first task: %v", err)
 	}
@@ -55,7 +62,7 @@ func TestGetAllTasks(t *testing.T) {
 
 	taskRepository := repositories.NewTaskRepository(db)
 
-	allTasks, err := taskRepository.GetAll()
+	allTasks, err := taskRepository.GetAll(context.Background(), []int64{1, 2})
 	if err != nil {
 		t.Fatalf("Failed to get all tasks: %v", err)
 	}
@@ -70,3 +77,689 @@ func TestGetAllTasks(t *testing.T) {
 
 	t.Logf("Successfully queried all tasks from SQLite in-memory database, got %d tasks", len(allTasks))
 }
+
+func TestGetAllTasks_ScopedToTeams(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+
+	supportTasks, err := taskRepository.GetAll(context.Background(), []int64{2})
+	if err != nil {
+		t.Fatalf("Failed to get support tasks: %v", err)
+	}
+	if len(supportTasks) != 1 || supportTasks[0].ID != 10 {
+		t.Errorf("Expected only task 10 for the support team, got %v", supportTasks)
+	}
+
+	noTasks, err := taskRepository.GetAll(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Failed to get tasks without teams: %v", err)
+	}
+	if len(noTasks) != 0 {
+		t.Errorf("Expected no task for a caller without teams, got %d", len(noTasks))
+	}
+
+	if _, err := taskRepository.GetByID(context.Background(), 10, []int64{1}); err == nil {
+		t.Error("Expected task 10 to be hidden from the platform team")
+	}
+
+	if err := taskRepository.Remove(context.Background(), 10, 0, domain.SubtasksCascade, 1, []int64{1}); err == nil {
+		t.Error("Expected removing a task of another team to fail")
+	}
+}
+
+func TestFindTasks(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+	teams := []int64{1, 2}
+
+	page, err := taskRepository.Find(context.Background(), domain.TaskQuery{Sort: "deadline", Limit: 5}, teams)
+	if err != nil {
+		t.Fatalf("Failed to find tasks: %v", err)
+	}
+	if page.Total != 12 || len(page.Tasks) != 5 {
+		t.Fatalf("Expected 5 of 12 tasks, got %d of %d", len(page.Tasks), page.Total)
+	}
+	if page.Tasks[0].ID != 5 {
+		t.Errorf("Expected task 5 to have the earliest deadline, got task %d", page.Tasks[0].ID)
+	}
+
+	lastPage, err := taskRepository.Find(context.Background(), domain.TaskQuery{Sort: "deadline", Descending: true, Limit: 5, Offset: 10}, teams)
+	if err != nil {
+		t.Fatalf("Failed to find tasks: %v", err)
+	}
+	if len(lastPage.Tasks) != 2 || lastPage.Tasks[1].ID != 5 {
+		t.Errorf("Expected the last page to end with task 5, got %v", lastPage.Tasks)
+	}
+
+	completed := false
+	from := time.Date(2026, 2, 19, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	filtered, err := taskRepository.Find(context.Background(), domain.TaskQuery{
+		ResponsibleID: 2,
+		Completed:     &completed,
+		DeadlineFrom:  &from,
+		DeadlineTo:    &to,
+		Limit:         domain.DefaultTaskPageSize,
+	}, teams)
+	if err != nil {
+		t.Fatalf("Failed to find tasks: %v", err)
+	}
+	// Tasks 1, 7 and 8 are assigned to Jane Smith, open, and due between the 19th and the 28th
+	if filtered.Total != 3 {
+		t.Errorf("Expected 3 filtered tasks, got %d", filtered.Total)
+	}
+}
+
+func TestSearchTasks(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	if _, err := db.Exec("SELECT 1 FROM tasks_fts LIMIT 1"); err != nil {
+		t.Skip("SQLite was built without FTS5, run the tests with -tags sqlite_fts5")
+	}
+
+	taskRepository := repositories.NewTaskRepository(db)
+
+	results, err := taskRepository.Search(context.Background(), "login mobile", 10, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("Failed to search tasks: %v", err)
+	}
+	if len(results) == 0 || results[0].Task.ID != 2 {
+		t.Fatalf("Expected task 2 to be the best match, got %v", results)
+	}
+	if results[0].TitleSnippet != "Fix <mark>Login</mark> Bug on <mark>Mobile</mark> Devices" {
+		t.Errorf("Unexpected title highlight %q", results[0].TitleSnippet)
+	}
+
+	// Words match as prefixes, and all of them must match
+	prefixed, err := taskRepository.Search(context.Background(), "log mob", 10, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("Failed to search tasks: %v", err)
+	}
+	if len(prefixed) == 0 || prefixed[0].Task.ID != 2 {
+		t.Errorf("Expected prefixes to find task 2, got %v", prefixed)
+	}
+	partial, err := taskRepository.Search(context.Background(), "login zeppelin", 10, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("Failed to search tasks: %v", err)
+	}
+	if len(partial) != 0 {
+		t.Errorf("Expected no task matching only some words, got %v", partial)
+	}
+
+	// Search follows writes through the triggers and stays within the caller's teams
+	task, err := taskRepository.GetByID(context.Background(), 10, []int64{2})
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	task.Description = "Waiting for the mobile login redesign"
+	if _, err := taskRepository.Update(context.Background(), task, 1, []int64{2}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	scoped, err := taskRepository.Search(context.Background(), "redesign", 10, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to search tasks: %v", err)
+	}
+	if len(scoped) != 0 {
+		t.Errorf("Expected no result outside the caller's teams, got %d", len(scoped))
+	}
+
+	updated, err := taskRepository.Search(context.Background(), "redesign", 10, []int64{2})
+	if err != nil {
+		t.Fatalf("Failed to search tasks: %v", err)
+	}
+	if len(updated) != 1 || updated[0].Task.ID != 10 {
+		t.Errorf("Expected the updated task to be found, got %v", updated)
+	}
+}
+
+func TestGetAllTasksByWorkflow(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+
+	tasks, err := taskRepository.GetAllByWorkflow(context.Background(), 1, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("Failed to get tasks by workflow: %v", err)
+	}
+	if len(tasks) == 0 {
+		t.Fatalf("Expected tasks for workflow 1")
+	}
+	for _, task := range tasks {
+		if task.Workflow.ID != 1 {
+			t.Errorf("Expected only tasks of workflow 1, got task %d in workflow %d", task.ID, task.Workflow.ID)
+		}
+	}
+
+	outsideTeams, err := taskRepository.GetAllByWorkflow(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatalf("Failed to get tasks by workflow: %v", err)
+	}
+	if len(outsideTeams) != 0 {
+		t.Errorf("Expected no tasks without team membership, got %d", len(outsideTeams))
+	}
+}
+
+func TestGetExpandedTask(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+
+	expansion := domain.TaskExpansion{Status: true, Type: true, Workflow: true, Parent: true, Author: true, Responsible: true}
+	task, err := taskRepository.GetExpanded(context.Background(), 7, expansion, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get expanded task: %v", err)
+	}
+
+	if task.Status.Label == "" {
+		t.Errorf("Expected the status label to be loaded, got %+v", task.Status)
+	}
+	if task.Type.Name == "" {
+		t.Errorf("Expected the type name to be loaded, got %+v", task.Type)
+	}
+	if task.Workflow.Name == "" || len(task.Workflow.Statuses) == 0 {
+		t.Errorf("Expected the workflow to be loaded, got %+v", task.Workflow)
+	}
+	if task.Parent == nil || task.Parent.Title == "" {
+		t.Errorf("Expected the parent task to be loaded, got %+v", task.Parent)
+	}
+	if task.Author == nil || task.Author.ID != task.AuthorID || task.Author.Username == "" {
+		t.Errorf("Expected the author to be loaded, got %+v", task.Author)
+	}
+	if task.Responsible == nil || task.Responsible.ID != task.ResponsibleID {
+		t.Errorf("Expected the responsible user to be loaded, got %+v", task.Responsible)
+	}
+
+	plain, err := taskRepository.GetByID(context.Background(), 7, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if plain.Status.Label != "" || plain.Author != nil || plain.Parent == nil || plain.Parent.Title != "" {
+		t.Errorf("Expected only IDs without expansion, got %+v", plain)
+	}
+
+	page, err := taskRepository.Find(context.Background(), domain.TaskQuery{Limit: 5, Expand: domain.TaskExpansion{Author: true}}, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to find tasks: %v", err)
+	}
+	for _, task := range page.Tasks {
+		if task.Author == nil || task.Author.ID != task.AuthorID {
+			t.Errorf("Expected every task of the page to carry its author, got %+v", task)
+		}
+	}
+}
+
+func TestGetExpandedTask_HidesOtherTeams(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	// Task 7 of team 1 gets the parent, workflow and type of team 2, as a member of both teams could arrange
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "INSERT INTO task_types (name, team_id) VALUES ('Incident', 2)"); err != nil {
+		t.Fatalf("Failed to insert task type: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "UPDATE tasks SET parent_id = 10, workflow_id = 3, type_id = 8 WHERE id = 7"); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	taskRepository := repositories.NewTaskRepository(db)
+	expansion := domain.TaskExpansion{Type: true, Workflow: true, Parent: true}
+
+	task, err := taskRepository.GetExpanded(ctx, 7, expansion, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get expanded task: %v", err)
+	}
+	if task.Parent == nil || task.Parent.ID != 10 || task.Parent.Title != "" || task.Parent.Description != "" {
+		t.Errorf("Expected only the ID of a parent in another team, got %+v", task.Parent)
+	}
+	if task.Workflow.ID != 3 || task.Workflow.Name != "" || len(task.Workflow.Statuses) != 0 {
+		t.Errorf("Expected only the ID of a workflow in another team, got %+v", task.Workflow)
+	}
+	if task.Type.ID != 8 || task.Type.Name != "" {
+		t.Errorf("Expected only the ID of a type in another team, got %+v", task.Type)
+	}
+
+	page, err := taskRepository.Find(ctx, domain.TaskQuery{Limit: 20, Expand: expansion}, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to find tasks: %v", err)
+	}
+	for _, found := range page.Tasks {
+		if found.ID == 7 && (found.Parent.Title != "" || found.Workflow.Name != "") {
+			t.Errorf("Expected the page to hide the relations in another team, got %+v", found)
+		}
+	}
+
+	both, err := taskRepository.GetExpanded(ctx, 7, expansion, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("Failed to get expanded task: %v", err)
+	}
+	if both.Parent == nil || both.Parent.Title == "" || both.Workflow.Name == "" || both.Type.Name != "Incident" {
+		t.Errorf("Expected the relations to be loaded for a member of both teams, got %+v", both)
+	}
+}
+
+func TestGetAllTasksByParent(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+
+	subtasks, err := taskRepository.GetAllByParent(context.Background(), 1, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get subtasks: %v", err)
+	}
+	if len(subtasks) == 0 {
+		t.Fatalf("Expected subtasks of task 1")
+	}
+	for _, subtask := range subtasks {
+		if subtask.Parent == nil || subtask.Parent.ID != 1 {
+			t.Errorf("Expected only children of task 1, got %+v", subtask.Parent)
+		}
+	}
+}
+
+func TestUpdateTaskRecordsHistory(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+
+	task, err := taskRepository.GetByID(context.Background(), 1, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	oldTitle := task.Title
+	task.Title = "Implement OAuth Authentication"
+	task.AssignTo(3)
+	if task, err = taskRepository.Update(context.Background(), task, 2, []int64{1}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	// Storing the task unchanged records nothing
+	if _, err := taskRepository.Update(context.Background(), task, 2, []int64{1}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	// A task outside the caller's teams is neither updated nor given events
+	task.Title = "Hijacked"
+	if _, err := taskRepository.Update(context.Background(), task, 4, []int64{2}); err == nil {
+		t.Fatalf("Expected a task outside the caller's teams not to be updated")
+	}
+
+	history, err := taskRepository.GetHistory(context.Background(), 1, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get task history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", history)
+	}
+	if history[0].Field != "title" || *history[0].OldValue != oldTitle || *history[0].NewValue != "Implement OAuth Authentication" {
+		t.Errorf("Unexpected title event %+v", history[0])
+	}
+	if history[1].Field != "responsible" || *history[1].NewValue != "3" || history[1].ActorID != 2 {
+		t.Errorf("Unexpected responsible event %+v", history[1])
+	}
+
+	scoped, err := taskRepository.GetHistory(context.Background(), 1, []int64{2})
+	if err != nil {
+		t.Fatalf("Failed to get task history: %v", err)
+	}
+	if len(scoped) != 0 {
+		t.Errorf("Expected no history outside the caller's teams, got %d events", len(scoped))
+	}
+}
+
+func TestCreateTaskRejectsDanglingReferences(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+
+	task, err := taskRepository.GetByID(context.Background(), 9, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	task.ID = 0
+	task.Title = "Unassigned copy"
+	task.ResponsibleID = 0
+
+	// A task without a responsible user is stored with a NULL reference
+	created, err := taskRepository.Create(context.Background(), task)
+	if err != nil {
+		t.Fatalf("Failed to create unassigned task: %v", err)
+	}
+	stored, err := taskRepository.GetByID(context.Background(), created.ID, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get created task: %v", err)
+	}
+	if stored.ResponsibleID != 0 {
+		t.Errorf("Expected no responsible user, got %d", stored.ResponsibleID)
+	}
+
+	task.Status = entities.TaskStatus{ID: 9999}
+	_, err = taskRepository.Create(context.Background(), task)
+	var invalid *domain.InvalidReferenceError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected an invalid reference error, got %v", err)
+	}
+
+	stored.Type = entities.TaskType{ID: 9999}
+	_, err = taskRepository.Update(context.Background(), stored, 1, []int64{1})
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected an invalid reference error on update, got %v", err)
+	}
+}
+
+func TestSubtaskTreeAndRemovalPolicies(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+	teamIDs := []int64{1}
+
+	// Task 1 has the subtasks 7 and 8, a subtask of 7 makes the tree two levels deep
+	grandchild, err := taskRepository.GetByID(context.Background(), 7, teamIDs)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	grandchild.Title = "Write the login form tests"
+	grandchild.Parent = &entities.Task{ID: 7}
+	if grandchild, err = taskRepository.Create(context.Background(), grandchild); err != nil {
+		t.Fatalf("Failed to create subtask: %v", err)
+	}
+
+	descendants, err := taskRepository.GetDescendants(context.Background(), 1, teamIDs)
+	if err != nil {
+		t.Fatalf("Failed to get descendants: %v", err)
+	}
+	if len(descendants) != 3 {
+		t.Fatalf("Expected 3 descendants of task 1, got %+v", descendants)
+	}
+
+	// Task 1 cannot move under the subtask of its subtask 7
+	root, err := taskRepository.GetByID(context.Background(), 1, teamIDs)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	root.Parent = &entities.Task{ID: grandchild.ID}
+	var invalid *domain.InvalidReferenceError
+	if _, err := taskRepository.Update(context.Background(), root, 2, teamIDs); !errors.As(err, &invalid) || invalid.Field != "parent" {
+		t.Fatalf("Expected the loop through task 7 to be rejected, got %v", err)
+	}
+
+	if err := taskRepository.Remove(context.Background(), 1, 0, domain.SubtasksReject, 2, teamIDs); !errors.Is(err, domain.ErrHasSubtasks) {
+		t.Fatalf("Expected the subtasks to keep task 1, got %v", err)
+	}
+
+	if err := taskRepository.Remove(context.Background(), 7, 0, domain.SubtasksPromote, 2, teamIDs); err != nil {
+		t.Fatalf("Failed to remove task 7: %v", err)
+	}
+	promoted, err := taskRepository.GetByID(context.Background(), grandchild.ID, teamIDs)
+	if err != nil {
+		t.Fatalf("Expected the subtask of task 7 to be kept: %v", err)
+	}
+	if promoted.Parent == nil || promoted.Parent.ID != 1 {
+		t.Fatalf("Expected the subtask to move up to task 1, got %+v", promoted.Parent)
+	}
+	history, err := taskRepository.GetHistory(context.Background(), grandchild.ID, teamIDs)
+	if err != nil || len(history) != 1 || history[0].Field != "parent" || history[0].ActorID != 2 {
+		t.Fatalf("Expected the promotion to be recorded, got %+v (%v)", history, err)
+	}
+
+	if err := taskRepository.Remove(context.Background(), 1, 0, domain.SubtasksCascade, 2, teamIDs); err != nil {
+		t.Fatalf("Failed to remove task 1: %v", err)
+	}
+	for _, id := range []int64{8, grandchild.ID} {
+		if _, err := taskRepository.GetByID(context.Background(), id, teamIDs); err == nil {
+			t.Errorf("Expected subtask %d to be removed with task 1", id)
+		}
+	}
+}
+
+func TestTaskDependencies(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+	dependencyRepository := repositories.NewTaskDependencyRepository(db)
+	service := domain.NewTaskService(taskRepository, repositories.NewWorkflowRepository(db), repositories.NewTaskStatusRepository(db),
+		repositories.NewTaskTypeRepository(db), repositories.NewUserRepository(db), dependencyRepository,
+		repositories.NewActionFailureRepository(db), notifications.NewLogNotifier())
+	teamIDs := []int64{1}
+
+	// Task 2 blocks task 3, which blocks task 4; task 10 belongs to team 2 and also blocks task 3
+	for _, dependency := range [][2]int64{{2, 3}, {3, 4}} {
+		if _, err := service.AddDependency(context.Background(), dependency[1], dependency[0], teamIDs); err != nil {
+			t.Fatalf("Failed to add dependency %v: %v", dependency, err)
+		}
+	}
+	if _, err := dependencyRepository.Add(context.Background(), entities.TaskDependency{BlockerID: 10, BlockedID: 3, CreatedAt: entities.Now()}); err != nil {
+		t.Fatalf("Failed to add dependency across teams: %v", err)
+	}
+
+	if _, err := service.AddDependency(context.Background(), 3, 2, teamIDs); !errors.Is(err, domain.ErrDependencyExists) {
+		t.Fatalf("Expected the dependency to exist already, got %v", err)
+	}
+	var cycle *entities.DependencyCycleError
+	if _, err := service.AddDependency(context.Background(), 2, 4, teamIDs); !errors.As(err, &cycle) {
+		t.Fatalf("Expected task 4 blocking task 2 to be a cycle, got %v", err)
+	}
+	if _, err := service.AddDependency(context.Background(), 3, 10, teamIDs); err == nil {
+		t.Fatalf("Expected a task of another team not to be usable as a blocker")
+	}
+	// Cycles are checked against the dependencies of every team: 10 blocks 3, which blocks 4
+	if _, err := dependencyRepository.Add(context.Background(), entities.TaskDependency{BlockerID: 4, BlockedID: 10, CreatedAt: entities.Now()}); !errors.As(err, &cycle) {
+		t.Fatalf("Expected task 4 blocking task 10 to be a cycle, got %v", err)
+	}
+
+	// Task 10 keeps task 3 open even for callers who cannot see it, it is only named
+	states, err := dependencyRepository.GetBlockerStates(context.Background(), 3)
+	if err != nil || len(states) != 2 || states[1].ID != 10 || states[1].Completed || states[1].Title != "" {
+		t.Fatalf("Expected the blockers of every team, by ID only, got %+v (%v)", states, err)
+	}
+	var blockedErr *entities.BlockedTaskError
+	if _, _, err := service.Transition(context.Background(), 3, 5, 2, teamIDs); !errors.As(err, &blockedErr) || !slices.Contains(blockedErr.BlockerIDs, 10) {
+		t.Fatalf("Expected completing task 3 to be blocked by task 10, got %v", err)
+	}
+
+	blockers, blocked, err := service.Dependencies(context.Background(), 3, teamIDs)
+	if err != nil {
+		t.Fatalf("Failed to get dependencies: %v", err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != 2 || len(blocked) != 1 || blocked[0].ID != 4 {
+		t.Fatalf("Expected task 3 to be blocked by task 2 only and to block task 4, got %+v and %+v", blockers, blocked)
+	}
+	all, err := dependencyRepository.GetAll(context.Background(), teamIDs)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("Expected the 2 dependencies between team 1 tasks, got %+v (%v)", all, err)
+	}
+
+	if err := service.RemoveDependency(context.Background(), 3, 2, teamIDs); err != nil {
+		t.Fatalf("Failed to remove dependency: %v", err)
+	}
+	if err := service.RemoveDependency(context.Background(), 3, 2, teamIDs); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Expected the removed dependency to be gone, got %v", err)
+	}
+
+	// Removing a task removes its dependencies along with it
+	if err := taskRepository.Remove(context.Background(), 4, 0, domain.SubtasksCascade, 2, teamIDs); err != nil {
+		t.Fatalf("Failed to remove task 4: %v", err)
+	}
+	if blocked, err := dependencyRepository.GetBlocked(context.Background(), 3, teamIDs); err != nil || len(blocked) != 0 {
+		t.Fatalf("Expected the dependencies of task 4 to be removed, got %+v (%v)", blocked, err)
+	}
+}
+
+func TestVersionsRejectStaleWrites(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskRepository := repositories.NewTaskRepository(db)
+	teamIDs := []int64{1}
+
+	task, err := taskRepository.GetByID(context.Background(), 2, teamIDs)
+	if err != nil || task.Version != 1 {
+		t.Fatalf("Expected a seeded task at version 1, got %d (%v)", task.Version, err)
+	}
+	stale := task
+	task.Title = "First writer"
+	if task, err = taskRepository.Update(context.Background(), task, 2, teamIDs); err != nil || task.Version != 2 {
+		t.Fatalf("Expected the update to move the task to version 2, got %d (%v)", task.Version, err)
+	}
+	stale.Title = "Second writer"
+	if _, err := taskRepository.Update(context.Background(), stale, 3, teamIDs); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("Expected an update based on version 1 to conflict, got %v", err)
+	}
+	if err := taskRepository.Remove(context.Background(), 2, 1, domain.SubtasksReject, 2, teamIDs); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("Expected a removal based on version 1 to conflict, got %v", err)
+	}
+	if stored, err := taskRepository.GetByID(context.Background(), 2, teamIDs); err != nil || stored.Title != "First writer" {
+		t.Fatalf("Expected the first write to be kept, got %+v (%v)", stored, err)
+	}
+	if err := taskRepository.Remove(context.Background(), 2, 2, domain.SubtasksReject, 2, teamIDs); err != nil {
+		t.Fatalf("Failed to remove the task at its current version: %v", err)
+	}
+
+	statusRepository := repositories.NewTaskStatusRepository(db)
+	status, err := statusRepository.GetByID(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	status.Rename("Renamed")
+	if status, err = statusRepository.Update(context.Background(), status); err != nil || status.Version != 2 {
+		t.Fatalf("Expected the update to move the status to version 2, got %d (%v)", status.Version, err)
+	}
+	status.Version = 1
+	if _, err := statusRepository.Update(context.Background(), status); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("Expected a status update based on version 1 to conflict, got %v", err)
+	}
+	if err := statusRepository.Remove(context.Background(), 5, 1); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("Expected a status removal based on version 1 to conflict, got %v", err)
+	}
+}
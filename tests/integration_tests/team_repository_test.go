@@ -0,0 +1,116 @@
+package integrationtests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/database/repositories"
+)
+
+func TestTeamMembership(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	teamRepository := repositories.NewTeamRepository(db)
+
+	teamIDs, err := teamRepository.GetTeamIDsByMember(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Failed to get team ids: %v", err)
+	}
+	if len(teamIDs) != 2 {
+		t.Errorf("Expected Bob Johnson to be in 2 teams, got %v", teamIDs)
+	}
+
+	created, err := teamRepository.Create(context.Background(), entities.NewTeam("Design"))
+	if err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+
+	if _, err := teamRepository.Create(context.Background(), entities.NewTeam("Platform")); !errors.Is(err, domain.ErrTeamNameTaken) {
+		t.Errorf("Expected ErrTeamNameTaken, got %v", err)
+	}
+
+	if err := teamRepository.AddMember(context.Background(), created.ID, 5); err != nil {
+		t.Fatalf("Failed to add member: %v", err)
+	}
+	if err := teamRepository.AddMember(context.Background(), created.ID, 5); !errors.Is(err, domain.ErrAlreadyTeamMember) {
+		t.Errorf("Expected ErrAlreadyTeamMember, got %v", err)
+	}
+
+	members, err := teamRepository.GetMembers(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get members: %v", err)
+	}
+	if len(members) != 1 || members[0].Username != "crodriguez" {
+		t.Errorf("Expected crodriguez as only member, got %v", members)
+	}
+
+	if err := teamRepository.RemoveMember(context.Background(), created.ID, 5); err != nil {
+		t.Fatalf("Failed to remove member: %v", err)
+	}
+	if err := teamRepository.RemoveMember(context.Background(), created.ID, 5); err == nil {
+		t.Error("Expected removing a missing member to fail")
+	}
+}
+
+func TestTaskTypesSharedAndTeamOwned(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	taskTypeRepository := repositories.NewTaskTypeRepository(db)
+
+	created, err := taskTypeRepository.Create(context.Background(), entities.TaskType{Name: "Incident", TeamID: 2})
+	if err != nil {
+		t.Fatalf("Failed to create task type: %v", err)
+	}
+
+	platformTypes, err := taskTypeRepository.GetAll(context.Background(), []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get task types: %v", err)
+	}
+	if len(platformTypes) != 7 {
+		t.Errorf("Expected the 7 shared task types for the platform team, got %d", len(platformTypes))
+	}
+
+	supportTypes, err := taskTypeRepository.GetAll(context.Background(), []int64{2})
+	if err != nil {
+		t.Fatalf("Failed to get task types: %v", err)
+	}
+	if len(supportTypes) != 8 {
+		t.Errorf("Expected 8 task types for the support team, got %d", len(supportTypes))
+	}
+
+	if _, err := taskTypeRepository.GetByID(context.Background(), created.ID, []int64{1}); err == nil {
+		t.Error("Expected the support task type to be hidden from the platform team")
+	}
+
+	// Names are only unique within a team
+	if _, err := taskTypeRepository.Create(context.Background(), entities.TaskType{Name: "Incident", TeamID: 1}); err != nil {
+		t.Errorf("Expected the platform team to reuse the name of a support task type, got %v", err)
+	}
+	if _, err := taskTypeRepository.Create(context.Background(), entities.TaskType{Name: "Incident", TeamID: 2}); !errors.Is(err, domain.ErrTaskTypeNameTaken) {
+		t.Errorf("Expected ErrTaskTypeNameTaken, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package integrationtests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/database/repositories"
+)
+
+func TestCreateUserConflicts(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	userRepository := repositories.NewUserRepository(db)
+
+	// Users without a password cannot fail to be hashed
+	user, _ := entities.NewUser("New User", "newuser", "new@example.com", "")
+	created, err := userRepository.Create(context.Background(), user)
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if created.ID == 0 {
+		t.Error("Expected created user to have an ID")
+	}
+
+	user, _ = entities.NewUser("Other", "johndoe", "other@example.com", "")
+	_, err = userRepository.Create(context.Background(), user)
+	if !errors.Is(err, domain.ErrUsernameTaken) {
+		t.Errorf("Expected ErrUsernameTaken, got %v", err)
+	}
+
+	user, _ = entities.NewUser("Other", "other", "john@example.com", "")
+	_, err = userRepository.Create(context.Background(), user)
+	if !errors.Is(err, domain.ErrEmailTaken) {
+		t.Errorf("Expected ErrEmailTaken, got %v", err)
+	}
+}
+
+func TestDeactivateUser(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	userRepository := repositories.NewUserRepository(db)
+
+	if err := userRepository.Deactivate(context.Background(), 2); err != nil {
+		t.Fatalf("Failed to deactivate user: %v", err)
+	}
+
+	user, err := userRepository.GetByID(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if user.Active {
+		t.Error("Expected user to be inactive after deactivation")
+	}
+
+	if err := userRepository.Deactivate(context.Background(), 999); err == nil {
+		t.Error("Expected an error when deactivating an unknown user")
+	}
+}
@@ -0,0 +1,241 @@
+package integrationtests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/database/repositories"
+)
+
+func TestWorkflowGraphRoundTrip(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	workflowRepository := repositories.NewWorkflowRepository(db)
+
+	support, err := workflowRepository.GetByID(context.Background(), 3, []int64{2})
+	if err != nil {
+		t.Fatalf("Failed to get workflow: %v", err)
+	}
+	if support.Graph == nil || support.Graph.InitialStatusID != 1 || len(support.Graph.TerminalStatusIDs) != 2 {
+		t.Fatalf("Expected the seeded graph of the support workflow, got %+v", support.Graph)
+	}
+	if err := support.ValidateGraph(); err != nil {
+		t.Errorf("Expected the seeded graph to be valid, got %v", err)
+	}
+
+	linear, err := workflowRepository.GetByID(context.Background(), 1, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get workflow: %v", err)
+	}
+	if linear.Graph != nil {
+		t.Errorf("Expected no graph for an ordered workflow, got %+v", linear.Graph)
+	}
+	if len(linear.Guards) != 2 || linear.Guards[0].Field != "responsible" {
+		t.Errorf("Expected the seeded guards of the default workflow, got %+v", linear.Guards)
+	}
+
+	linear.Graph = &entities.WorkflowGraph{
+		InitialStatusID:   1,
+		TerminalStatusIDs: []int64{5},
+		Transitions:       []entities.Transition{{From: 1, To: 2}, {From: 2, To: 3}, {From: 3, To: 4}, {From: 4, To: 5}},
+	}
+	if _, err := workflowRepository.Update(context.Background(), linear, nil, []int64{1}); err != nil {
+		t.Fatalf("Failed to update workflow: %v", err)
+	}
+
+	updated, err := workflowRepository.GetByID(context.Background(), 1, []int64{1})
+	if err != nil {
+		t.Fatalf("Failed to get workflow: %v", err)
+	}
+	if updated.Graph == nil || len(updated.Graph.Transitions) != 4 || !updated.Graph.Allows(4, 5) {
+		t.Errorf("Expected the graph to be stored, got %+v", updated.Graph)
+	}
+}
+
+func TestActionFailureRepository(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	failureRepository := repositories.NewActionFailureRepository(db)
+
+	none, err := failureRepository.GetAllByTask(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Failed to get action failures: %v", err)
+	}
+	if none == nil || len(none) != 0 {
+		t.Errorf("Expected an empty list of action failures, got %+v", none)
+	}
+
+	action := entities.TransitionAction{ToStatusID: 4, Kind: entities.ActionNotifyWatchers, WatcherIDs: []int64{2}}
+	for _, message := range []string{"mail server down", "mail server still down"} {
+		if _, err := failureRepository.Record(context.Background(), entities.NewActionFailure(1, action, 3, errors.New(message))); err != nil {
+			t.Fatalf("Failed to record action failure: %v", err)
+		}
+	}
+
+	failures, err := failureRepository.GetAllByTask(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Failed to get action failures: %v", err)
+	}
+	if len(failures) != 2 || failures[0].Error != "mail server still down" {
+		t.Fatalf("Expected the latest failure first, got %+v", failures)
+	}
+	if failures[0].Action != entities.ActionNotifyWatchers || failures[0].FromStatusID != 3 || failures[0].ToStatusID != 4 {
+		t.Errorf("Unexpected action failure %+v", failures[0])
+	}
+}
+
+func TestWorkflowChangesMigrateTasks(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	workflowRepository := repositories.NewWorkflowRepository(db)
+	taskRepository := repositories.NewTaskRepository(db)
+	teams := []int64{1}
+
+	// Task 4 is the only task of the agile workflow, in Todo (2)
+	agile, err := workflowRepository.GetByID(context.Background(), 2, teams)
+	if err != nil {
+		t.Fatalf("Failed to get workflow: %v", err)
+	}
+	delete(agile.Statuses, 1)
+
+	var orphaned *entities.OrphanedTasksError
+	if _, err := workflowRepository.Update(context.Background(), agile, nil, teams); !errors.As(err, &orphaned) || len(orphaned.StatusIDs) != 1 || orphaned.StatusIDs[0] != 2 {
+		t.Fatalf("Expected removing a status in use to be rejected, got %v", err)
+	}
+	if unchanged, _ := workflowRepository.GetByID(context.Background(), 2, teams); len(unchanged.Statuses) != 5 {
+		t.Fatalf("Expected a rejected update to leave the workflow unchanged")
+	}
+
+	remap := &entities.WorkflowMigration{StatusMapping: map[int64]int64{2: 1}, ActorID: 2}
+	if _, err := workflowRepository.Update(context.Background(), agile, remap, teams); err != nil {
+		t.Fatalf("Failed to update workflow with a status mapping: %v", err)
+	}
+	task, err := taskRepository.GetByID(context.Background(), 4, teams)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if task.Status.ID != 1 {
+		t.Errorf("Expected the task to be moved to Backlog, got status %d", task.Status.ID)
+	}
+	history, err := taskRepository.GetHistory(context.Background(), 4, teams)
+	if err != nil {
+		t.Fatalf("Failed to get task history: %v", err)
+	}
+	if len(history) != 1 || history[0].Field != "status" || *history[0].NewValue != "1" || history[0].ActorID != 2 {
+		t.Errorf("Expected the migration to be recorded in the task history, got %+v", history)
+	}
+
+	// Removing the workflow requires moving its tasks to another one
+	if err := workflowRepository.Remove(context.Background(), 2, 0, nil, teams); !errors.As(err, &orphaned) {
+		t.Fatalf("Expected removing a workflow in use to be rejected, got %v", err)
+	}
+	if err := workflowRepository.Remove(context.Background(), 2, 0, &entities.WorkflowMigration{TargetWorkflowID: 2}, teams); !errors.Is(err, entities.ErrInvalidMigration) {
+		t.Fatalf("Expected a migration to the removed workflow to be rejected, got %v", err)
+	}
+	if err := workflowRepository.Remove(context.Background(), 2, 0, &entities.WorkflowMigration{TargetWorkflowID: 1}, teams); err != nil {
+		t.Fatalf("Failed to remove workflow with a migration: %v", err)
+	}
+	if _, err := workflowRepository.GetByID(context.Background(), 2, teams); err == nil {
+		t.Errorf("Expected the workflow to be removed")
+	}
+	if task, _ := taskRepository.GetByID(context.Background(), 4, teams); task.Workflow.ID != 1 || task.Status.ID != 1 {
+		t.Errorf("Expected the task to be moved to the default workflow, got %+v", task)
+	}
+
+	// A mapping to a status outside the target moves nothing
+	invalid := entities.WorkflowMigration{SourceWorkflowID: 1, StatusMapping: map[int64]int64{5: 99}}
+	if _, err := workflowRepository.Migrate(context.Background(), invalid, teams); !errors.Is(err, entities.ErrInvalidMigration) {
+		t.Fatalf("Expected a mapping outside the workflow to be rejected, got %v", err)
+	}
+
+	// Task 5 is the only done task of the default workflow, reopening it clears Completed
+	reopen := entities.WorkflowMigration{SourceWorkflowID: 1, StatusMapping: map[int64]int64{5: 4}}
+	migrated, err := workflowRepository.Migrate(context.Background(), reopen, teams)
+	if err != nil {
+		t.Fatalf("Failed to migrate tasks: %v", err)
+	}
+	if migrated != 1 {
+		t.Errorf("Expected 1 migrated task, got %d", migrated)
+	}
+	if task, _ := taskRepository.GetByID(context.Background(), 5, teams); task.Status.ID != 4 || task.Completed {
+		t.Errorf("Expected the task to be reopened in review, got %+v", task)
+	}
+}
+
+func TestMigrationKeepsBlockedTasksOpen(t *testing.T) {
+	db, err := InitializeTestDatabase(TestDatabaseConfig{
+		Type: "sqlite",
+		Path: "", // empty path means in-memory
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize test database: %v", err)
+	}
+	defer func() {
+		if err := CleanupTestDatabase(db, "sqlite"); err != nil {
+			t.Logf("Cleanup error: %v", err)
+		}
+	}()
+
+	workflowRepository := repositories.NewWorkflowRepository(db)
+	taskRepository := repositories.NewTaskRepository(db)
+	dependencyRepository := repositories.NewTaskDependencyRepository(db)
+	teams := []int64{1}
+
+	// Task 4, the only task of the agile workflow, is blocked by the open task 10 of team 2
+	if _, err := dependencyRepository.Add(context.Background(), entities.TaskDependency{BlockerID: 10, BlockedID: 4, CreatedAt: entities.Now()}); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	complete := entities.WorkflowMigration{SourceWorkflowID: 2, StatusMapping: map[int64]int64{2: 5}}
+	var blocked *entities.BlockedTaskError
+	if _, err := workflowRepository.Migrate(context.Background(), complete, teams); !errors.As(err, &blocked) || len(blocked.BlockerIDs) != 1 || blocked.BlockerIDs[0] != 10 {
+		t.Fatalf("Expected completing a blocked task by migration to be rejected, got %v", err)
+	}
+	if task, _ := taskRepository.GetByID(context.Background(), 4, teams); task.Status.ID != 2 || task.Completed {
+		t.Fatalf("Expected a rejected migration to leave the task unchanged, got %+v", task)
+	}
+
+	if _, err := db.ExecContext(context.Background(), "UPDATE tasks SET completed = 1 WHERE id = 10"); err != nil {
+		t.Fatalf("Failed to complete task 10: %v", err)
+	}
+	if _, err := workflowRepository.Migrate(context.Background(), complete, teams); err != nil {
+		t.Fatalf("Failed to migrate tasks: %v", err)
+	}
+	if task, _ := taskRepository.GetByID(context.Background(), 4, teams); task.Status.ID != 5 || !task.Completed {
+		t.Errorf("Expected the task to be completed once its blocker is, got %+v", task)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"todo-api/internal/domain"
 	"todo-api/internal/infrastructure/api/handlers"
 	"todo-api/internal/infrastructure/database/repositories"
 	"todo-api/internal/middleware"
@@ -121,10 +122,11 @@ func TestHandlerSecurityHeaders(t *testing.T) {
 
 	// Mock repository
 	mockRepo := &repositories.TaskRepository{}
-	handler := handlers.NewTaskHandler(mockRepo)
+	handler := handlers.NewTaskHandler(domain.NewTaskService(mockRepo, nil, nil, nil, nil, nil, nil, nil))
 
 	router := gin.New()
 	router.Use(middleware.SecurityHeaders())
+	router.Use(middleware.ErrorHandler())
 	router.POST("/todo", handler.CreateTask)
 
 	w := httptest.NewRecorder()
@@ -136,7 +138,7 @@ func TestHandlerSecurityHeaders(t *testing.T) {
 	// Even on error, security headers should be present
 	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
 	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
-	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
 }
 
 // TestCachePreventionHeaders tests that cache prevention headers are set correctly
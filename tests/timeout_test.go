@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-api/internal/infrastructure/api"
+	"todo-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout_SetsRequestDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Timeout(time.Minute))
+
+	var deadline time.Time
+	var hasDeadline bool
+	router.GET("/todo", func(c *gin.Context) {
+		deadline, hasDeadline = c.Request.Context().Deadline()
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/todo", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+}
+
+func TestTimeout_ExpiredRequestAnswers504(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.Timeout(10 * time.Millisecond))
+	router.GET("/todo", func(c *gin.Context) {
+		// Stands for a query the database gives up on once the request context expires
+		<-c.Request.Context().Done()
+		_ = c.Error(c.Request.Context().Err())
+		c.Abort()
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/todo", nil)
+	router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, api.ERROR_CODE_TIMEOUT, body["code"])
+	assert.Equal(t, "Gateway Timeout", body["title"])
+}
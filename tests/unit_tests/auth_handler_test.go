@@ -0,0 +1,223 @@
+package unittests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockRefreshTokenRepo struct {
+	tokens map[string]entities.RefreshToken
+}
+
+func newMockRefreshTokenRepo() *mockRefreshTokenRepo {
+	return &mockRefreshTokenRepo{tokens: map[string]entities.RefreshToken{}}
+}
+
+func (m *mockRefreshTokenRepo) Create(ctx context.Context, token entities.RefreshToken) (entities.RefreshToken, error) {
+	m.tokens[token.ID] = token
+	return token, nil
+}
+func (m *mockRefreshTokenRepo) GetByID(ctx context.Context, id string) (entities.RefreshToken, error) {
+	token, ok := m.tokens[id]
+	if !ok {
+		return entities.RefreshToken{}, domain.NewNotFoundError("refresh token", 0)
+	}
+	return token, nil
+}
+func (m *mockRefreshTokenRepo) Revoke(ctx context.Context, id string, replacedBy string) error {
+	token := m.tokens[id]
+	token.Revoked = true
+	token.ReplacedBy = replacedBy
+	m.tokens[id] = token
+	return nil
+}
+func (m *mockRefreshTokenRepo) Rotate(ctx context.Context, id string, successor entities.RefreshToken) error {
+	token := m.tokens[id]
+	if token.Revoked {
+		return domain.ErrTokenReused
+	}
+	token.Revoked = true
+	token.ReplacedBy = successor.ID
+	m.tokens[id] = token
+	m.tokens[successor.ID] = successor
+	return nil
+}
+func (m *mockRefreshTokenRepo) RevokeAllForUser(ctx context.Context, userID int64) error {
+	for id, token := range m.tokens {
+		if token.UserID == userID {
+			token.Revoked = true
+			m.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+var testJWTConfig = utils.JWTConfig{Secret: []byte("test-secret"), AccessTTL: time.Minute, RefreshTTL: time.Hour}
+
+func newAuthTestUserRepo() *mockUserRepo {
+	user, err := entities.NewUser("Jane", "jane", "jane@example.com", "s3cret")
+	if err != nil {
+		panic(err)
+	}
+	user.ID = 2
+	return &mockUserRepo{
+		GetByUsernameFn: func(username string) (entities.User, error) {
+			if username != user.Username {
+				return entities.User{}, domain.NewNotFoundError("user", 0)
+			}
+			return user, nil
+		},
+		GetByIDFn: func(id int64) (entities.User, error) { return user, nil },
+	}
+}
+
+func postJSON(handler gin.HandlerFunc, path string, body interface{}) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	serve(c, handler)
+	return w
+}
+
+func TestLogin_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewAuthHandler(newAuthTestUserRepo(), newMockRefreshTokenRepo(), testJWTConfig)
+
+	w := postJSON(handler.Login, "/auth/login", gin.H{"username": "jane", "password": "s3cret"})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	claims, err := utils.ParseToken(response["access_token"].(string), testJWTConfig.Secret)
+	if err != nil {
+		t.Fatalf("expected a valid access token, got %v", err)
+	}
+	if claims.Subject != "2" || claims.Type != utils.AccessTokenType {
+		t.Fatalf("unexpected access token claims %+v", claims)
+	}
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewAuthHandler(newAuthTestUserRepo(), newMockRefreshTokenRepo(), testJWTConfig)
+
+	w := postJSON(handler.Login, "/auth/login", gin.H{"username": "jane", "password": "wrong"})
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRefresh_RotatesAndDetectsReuse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := newMockRefreshTokenRepo()
+	handler := handlers.NewAuthHandler(newAuthTestUserRepo(), tokens, testJWTConfig)
+
+	var login map[string]interface{}
+	json.Unmarshal(postJSON(handler.Login, "/auth/login", gin.H{"username": "jane", "password": "s3cret"}).Body.Bytes(), &login)
+	firstRefreshToken := login["refresh_token"].(string)
+
+	w := postJSON(handler.Refresh, "/auth/refresh", gin.H{"refresh_token": firstRefreshToken})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var rotated map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &rotated)
+	secondRefreshToken := rotated["refresh_token"].(string)
+
+	// Replaying the rotated token is rejected and revokes the whole token family
+	w = postJSON(handler.Refresh, "/auth/refresh", gin.H{"refresh_token": firstRefreshToken})
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w = postJSON(handler.Refresh, "/auth/refresh", gin.H{"refresh_token": secondRefreshToken})
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the successor token to be revoked after reuse, got %d", w.Code)
+	}
+}
+
+// staleTokenRepo reads tokens as they were before any revocation, as a refresh racing another one does
+type staleTokenRepo struct {
+	*mockRefreshTokenRepo
+}
+
+func (m *staleTokenRepo) GetByID(ctx context.Context, id string) (entities.RefreshToken, error) {
+	token, err := m.mockRefreshTokenRepo.GetByID(ctx, id)
+	token.Revoked = false
+	return token, err
+}
+
+func TestRefresh_ConcurrentReuse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := &staleTokenRepo{newMockRefreshTokenRepo()}
+	handler := handlers.NewAuthHandler(newAuthTestUserRepo(), tokens, testJWTConfig)
+
+	var login map[string]interface{}
+	json.Unmarshal(postJSON(handler.Login, "/auth/login", gin.H{"username": "jane", "password": "s3cret"}).Body.Bytes(), &login)
+
+	w := postJSON(handler.Refresh, "/auth/refresh", gin.H{"refresh_token": login["refresh_token"]})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// The second refresh read the token before the first one revoked it, it loses the claim
+	w = postJSON(handler.Refresh, "/auth/refresh", gin.H{"refresh_token": login["refresh_token"]})
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d got %d", http.StatusUnauthorized, w.Code)
+	}
+	for id, token := range tokens.tokens {
+		if !token.Revoked {
+			t.Fatalf("expected every token of the user to be revoked after reuse, %s is not", id)
+		}
+	}
+}
+
+func TestLogout_RevokesRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewAuthHandler(newAuthTestUserRepo(), newMockRefreshTokenRepo(), testJWTConfig)
+
+	var login map[string]interface{}
+	json.Unmarshal(postJSON(handler.Login, "/auth/login", gin.H{"username": "jane", "password": "s3cret"}).Body.Bytes(), &login)
+
+	w := postJSON(handler.Logout, "/auth/logout", gin.H{"refresh_token": login["refresh_token"]})
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d got %d", http.StatusNoContent, w.Code)
+	}
+
+	w = postJSON(handler.Refresh, "/auth/refresh", gin.H{"refresh_token": login["refresh_token"]})
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestParseToken_RejectsTamperedSignature(t *testing.T) {
+	token, _ := utils.GenerateToken(utils.TokenClaims{Subject: "1", Type: utils.AccessTokenType, ExpiresAt: time.Now().Add(time.Minute).Unix()}, []byte("one"))
+
+	if _, err := utils.ParseToken(token, []byte("another")); !errors.Is(err, utils.ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package unittests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+func createTaskRequest(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	repo := &mockTaskRepo{}
+	repo.CreateFn = func(task entities.Task) (entities.Task, error) {
+		t.Fatalf("expected an invalid request not to be stored, got %+v", task)
+		return task, nil
+	}
+	handler := taskHandler(repo, transitionWorkflows())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/todo", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Set("user_id", int64(42))
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.CreateTask)
+	return w
+}
+
+func TestCreateTask_FieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	cases := []struct {
+		body    string
+		field   string
+		message string
+	}{
+		{`{"workflow_id": 1, "type_id": 1}`, "title", "title is required"},
+		{`{"title": " \t ", "workflow_id": 1, "type_id": 1}`, "title", "title must not be blank"},
+		{`{"title": "` + strings.Repeat("t", 256) + `", "workflow_id": 1, "type_id": 1}`, "title", "title must be at most 255 characters long"},
+		{`{"title": "t", "deadline": "` + past + `", "workflow_id": 1, "type_id": 1}`, "deadline", "deadline must be in the future"},
+		{`{"title": "t", "workflow_id": -1, "type_id": 1}`, "workflow_id", "workflow_id must be a positive ID"},
+		{`{"title": "t", "workflow_id": 1, "type_id": 1, "parent_id": -4}`, "parent_id", "parent_id must be a positive ID"},
+		{`{"title": "t", "workflow_id": "one", "type_id": 1}`, "workflow_id", "workflow_id must be an integer"},
+	}
+	for _, tc := range cases {
+		w := createTaskRequest(t, tc.body)
+
+		var resp struct {
+			Code   string           `json:"code"`
+			Fields []dto.FieldError `json:"fields"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response body: %v", err)
+		}
+		if w.Code != http.StatusBadRequest || resp.Code != "ERR-VL040" || len(resp.Fields) != 1 || resp.Fields[0].Field != tc.field || resp.Fields[0].Message != tc.message {
+			t.Fatalf("%s: expected 400 rejecting %s with %q, got %d %s", tc.body, tc.field, tc.message, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestCreateTask_ReportsEveryField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := createTaskRequest(t, `{"description": "d", "responsible_id": 0, "team_id": -1, "status_id": -2}`)
+
+	var resp struct {
+		Fields []dto.FieldError `json:"fields"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusBadRequest || len(resp.Fields) != 3 {
+		t.Fatalf("expected 400 listing title, status_id and team_id, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateTask_MalformedJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := createTaskRequest(t, `{"title": `)
+
+	if w.Code != http.StatusBadRequest || strings.Contains(w.Body.String(), `"fields"`) {
+		t.Fatalf("expected 400 without field errors, got %d %s", w.Code, w.Body.String())
+	}
+}
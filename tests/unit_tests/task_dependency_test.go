@@ -0,0 +1,221 @@
+package unittests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockDependencyRepo keeps dependencies in memory, the tasks they return are looked up in tasks
+type mockDependencyRepo struct {
+	dependencies []entities.TaskDependency
+	tasks        map[int64]entities.Task
+}
+
+func (m *mockDependencyRepo) Add(ctx context.Context, dependency entities.TaskDependency) (entities.TaskDependency, error) {
+	for _, existing := range m.dependencies {
+		if existing.BlockerID == dependency.BlockerID && existing.BlockedID == dependency.BlockedID {
+			return entities.TaskDependency{}, domain.ErrDependencyExists
+		}
+	}
+	if err := dependency.CheckCycle(m.dependencies); err != nil {
+		return entities.TaskDependency{}, err
+	}
+	m.dependencies = append(m.dependencies, dependency)
+	return dependency, nil
+}
+func (m *mockDependencyRepo) Remove(ctx context.Context, blockerID int64, blockedID int64) error {
+	m.dependencies = slices.DeleteFunc(m.dependencies, func(dependency entities.TaskDependency) bool {
+		return dependency.BlockerID == blockerID && dependency.BlockedID == blockedID
+	})
+	return nil
+}
+func (m *mockDependencyRepo) GetBlockers(ctx context.Context, taskID int64, teamIDs []int64) ([]entities.Task, error) {
+	var blockers []entities.Task
+	for _, dependency := range m.dependencies {
+		if dependency.BlockedID == taskID {
+			blockers = append(blockers, m.task(dependency.BlockerID))
+		}
+	}
+	return blockers, nil
+}
+func (m *mockDependencyRepo) GetBlockerStates(ctx context.Context, taskID int64) ([]entities.Task, error) {
+	var blockers []entities.Task
+	for _, dependency := range m.dependencies {
+		if dependency.BlockedID == taskID {
+			blocker := m.task(dependency.BlockerID)
+			blockers = append(blockers, entities.Task{ID: blocker.ID, Completed: blocker.Completed})
+		}
+	}
+	return blockers, nil
+}
+func (m *mockDependencyRepo) GetBlocked(ctx context.Context, taskID int64, teamIDs []int64) ([]entities.Task, error) {
+	var blocked []entities.Task
+	for _, dependency := range m.dependencies {
+		if dependency.BlockerID == taskID {
+			blocked = append(blocked, m.task(dependency.BlockedID))
+		}
+	}
+	return blocked, nil
+}
+func (m *mockDependencyRepo) GetAll(ctx context.Context, teamIDs []int64) ([]entities.TaskDependency, error) {
+	return m.dependencies, nil
+}
+func (m *mockDependencyRepo) task(id int64) entities.Task {
+	if task, ok := m.tasks[id]; ok {
+		return task
+	}
+	return entities.Task{ID: id}
+}
+
+func TestTaskDependency_CheckCycle(t *testing.T) {
+	existing := []entities.TaskDependency{
+		{BlockerID: 1, BlockedID: 2},
+		{BlockerID: 2, BlockedID: 3},
+		{BlockerID: 4, BlockedID: 3},
+	}
+
+	if err := (entities.TaskDependency{BlockerID: 1, BlockedID: 4}).CheckCycle(existing); err != nil {
+		t.Fatalf("expected 1 to be able to block 4, got %v", err)
+	}
+
+	var cycle *entities.DependencyCycleError
+	err := (entities.TaskDependency{BlockerID: 3, BlockedID: 1}).CheckCycle(existing)
+	if !errors.As(err, &cycle) || !slices.Equal(cycle.TaskIDs, []int64{1, 2, 3, 1}) {
+		t.Fatalf("expected the cycle 1 -> 2 -> 3 -> 1, got %v", err)
+	}
+
+	err = (entities.TaskDependency{BlockerID: 5, BlockedID: 5}).CheckCycle(existing)
+	if !errors.As(err, &cycle) {
+		t.Fatalf("expected a task blocking itself to be a cycle, got %v", err)
+	}
+}
+
+func TestNewTaskPlan_OrdersByStageThenDeadline(t *testing.T) {
+	soon := entities.NewDateTime(time.Now().Add(time.Hour))
+	later := entities.NewDateTime(time.Now().Add(48 * time.Hour))
+	tasks := []entities.Task{
+		{ID: 1, Deadline: later},
+		{ID: 2, Deadline: soon},
+		{ID: 3, Deadline: soon},
+		{ID: 4, Deadline: soon, Completed: true},
+		{ID: 5, Deadline: soon},
+	}
+	dependencies := []entities.TaskDependency{
+		{BlockerID: 1, BlockedID: 3},
+		{BlockerID: 2, BlockedID: 3},
+		{BlockerID: 3, BlockedID: 5},
+		{BlockerID: 4, BlockedID: 2},
+	}
+
+	plan := domain.NewTaskPlan(tasks, dependencies)
+
+	var order []int64
+	var stages []int
+	for _, planned := range plan {
+		order = append(order, planned.Task.ID)
+		stages = append(stages, planned.Stage)
+	}
+	if !slices.Equal(order, []int64{2, 1, 3, 5}) || !slices.Equal(stages, []int{0, 0, 1, 2}) {
+		t.Fatalf("expected tasks 2, 1, 3, 5 in stages 0, 0, 1, 2, got %v in %v", order, stages)
+	}
+	if !slices.Equal(plan[2].BlockedBy, []int64{1, 2}) || len(plan[0].BlockedBy) != 0 {
+		t.Fatalf("expected only open blockers to be listed, got %+v", plan)
+	}
+}
+
+func dependencyRequest(t *testing.T, handler gin.HandlerFunc, method string, body string, params gin.Params) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(method, "/todo/1/dependencies", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = params
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler)
+	return w
+}
+
+func TestAddDependency_RejectsCycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{ID: id}, nil }
+	dependencies := &mockDependencyRepo{dependencies: []entities.TaskDependency{{BlockerID: 1, BlockedID: 2}}}
+	handler := dependencyHandler(repo, nil, dependencies)
+
+	w := dependencyRequest(t, handler.AddDependency, http.MethodPost, `{"blocked_by": 2}`, gin.Params{{Key: "id", Value: "1"}})
+
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"cycle":[1,2,1]`) {
+		t.Fatalf("expected 422 with the cycle, got %d %s", w.Code, w.Body.String())
+	}
+	if len(dependencies.dependencies) != 1 {
+		t.Fatalf("expected the cycle not to be stored")
+	}
+
+	w = dependencyRequest(t, handler.AddDependency, http.MethodPost, `{"blocked_by": 1}`, gin.Params{{Key: "id", Value: "2"}})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an existing dependency, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = dependencyRequest(t, handler.AddDependency, http.MethodPost, `{"blocked_by": 3}`, gin.Params{{Key: "id", Value: "2"}})
+	if w.Code != http.StatusCreated || len(dependencies.dependencies) != 2 {
+		t.Fatalf("expected 201, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRemoveDependency_UnknownDependency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dependencies := &mockDependencyRepo{dependencies: []entities.TaskDependency{{BlockerID: 1, BlockedID: 2}}}
+	handler := dependencyHandler(&mockTaskRepo{}, nil, dependencies)
+
+	w := dependencyRequest(t, handler.RemoveDependency, http.MethodDelete, "", gin.Params{{Key: "id", Value: "2"}, {Key: "blockerID", Value: "3"}})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = dependencyRequest(t, handler.RemoveDependency, http.MethodDelete, "", gin.Params{{Key: "id", Value: "2"}, {Key: "blockerID", Value: "1"}})
+	if w.Code != http.StatusNoContent || len(dependencies.dependencies) != 0 {
+		t.Fatalf("expected 204 and the dependency removed, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTransitionTask_BlockedByOpenTask(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, Status: entities.TaskStatus{ID: 2}, Workflow: entities.Workflow{ID: 4}}, nil
+	}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) { return task, nil }
+	dependencies := &mockDependencyRepo{
+		dependencies: []entities.TaskDependency{{BlockerID: 6, BlockedID: 5}, {BlockerID: 7, BlockedID: 5}},
+		tasks:        map[int64]entities.Task{6: {ID: 6, Completed: true}, 7: {ID: 7}},
+	}
+	handler := dependencyHandler(repo, nil, dependencies)
+
+	w := transitionRequest(t, handler, `{"status_id": 3}`)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"open_blocker_ids":[7]`) {
+		t.Fatalf("expected 422 naming the open blocker, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = transitionRequest(t, handler, `{"status_id": 1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a blocked task to still move to other statuses, got %d %s", w.Code, w.Body.String())
+	}
+
+	dependencies.tasks[7] = entities.Task{ID: 7, Completed: true}
+	w = transitionRequest(t, handler, `{"status_id": 3}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the task to complete once its blockers are done, got %d %s", w.Code, w.Body.String())
+	}
+}
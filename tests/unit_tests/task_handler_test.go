@@ -2,15 +2,19 @@ package unittests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"todo-api/internal/domain"
 	"todo-api/internal/domain/entities"
+	dto "todo-api/internal/infrastructure/api/dto/v1"
 	"todo-api/internal/infrastructure/api/handlers"
+	"todo-api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,37 +22,126 @@ import (
 type mockTaskRepo struct {
 	CreateFn              func(task entities.Task) (entities.Task, error)
 	GetByIDFn             func(id int64) (entities.Task, error)
+	GetExpandedFn         func(id int64, expansion domain.TaskExpansion) (entities.Task, error)
 	UpdateFn              func(task entities.Task) (entities.Task, error)
+	GetHistoryFn          func(id int64) ([]entities.TaskEvent, error)
 	GetAllFn              func() ([]entities.Task, error)
+	FindFn                func(query domain.TaskQuery) (domain.TaskPage, error)
+	SearchFn              func(query string, limit int) ([]domain.TaskSearchResult, error)
 	GetAllByResponsibleFn func(userID int64) ([]entities.Task, error)
-	RemoveFn              func(id int64) error
+	GetAllByStatusFn      func(statusID int64) ([]entities.Task, error)
+	GetAllByWorkflowFn    func(workflowID int64) ([]entities.Task, error)
+	GetAllByParentFn      func(parentID int64) ([]entities.Task, error)
+	GetDescendantsFn      func(id int64) ([]entities.Task, error)
+	GetAllOverdueFn       func() ([]entities.Task, error)
+	RemoveFn              func(id int64, policy domain.SubtaskPolicy) error
 }
 
-func (m *mockTaskRepo) Create(task entities.Task) (entities.Task, error) { return m.CreateFn(task) }
-func (m *mockTaskRepo) GetByID(id int64) (entities.Task, error)          { return m.GetByIDFn(id) }
-func (m *mockTaskRepo) Update(task entities.Task) (entities.Task, error) { return m.UpdateFn(task) }
-func (m *mockTaskRepo) GetAll() ([]entities.Task, error)                 { return m.GetAllFn() }
-func (m *mockTaskRepo) GetAllByResponsible(userID int64) ([]entities.Task, error) {
+func (m *mockTaskRepo) Create(ctx context.Context, task entities.Task) (entities.Task, error) {
+	return m.CreateFn(task)
+}
+func (m *mockTaskRepo) GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.Task, error) {
+	return m.GetByIDFn(id)
+}
+func (m *mockTaskRepo) GetExpanded(ctx context.Context, id int64, expansion domain.TaskExpansion, teamIDs []int64) (entities.Task, error) {
+	if m.GetExpandedFn != nil {
+		return m.GetExpandedFn(id, expansion)
+	}
+	return m.GetByIDFn(id)
+}
+func (m *mockTaskRepo) Update(ctx context.Context, task entities.Task, actorID int64, teamIDs []int64) (entities.Task, error) {
+	return m.UpdateFn(task)
+}
+func (m *mockTaskRepo) GetHistory(ctx context.Context, id int64, teamIDs []int64) ([]entities.TaskEvent, error) {
+	return m.GetHistoryFn(id)
+}
+func (m *mockTaskRepo) GetAll(ctx context.Context, teamIDs []int64) ([]entities.Task, error) {
+	return m.GetAllFn()
+}
+func (m *mockTaskRepo) Find(ctx context.Context, query domain.TaskQuery, teamIDs []int64) (domain.TaskPage, error) {
+	return m.FindFn(query)
+}
+func (m *mockTaskRepo) Search(ctx context.Context, query string, limit int, teamIDs []int64) ([]domain.TaskSearchResult, error) {
+	return m.SearchFn(query, limit)
+}
+func (m *mockTaskRepo) GetAllByResponsible(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error) {
 	return m.GetAllByResponsibleFn(userID)
 }
-func (m *mockTaskRepo) GetAllByAuthor(userID int64) ([]entities.Task, error) { return nil, nil }
-func (m *mockTaskRepo) GetAllByStatus(status entities.TaskStatus) ([]entities.Task, error) {
+func (m *mockTaskRepo) GetAllByAuthor(ctx context.Context, userID int64, teamIDs []int64) ([]entities.Task, error) {
+	return nil, nil
+}
+func (m *mockTaskRepo) GetAllByStatus(ctx context.Context, status entities.TaskStatus, teamIDs []int64) ([]entities.Task, error) {
+	return m.GetAllByStatusFn(status.ID)
+}
+func (m *mockTaskRepo) GetAllByWorkflow(ctx context.Context, workflowID int64, teamIDs []int64) ([]entities.Task, error) {
+	return m.GetAllByWorkflowFn(workflowID)
+}
+func (m *mockTaskRepo) GetAllByParent(ctx context.Context, parentID int64, teamIDs []int64) ([]entities.Task, error) {
+	if m.GetAllByParentFn != nil {
+		return m.GetAllByParentFn(parentID)
+	}
+	return nil, nil
+}
+func (m *mockTaskRepo) GetDescendants(ctx context.Context, id int64, teamIDs []int64) ([]entities.Task, error) {
+	if m.GetDescendantsFn != nil {
+		return m.GetDescendantsFn(id)
+	}
+	return nil, nil
+}
+func (m *mockTaskRepo) GetAllOverdue(ctx context.Context, teamIDs []int64) ([]entities.Task, error) {
+	if m.GetAllOverdueFn != nil {
+		return m.GetAllOverdueFn()
+	}
 	return nil, nil
 }
-func (m *mockTaskRepo) GetAllOverdue() ([]entities.Task, error) { return nil, nil }
-func (m *mockTaskRepo) Remove(id int64) error                   { return m.RemoveFn(id) }
+func (m *mockTaskRepo) Remove(ctx context.Context, id int64, version int64, policy domain.SubtaskPolicy, actorID int64, teamIDs []int64) error {
+	return m.RemoveFn(id, policy)
+}
+
+// serve runs a handler the way the router does, errors it aborts with answered by the error handler middleware
+func serve(c *gin.Context, handler gin.HandlerFunc) {
+	handler(c)
+	middleware.ErrorHandler()(c)
+}
+
+// taskHandler builds a handler whose service finds every user, status and type, and no dependency.
+// Workflows default to those of transitionWorkflows.
+func taskHandler(repo *mockTaskRepo, workflows *mockWorkflowRepo) *handlers.TaskHandler {
+	return dependencyHandler(repo, workflows, &mockDependencyRepo{})
+}
+
+// dependencyHandler builds a handler like taskHandler, with the dependencies of dependencies
+func dependencyHandler(repo *mockTaskRepo, workflows *mockWorkflowRepo, dependencies *mockDependencyRepo) *handlers.TaskHandler {
+	if workflows == nil {
+		workflows = transitionWorkflows()
+	}
+	users := &mockUserRepo{}
+	users.GetByIDFn = func(id int64) (entities.User, error) { return entities.User{ID: id}, nil }
+	return handlers.NewTaskHandler(domain.NewTaskService(repo, workflows, &mockStatusRepo{}, &mockTypeRepo{}, users,
+		dependencies, &mockActionFailureRepo{}, &mockNotifier{}))
+}
 
 func TestCreateTask_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	repo := &mockTaskRepo{}
 	repo.CreateFn = func(task entities.Task) (entities.Task, error) {
+		if task.AuthorID != 42 {
+			t.Fatalf("expected author to be the authenticated user, got %d", task.AuthorID)
+		}
+		if task.CreatedAt.IsZero() || task.UpdatedAt.IsZero() {
+			t.Fatalf("expected timestamps to be set by the server")
+		}
+		if task.TeamID != 7 {
+			t.Fatalf("expected task to default to the caller's only team, got %d", task.TeamID)
+		}
 		task.ID = 123
 		return task, nil
 	}
 
-	handler := handlers.NewTaskHandler(repo)
+	handler := taskHandler(repo, transitionWorkflows())
 
-	body := entities.Task{Title: "T1", Description: "D1", AuthorID: 1, Deadline: entities.NewDateTime(time.Now())}
+	body := gin.H{"title": "T1", "description": "D1", "deadline": time.Now().Add(time.Hour).Format(time.RFC3339),
+		"workflow_id": 1, "type_id": 1}
 	b, _ := json.Marshal(body)
 
 	w := httptest.NewRecorder()
@@ -56,20 +149,64 @@ func TestCreateTask_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/todo", bytes.NewReader(b))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
+	c.Set("user_id", int64(42))
+	c.Set("team_ids", []int64{7})
 
-	handler.CreateTask(c)
+	serve(c, handler.CreateTask)
 
 	if w.Code != http.StatusCreated {
 		t.Fatalf("expected status %d got %d, body: %s", http.StatusCreated, w.Code, w.Body.String())
 	}
 }
 
+func TestCreateTask_ForeignTeam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := taskHandler(&mockTaskRepo{}, nil)
+
+	body := gin.H{"title": "T1", "team_id": 9}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/todo", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Set("user_id", int64(42))
+	c.Set("team_ids", []int64{7, 8})
+
+	serve(c, handler.CreateTask)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestCreateTask_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := taskHandler(&mockTaskRepo{}, nil)
+
+	body := gin.H{"title": "T1"}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/todo", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	serve(c, handler.CreateTask)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
 func TestGetTask_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	repo := &mockTaskRepo{}
-	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{}, errors.New("not found") }
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{}, domain.NewNotFoundError("task", id) }
 
-	handler := handlers.NewTaskHandler(repo)
+	handler := taskHandler(repo, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -77,78 +214,502 @@ func TestGetTask_NotFound(t *testing.T) {
 	c.Request = req
 	c.Params = gin.Params{{Key: "id", Value: "999"}}
 
-	handler.GetTask(c)
+	serve(c, handler.GetTask)
 
 	if w.Code != http.StatusNotFound {
 		t.Fatalf("expected status %d got %d", http.StatusNotFound, w.Code)
 	}
 }
 
+func TestGetTask_Expand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetExpandedFn = func(id int64, expansion domain.TaskExpansion) (entities.Task, error) {
+		expected := domain.TaskExpansion{Status: true, Author: true}
+		if expansion != expected {
+			t.Fatalf("expected status and author to be expanded, got %+v", expansion)
+		}
+		return entities.Task{
+			ID:       id,
+			Status:   entities.TaskStatus{ID: 2, Label: "In Progress"},
+			AuthorID: 3,
+			Author:   &entities.User{ID: 3, Username: "jdoe"},
+		}, nil
+	}
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/5?expand=status,author", nil)
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+
+	serve(c, handler.GetTask)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var task dto.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &task); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+	if task.Status.Label != "In Progress" || task.Author == nil || task.Author.Username != "jdoe" {
+		t.Fatalf("expected expanded status and author, got %+v", task)
+	}
+}
+
+func TestGetTask_InvalidExpand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := taskHandler(&mockTaskRepo{}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/5?expand=owner", nil)
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+
+	serve(c, handler.GetTask)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
 func TestGetAllTasks_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	repo := &mockTaskRepo{}
-	repo.GetAllFn = func() ([]entities.Task, error) {
-		return []entities.Task{{ID: 1, Title: "A"}}, nil
+	repo.FindFn = func(query domain.TaskQuery) (domain.TaskPage, error) {
+		if query.Limit != domain.DefaultTaskPageSize || query.Offset != 0 {
+			t.Fatalf("expected the default page, got limit %d offset %d", query.Limit, query.Offset)
+		}
+		return domain.TaskPage{Tasks: []entities.Task{{ID: 1, Title: "A"}}, Total: 1}, nil
 	}
 
-	handler := handlers.NewTaskHandler(repo)
+	handler := taskHandler(repo, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	req := httptest.NewRequest(http.MethodGet, "/todo", nil)
 	c.Request = req
 
-	handler.GetAllTasks(c)
+	serve(c, handler.GetAllTasks)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestGetAllTasks_DeadlineToDateIncludesTheDay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := map[string]string{
+		"2026-02-01":           "2026-02-01T23:59:59Z",
+		"2026-02-01T12:00:00Z": "2026-02-01T12:00:00Z",
+	}
+	for value, expected := range cases {
+		repo := &mockTaskRepo{}
+		repo.FindFn = func(query domain.TaskQuery) (domain.TaskPage, error) {
+			if query.DeadlineTo == nil || query.DeadlineTo.Format(time.RFC3339) != expected {
+				t.Fatalf("deadline_to=%s: expected the range to end at %s, got %v", value, expected, query.DeadlineTo)
+			}
+			return domain.TaskPage{}, nil
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/todo?deadline_to="+value, nil)
+
+		serve(c, taskHandler(repo, nil).GetAllTasks)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("deadline_to=%s: expected status %d got %d", value, http.StatusOK, w.Code)
+		}
+	}
+}
+
+func TestGetAllTasks_PaginationAndFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.FindFn = func(query domain.TaskQuery) (domain.TaskPage, error) {
+		if query.Sort != "deadline" || !query.Descending {
+			t.Fatalf("expected descending deadline sort, got %q %v", query.Sort, query.Descending)
+		}
+		if query.StatusID != 3 || query.Completed == nil || *query.Completed {
+			t.Fatalf("expected status and completed filters, got %+v", query)
+		}
+		if query.DeadlineFrom == nil || query.DeadlineFrom.Format("2006-01-02") != "2026-02-01" {
+			t.Fatalf("expected deadline_from to be parsed, got %v", query.DeadlineFrom)
+		}
+		return domain.TaskPage{Tasks: []entities.Task{{ID: 3}, {ID: 4}}, Total: 5}, nil
+	}
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo?limit=2&offset=2&sort=-deadline&status_id=3&completed=false&deadline_from=2026-02-01", nil)
+
+	serve(c, handler.GetAllTasks)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var body struct {
+		Total int64
+		Links map[string]string
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Total != 5 {
+		t.Fatalf("expected total 5, got %d", body.Total)
+	}
+	if !strings.Contains(body.Links["next"], "offset=4") || !strings.Contains(body.Links["next"], "status_id=3") {
+		t.Fatalf("expected next link to keep the filters and move the offset, got %q", body.Links["next"])
+	}
+	if !strings.Contains(body.Links["prev"], "offset=0") {
+		t.Fatalf("expected prev link to the first page, got %q", body.Links["prev"])
+	}
+}
+
+func TestGetAllTasks_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := taskHandler(&mockTaskRepo{}, nil)
+
+	for _, query := range []string{"limit=0", "limit=1000", "offset=-1", "sort=password", "author_id=abc", "completed=maybe", "deadline_to=soon"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/todo?"+query, nil)
+
+		serve(c, handler.GetAllTasks)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d for %q got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+func TestSearchTasks_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.SearchFn = func(query string, limit int) ([]domain.TaskSearchResult, error) {
+		if query != "login bug" || limit != 5 {
+			t.Fatalf("unexpected search %q limit %d", query, limit)
+		}
+		return []domain.TaskSearchResult{{Task: entities.Task{ID: 2}, Score: 1.5, TitleSnippet: "Fix <mark>Login</mark> <mark>Bug</mark>"}}, nil
+	}
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/search?q=login+bug&limit=5", nil)
+
+	serve(c, handler.SearchTasks)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
 	}
+	if !strings.Contains(w.Body.String(), `"title":"Fix \u003cmark\u003eLogin`) {
+		t.Fatalf("expected highlighted title in body, got %s", w.Body.String())
+	}
+}
+
+func TestSearchTasks_RequiresQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := taskHandler(&mockTaskRepo{}, nil)
+
+	for _, target := range []string{"/todo/search", "/todo/search?q=%22*%28", "/todo/search?q=bug&limit=0"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+		serve(c, handler.SearchTasks)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d for %s got %d", http.StatusBadRequest, target, w.Code)
+		}
+	}
 }
 
 func TestUpdateTask_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	repo := &mockTaskRepo{}
-	repo.UpdateFn = func(task entities.Task) (entities.Task, error) { return task, nil }
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, AuthorID: 3, Status: entities.TaskStatus{ID: 2}}, nil
+	}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		if task.AuthorID != 3 {
+			t.Fatalf("expected the stored author to be kept, got %d", task.AuthorID)
+		}
+		return task, nil
+	}
 
-	handler := handlers.NewTaskHandler(repo)
+	handler := taskHandler(repo, nil)
 
-	body := entities.Task{Title: "Updated"}
+	body := gin.H{"title": "Updated", "workflow_id": 1, "type_id": 1}
 	b, _ := json.Marshal(body)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	req := httptest.NewRequest(http.MethodPut, "/todo/1", bytes.NewReader(b))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-	handler.UpdateTask(c)
+	serve(c, handler.UpdateTask)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
 	}
 }
 
+func TestCreateTask_DanglingReference(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.CreateFn = func(task entities.Task) (entities.Task, error) {
+		t.Fatalf("expected a task with a dangling reference not to be stored")
+		return task, nil
+	}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{}, domain.NewNotFoundError("task", id) }
+
+	handler := taskHandler(repo, transitionWorkflows())
+
+	cases := []struct {
+		body  string
+		field string
+	}{
+		{`{"title": "t", "type_id": 1}`, "workflow"},
+		{`{"title": "t", "workflow_id": 1, "status_id": 9999, "type_id": 1}`, "status"},
+		{`{"title": "t", "workflow_id": 1, "status_id": 2}`, "type"},
+		{`{"title": "t", "workflow_id": 1, "type_id": 1, "parent_id": 404}`, "parent"},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req := httptest.NewRequest(http.MethodPost, "/todo", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+		c.Set("user_id", int64(42))
+		c.Set("team_ids", []int64{1})
+
+		serve(c, handler.CreateTask)
+
+		var resp struct {
+			Code  string `json:"code"`
+			Field string `json:"field"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusUnprocessableEntity || resp.Code != "ERR-RF422" || resp.Field != tc.field {
+			t.Fatalf("%s: expected 422 naming %q, got %d %s", tc.body, tc.field, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestCreateTask_ForeignKeyViolation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.CreateFn = func(task entities.Task) (entities.Task, error) {
+		return entities.Task{}, domain.NewMissingReferenceError("responsible", 0)
+	}
+
+	handler := taskHandler(repo, transitionWorkflows())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/todo", strings.NewReader(`{"title": "t", "workflow_id": 1, "type_id": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Set("user_id", int64(42))
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.CreateTask)
+
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"field":"responsible"`) {
+		t.Fatalf("expected the violation to be reported as 422, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateTask_RejectsAuthorChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{ID: id, AuthorID: 3}, nil }
+
+	handler := taskHandler(repo, nil)
+
+	body := gin.H{"title": "Updated", "author_id": 4}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPut, "/todo/1", bytes.NewReader(b))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	serve(c, handler.UpdateTask)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestUpdateTask_RejectsBlankTitle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{ID: id, AuthorID: 3}, nil }
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		t.Fatalf("expected a blank title not to be stored")
+		return task, nil
+	}
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPut, "/todo/1", strings.NewReader(`{"title": "  ", "workflow_id": 1, "type_id": 1}`))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	serve(c, handler.UpdateTask)
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "title must not be blank") {
+		t.Fatalf("expected 400 rejecting the title, got %d %s", w.Code, w.Body.String())
+	}
+}
+
 func TestDeleteTask_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	repo := &mockTaskRepo{}
-	repo.RemoveFn = func(id int64) error { return nil }
+	repo.RemoveFn = func(id int64, policy domain.SubtaskPolicy) error {
+		if policy != domain.SubtasksCascade {
+			t.Fatalf("expected subtasks to be deleted by default, got %q", policy)
+		}
+		return nil
+	}
 
-	handler := handlers.NewTaskHandler(repo)
+	handler := taskHandler(repo, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	req := httptest.NewRequest(http.MethodDelete, "/todo/1", nil)
+	req.Header.Set("If-Match", `"1"`)
 	c.Request = req
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-	handler.DeleteTask(c)
+	serve(c, handler.DeleteTask)
 
 	if w.Code != http.StatusNoContent {
 		t.Fatalf("expected status %d got %d", http.StatusNoContent, w.Code)
 	}
 }
 
+func TestDeleteTask_SubtaskPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.RemoveFn = func(id int64, policy domain.SubtaskPolicy) error {
+		if policy != domain.SubtasksReject {
+			t.Fatalf("expected the reject policy, got %q", policy)
+		}
+		return domain.ErrHasSubtasks
+	}
+	handler := taskHandler(repo, nil)
+
+	for query, expected := range map[string]int{"reject": http.StatusConflict, "orphan": http.StatusBadRequest} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/todo/1?subtasks="+query, nil)
+		c.Request.Header.Set("If-Match", "*")
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		serve(c, handler.DeleteTask)
+
+		if w.Code != expected {
+			t.Fatalf("subtasks=%s: expected %d got %d", query, expected, w.Code)
+		}
+	}
+}
+
+func subtaskRepo() *mockTaskRepo {
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, AuthorID: 1, Status: entities.TaskStatus{ID: 1}, Workflow: entities.Workflow{ID: 1}, Type: entities.TaskType{ID: 1}}, nil
+	}
+	// 1 has the subtasks 2 and 3, 3 has the subtask 4
+	repo.GetDescendantsFn = func(id int64) ([]entities.Task, error) {
+		return []entities.Task{
+			{ID: 2, Parent: &entities.Task{ID: 1}, Completed: true},
+			{ID: 3, Parent: &entities.Task{ID: 1}},
+			{ID: 4, Parent: &entities.Task{ID: 3}, Completed: true},
+		}, nil
+	}
+	return repo
+}
+
+func TestGetSubtasks_Recursive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := taskHandler(subtaskRepo(), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/1/subtasks?recursive=true", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	serve(c, handler.GetSubtasks)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Progress struct{ Completed, Total, Percent int }
+		Subtasks []struct {
+			Task     entities.Task
+			Progress struct{ Completed, Total, Percent int }
+			Subtasks []json.RawMessage
+		}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if resp.Progress.Completed != 2 || resp.Progress.Total != 3 || resp.Progress.Percent != 66 {
+		t.Fatalf("unexpected progress %+v", resp.Progress)
+	}
+	if len(resp.Subtasks) != 2 || resp.Subtasks[1].Task.ID != 3 || len(resp.Subtasks[1].Subtasks) != 1 || resp.Subtasks[1].Progress.Percent != 100 {
+		t.Fatalf("unexpected tree %s", w.Body.String())
+	}
+}
+
+func TestReparentTask_RejectsCycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := subtaskRepo()
+	// The repository rejects the loop in the transaction of the update
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		if task.Parent == nil || task.Parent.ID != 4 {
+			t.Fatalf("expected task 4 to be stored as the parent, got %+v", task.Parent)
+		}
+		return entities.Task{}, &domain.InvalidReferenceError{Field: "parent", ID: 4, Message: "task 4 is a subtask of task 1, it cannot become its parent"}
+	}
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPut, "/todo/1/parent", strings.NewReader(`{"parent_id": 4}`))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.ReparentTask)
+
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"field":"parent"`) {
+		t.Fatalf("expected 422 naming the parent, got %d %s", w.Code, w.Body.String())
+	}
+}
+
 func TestGetTasksByResponsible_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	repo := &mockTaskRepo{}
@@ -156,7 +717,7 @@ func TestGetTasksByResponsible_Success(t *testing.T) {
 		return []entities.Task{{ID: 5, Title: "R"}}, nil
 	}
 
-	handler := handlers.NewTaskHandler(repo)
+	handler := taskHandler(repo, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -164,9 +725,403 @@ func TestGetTasksByResponsible_Success(t *testing.T) {
 	c.Request = req
 	c.Params = gin.Params{{Key: "userID", Value: "2"}}
 
-	handler.GetTasksByResponsible(c)
+	serve(c, handler.GetTasksByResponsible)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
 	}
 }
+
+func TestGetTasksByStatus_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetAllByStatusFn = func(statusID int64) ([]entities.Task, error) {
+		if statusID != 3 {
+			t.Fatalf("expected status 3, got %d", statusID)
+		}
+		return []entities.Task{{ID: 1, Status: entities.TaskStatus{ID: 3}}}, nil
+	}
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/status/3", nil)
+	c.Params = gin.Params{{Key: "statusID", Value: "3"}}
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.GetTasksByStatus)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var tasks []dto.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != 1 {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+}
+
+func TestGetTasksByStatus_InvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := taskHandler(&mockTaskRepo{}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/status/abc", nil)
+	c.Params = gin.Params{{Key: "statusID", Value: "abc"}}
+
+	serve(c, handler.GetTasksByStatus)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestGetBoard_GroupsTasksByWorkflowOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	workflows := &mockWorkflowRepo{}
+	workflows.GetByIDFn = func(id int64) (entities.Workflow, error) {
+		return entities.Workflow{
+			ID:   id,
+			Name: "Default",
+			Statuses: map[uint8]entities.TaskStatus{
+				2: {ID: 20, Label: "In Progress"},
+				1: {ID: 10, Label: "To Do"},
+				3: {ID: 30, Label: "Done"},
+			},
+		}, nil
+	}
+	repo := &mockTaskRepo{}
+	repo.GetAllByWorkflowFn = func(workflowID int64) ([]entities.Task, error) {
+		return []entities.Task{
+			{ID: 1, Status: entities.TaskStatus{ID: 10}},
+			{ID: 2, Status: entities.TaskStatus{ID: 30}},
+			{ID: 3, Status: entities.TaskStatus{ID: 10}},
+			{ID: 4, Status: entities.TaskStatus{ID: 99}},
+		}, nil
+	}
+
+	handler := taskHandler(repo, workflows)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/board/1", nil)
+	c.Params = gin.Params{{Key: "workflowID", Value: "1"}}
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.GetBoard)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var body struct {
+		Columns []struct {
+			Position uint8
+			Status   entities.TaskStatus
+			Count    int
+			Tasks    []entities.Task
+		}
+		Unmapped struct {
+			Count int
+		}
+		Total int
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+
+	expected := []struct {
+		label string
+		count int
+	}{{"To Do", 2}, {"In Progress", 0}, {"Done", 1}}
+	if len(body.Columns) != len(expected) {
+		t.Fatalf("expected %d columns, got %d", len(expected), len(body.Columns))
+	}
+	for i, column := range body.Columns {
+		if column.Status.Label != expected[i].label || column.Count != expected[i].count || len(column.Tasks) != column.Count {
+			t.Fatalf("unexpected column %d: %+v", i, column)
+		}
+	}
+	if body.Unmapped.Count != 1 || body.Total != 4 {
+		t.Fatalf("expected 1 unmapped task out of 4, got %d of %d", body.Unmapped.Count, body.Total)
+	}
+}
+
+func TestGetBoard_WorkflowNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	workflows := &mockWorkflowRepo{}
+	workflows.GetByIDFn = func(id int64) (entities.Workflow, error) {
+		return entities.Workflow{}, domain.NewNotFoundError("workflow", id)
+	}
+
+	handler := taskHandler(&mockTaskRepo{}, workflows)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/board/9", nil)
+	c.Params = gin.Params{{Key: "workflowID", Value: "9"}}
+
+	serve(c, handler.GetBoard)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestGetTaskHistory_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{ID: id}, nil }
+	repo.GetHistoryFn = func(id int64) ([]entities.TaskEvent, error) {
+		done := "3"
+		return []entities.TaskEvent{{ID: 1, TaskID: id, ActorID: 2, Field: "status", NewValue: &done}}, nil
+	}
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/5/history", nil)
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.GetTaskHistory)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var events []dto.TaskEventResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+	if len(events) != 1 || events[0].Field != "status" || *events[0].NewValue != "3" || events[0].OldValue != nil {
+		t.Fatalf("unexpected history %s", w.Body.String())
+	}
+}
+
+func TestGetTaskHistory_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{}, domain.NewNotFoundError("task", id) }
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/todo/5/history", nil)
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+
+	serve(c, handler.GetTaskHistory)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func transitionRequest(t *testing.T, handler *handlers.TaskHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/todo/5/transition", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.TransitionTask)
+	return w
+}
+
+func transitionWorkflows() *mockWorkflowRepo {
+	workflows := &mockWorkflowRepo{}
+	workflows.GetByIDFn = func(id int64) (entities.Workflow, error) {
+		return entities.Workflow{
+			ID: id,
+			Statuses: map[uint8]entities.TaskStatus{
+				0: {ID: 1, Label: "Todo", Active: true},
+				1: {ID: 2, Label: "Doing", Active: true},
+				2: {ID: 3, Label: "Done", Active: true},
+			},
+		}, nil
+	}
+	return workflows
+}
+
+func TestTransitionTask_CompletesOnLastStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, Status: entities.TaskStatus{ID: 2}, Workflow: entities.Workflow{ID: 4}}, nil
+	}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		if task.Status.ID != 3 || !task.Completed {
+			t.Fatalf("expected the task to be stored done and completed, got %+v", task)
+		}
+		return task, nil
+	}
+
+	w := transitionRequest(t, taskHandler(repo, transitionWorkflows()), `{"status_id": 3}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Task           dto.TaskResponse            `json:"task"`
+		ActionFailures []dto.ActionFailureResponse `json:"action_failures"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+	if !body.Task.Completed || body.ActionFailures == nil || len(body.ActionFailures) != 0 {
+		t.Fatalf("expected the completed task and an empty list of action failures, got %s", w.Body.String())
+	}
+}
+
+func TestTransitionTask_StatusOutsideWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, Status: entities.TaskStatus{ID: 1}, Workflow: entities.Workflow{ID: 4}}, nil
+	}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		t.Fatalf("a rejected transition must not be stored")
+		return task, nil
+	}
+
+	w := transitionRequest(t, taskHandler(repo, transitionWorkflows()), `{"status_id": 9}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", w.Code)
+	}
+}
+
+func TestTransitionTask_RequiresStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := transitionRequest(t, taskHandler(&mockTaskRepo{}, transitionWorkflows()), `{}`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestUpdateTask_RejectsStatusChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, AuthorID: 3, Status: entities.TaskStatus{ID: 1}, TeamID: 1}, nil
+	}
+
+	handler := taskHandler(repo, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPut, "/todo/5", strings.NewReader(`{"title": "t", "status_id": 3}`))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.UpdateTask)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestUpdateTask_IfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, Title: "Stored", Status: entities.TaskStatus{ID: 1}, TeamID: 1, Version: 3}, nil
+	}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		if task.Version != 3 {
+			return entities.Task{}, domain.ErrVersionConflict
+		}
+		task.Version++
+		return task, nil
+	}
+	handler := taskHandler(repo, nil)
+
+	cases := []struct {
+		ifMatch string
+		status  int
+		etag    string
+	}{
+		{"", http.StatusPreconditionRequired, ""},
+		{"W/3", http.StatusBadRequest, ""},
+		{`"2"`, http.StatusPreconditionFailed, `"3"`},
+		{`"3"`, http.StatusOK, `"4"`},
+		{"*", http.StatusOK, `"4"`},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req := httptest.NewRequest(http.MethodPut, "/todo/5", strings.NewReader(`{"title": "t", "workflow_id": 1, "type_id": 1}`))
+		if tc.ifMatch != "" {
+			req.Header.Set("If-Match", tc.ifMatch)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+		c.Params = gin.Params{{Key: "id", Value: "5"}}
+		c.Set("team_ids", []int64{1})
+
+		serve(c, handler.UpdateTask)
+
+		if w.Code != tc.status || w.Header().Get("ETag") != tc.etag {
+			t.Fatalf("If-Match %q: expected %d with ETag %q, got %d with %q: %s", tc.ifMatch, tc.status, tc.etag, w.Code, w.Header().Get("ETag"), w.Body.String())
+		}
+		if tc.status == http.StatusPreconditionFailed && !strings.Contains(w.Body.String(), `"title":"Stored"`) {
+			t.Fatalf("expected the stored task in the 412 body, got %s", w.Body.String())
+		}
+	}
+}
+
+func TestTransitionTask_ReportsFailedGuards(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	workflows := transitionWorkflows()
+	load := workflows.GetByIDFn
+	workflows.GetByIDFn = func(id int64) (entities.Workflow, error) {
+		workflow, err := load(id)
+		workflow.Guards = []entities.TransitionGuard{
+			{ToStatusID: 3, Kind: entities.GuardRequiredField, Field: "responsible"},
+			{ToStatusID: 3, Kind: entities.GuardSubtasksCompleted},
+		}
+		return workflow, err
+	}
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, Status: entities.TaskStatus{ID: 2}, Workflow: entities.Workflow{ID: 4}}, nil
+	}
+	repo.GetAllByParentFn = func(parentID int64) ([]entities.Task, error) {
+		return []entities.Task{{ID: 8, Completed: true}, {ID: 9}}, nil
+	}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		t.Fatalf("a task failing its guards must not be stored")
+		return task, nil
+	}
+
+	w := transitionRequest(t, taskHandler(repo, workflows), `{"status_id": 3}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", w.Code)
+	}
+	var body struct {
+		FailedGuards []struct {
+			Kind    string
+			Field   string
+			Message string
+		} `json:"failed_guards"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+	if len(body.FailedGuards) != 2 || body.FailedGuards[0].Field != "responsible" || body.FailedGuards[1].Kind != "subtasks_completed" {
+		t.Fatalf("expected both guards to be reported, got %+v", body.FailedGuards)
+	}
+}
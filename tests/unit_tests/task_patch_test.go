@@ -0,0 +1,133 @@
+package unittests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// patchRepo stores task 5, whose responsible, parent and description a full PUT body would have to repeat
+func patchRepo(updated *entities.Task) *mockTaskRepo {
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{
+			ID:            id,
+			Title:         "Stored",
+			Description:   "Stored description",
+			Parent:        &entities.Task{ID: 1},
+			AuthorID:      3,
+			ResponsibleID: 4,
+			Status:        entities.TaskStatus{ID: 1},
+			Workflow:      entities.Workflow{ID: 1},
+			Type:          entities.TaskType{ID: 1},
+			TeamID:        1,
+			Version:       2,
+		}, nil
+	}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		*updated = task
+		task.Version++
+		return task, nil
+	}
+	return repo
+}
+
+func patchRequest(t *testing.T, handler *handlers.TaskHandler, contentType string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPatch, "/todo/5", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("If-Match", `"2"`)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.PatchTask)
+	return w
+}
+
+func TestPatchTask_MergePatchKeepsOtherFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var updated entities.Task
+	handler := taskHandler(patchRepo(&updated), nil)
+
+	w := patchRequest(t, handler, "application/merge-patch+json", `{"title": "Patched", "description": null}`)
+
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"3"` {
+		t.Fatalf("expected 200 with ETag \"3\", got %d %q: %s", w.Code, w.Header().Get("ETag"), w.Body.String())
+	}
+	if updated.Title != "Patched" || updated.Description != "" {
+		t.Fatalf("expected the title to be patched and the description removed, got %q and %q", updated.Title, updated.Description)
+	}
+	if updated.ResponsibleID != 4 || updated.Parent == nil || updated.Parent.ID != 1 || updated.AuthorID != 3 {
+		t.Fatalf("expected the fields left out of the patch to be kept, got %+v", updated)
+	}
+}
+
+func TestPatchTask_JSONPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var updated entities.Task
+	handler := taskHandler(patchRepo(&updated), nil)
+
+	w := patchRequest(t, handler, "application/json-patch+json", `[
+		{"op": "test", "path": "/responsible_id", "value": 4},
+		{"op": "replace", "path": "/responsible_id", "value": 6},
+		{"op": "remove", "path": "/parent_id"},
+		{"op": "copy", "from": "/title", "path": "/description"}
+	]`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if updated.ResponsibleID != 6 || updated.Parent != nil || updated.Description != "Stored" || updated.Title != "Stored" {
+		t.Fatalf("expected the operations to be applied in order, got %+v", updated)
+	}
+
+	updated = entities.Task{}
+	w = patchRequest(t, handler, "application/json-patch+json", `[
+		{"op": "replace", "path": "/title", "value": "Patched"},
+		{"op": "test", "path": "/responsible_id", "value": 5}
+	]`)
+	if w.Code != http.StatusUnprocessableEntity || updated.ID != 0 {
+		t.Fatalf("expected a failed test to reject the whole patch with 422, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPatchTask_RejectsInvalidPatches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var updated entities.Task
+	handler := taskHandler(patchRepo(&updated), nil)
+
+	cases := []struct {
+		contentType string
+		body        string
+		status      int
+	}{
+		{"application/json", `{"title": "Patched"}`, http.StatusUnsupportedMediaType},
+		{"application/merge-patch+json", `{"title": `, http.StatusBadRequest},
+		{"application/merge-patch+json", `["title"]`, http.StatusBadRequest},
+		{"application/json-patch+json", `[{"op": "rename", "path": "/title"}]`, http.StatusBadRequest},
+		{"application/json-patch+json", `[{"op": "remove", "path": "/missing"}]`, http.StatusUnprocessableEntity},
+		{"application/merge-patch+json", `{"title": null}`, http.StatusUnprocessableEntity},
+		{"application/merge-patch+json", `{"title": "   "}`, http.StatusUnprocessableEntity},
+		{"application/json-patch+json", `[{"op": "replace", "path": "/title", "value": "\t"}]`, http.StatusUnprocessableEntity},
+		{"application/merge-patch+json", `{"parent_id": -1}`, http.StatusUnprocessableEntity},
+		{"application/merge-patch+json", `{"responsible_id": "me"}`, http.StatusUnprocessableEntity},
+		{"application/merge-patch+json", `{"status_id": 3}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w := patchRequest(t, handler, tc.contentType, tc.body)
+		if w.Code != tc.status {
+			t.Fatalf("%s %s: expected %d, got %d: %s", tc.contentType, tc.body, tc.status, w.Code, w.Body.String())
+		}
+	}
+	if updated.ID != 0 {
+		t.Fatalf("expected no rejected patch to be stored, got %+v", updated)
+	}
+}
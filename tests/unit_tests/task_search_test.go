@@ -0,0 +1,64 @@
+package unittests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"todo-api/internal/domain"
+)
+
+func TestSearchTerms_DropsOperatorsAndDuplicates(t *testing.T) {
+	terms := domain.SearchTerms(`Login "BUG" -login* OR (mobile)`)
+
+	expected := []string{"login", "bug", "or", "mobile"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Fatalf("expected %v got %v", expected, terms)
+	}
+}
+
+func TestHighlight_MatchesWordPrefixesCaseInsensitively(t *testing.T) {
+	highlighted := domain.Highlight("Fix Login Bug on mobile; relogin works", []string{"login", "bug"})
+
+	expected := "Fix <mark>Login</mark> <mark>Bug</mark> on mobile; relogin works"
+	if highlighted != expected {
+		t.Fatalf("expected %q got %q", expected, highlighted)
+	}
+}
+
+func TestHighlight_EscapesTaskContent(t *testing.T) {
+	highlighted := domain.Highlight(`<script>alert("login")</script> & login`, []string{"login", "script"})
+
+	expected := "&lt;<mark>script</mark>&gt;alert(&#34;<mark>login</mark>&#34;)&lt;/<mark>script</mark>&gt; &amp; <mark>login</mark>"
+	if highlighted != expected {
+		t.Fatalf("expected %q got %q", expected, highlighted)
+	}
+
+	snippet := domain.Snippet(`<img src=x onerror=alert(1)> nothing to match`, []string{"missing"})
+	if strings.Contains(snippet, "<img") {
+		t.Fatalf("expected the snippet to be escaped, got %q", snippet)
+	}
+}
+
+func TestSnippet_CentersOnFirstMatch(t *testing.T) {
+	text := strings.Repeat("filler words here ", 20) + "the cookie handling on iOS Safari " + strings.Repeat("more text ", 20)
+
+	snippet := domain.Snippet(text, []string{"cookie"})
+
+	if !strings.Contains(snippet, "<mark>cookie</mark>") {
+		t.Fatalf("expected the match to be highlighted, got %q", snippet)
+	}
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") {
+		t.Fatalf("expected the snippet to be marked as an excerpt, got %q", snippet)
+	}
+	if len(snippet) > 200 {
+		t.Fatalf("expected a short excerpt, got %d bytes", len(snippet))
+	}
+}
+
+func TestSnippet_WithoutMatchKeepsBeginning(t *testing.T) {
+	snippet := domain.Snippet("Short description", []string{"missing"})
+
+	if snippet != "Short description" {
+		t.Fatalf("expected the whole short text, got %q", snippet)
+	}
+}
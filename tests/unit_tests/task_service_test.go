@@ -0,0 +1,133 @@
+package unittests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+func taskService(repo *mockTaskRepo) *domain.TaskService {
+	users := &mockUserRepo{}
+	users.GetByIDFn = func(id int64) (entities.User, error) { return entities.User{ID: id}, nil }
+	return domain.NewTaskService(repo, transitionWorkflows(), &mockStatusRepo{}, &mockTypeRepo{}, users,
+		&mockDependencyRepo{}, &mockActionFailureRepo{}, &mockNotifier{})
+}
+
+func TestTaskService_UpdateAssignsAndKeepsIdentity(t *testing.T) {
+	stored := entities.Task{ID: 5, AuthorID: 3, Status: entities.TaskStatus{ID: 2}, TeamID: 1, Completed: false,
+		CreatedAt: entities.NewDateTime(time.Now().Add(-time.Hour))}
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return stored, nil }
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) { return task, nil }
+
+	updated, err := taskService(repo).Update(context.Background(), 5, entities.Task{
+		Title:         "Renamed",
+		ResponsibleID: 7,
+		Workflow:      entities.Workflow{ID: 1},
+		Type:          entities.TaskType{ID: 1},
+	}, 3, []int64{1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.ResponsibleID != 7 || updated.Title != "Renamed" {
+		t.Fatalf("expected the changes to be applied, got %+v", updated)
+	}
+	if updated.AuthorID != 3 || updated.Status.ID != 2 || updated.TeamID != 1 || !updated.CreatedAt.Equal(stored.CreatedAt.Time) {
+		t.Fatalf("expected the identity of the task to be kept, got %+v", updated)
+	}
+	if !updated.UpdatedAt.After(stored.CreatedAt.Time) {
+		t.Fatalf("expected the update to be timestamped")
+	}
+}
+
+func TestTaskService_UpdateRejectsForbiddenChanges(t *testing.T) {
+	repo := &mockTaskRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		if id != 5 {
+			return entities.Task{}, domain.NewNotFoundError("task", id)
+		}
+		return entities.Task{ID: 5, AuthorID: 3, Status: entities.TaskStatus{ID: 2}}, nil
+	}
+	service := taskService(repo)
+
+	if _, err := service.Update(context.Background(), 5, entities.Task{AuthorID: 4}, 3, nil); !errors.Is(err, domain.ErrTaskAuthorChange) {
+		t.Fatalf("expected an author change to be rejected, got %v", err)
+	}
+	if _, err := service.Update(context.Background(), 5, entities.Task{Status: entities.TaskStatus{ID: 3}}, 3, nil); !errors.Is(err, domain.ErrTaskStatusChange) {
+		t.Fatalf("expected a status change to be rejected, got %v", err)
+	}
+	if _, err := service.Update(context.Background(), 6, entities.Task{}, 3, nil); !errors.Is(err, domain.ErrNotFound) || err.Error() != "task 6 not found" {
+		t.Fatalf("expected an unknown task to be reported as not found, got %v", err)
+	}
+}
+
+func TestTaskService_CreateKeepsLookupFailures(t *testing.T) {
+	repo := &mockTaskRepo{}
+	repo.CreateFn = func(task entities.Task) (entities.Task, error) {
+		t.Fatalf("expected the task not to be stored, got %+v", task)
+		return task, nil
+	}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) { return entities.Task{}, domain.NewNotFoundError("task", id) }
+	service := taskService(repo)
+
+	// A parent that does not exist is a dangling reference
+	_, err := service.Create(context.Background(), entities.Task{Workflow: entities.Workflow{ID: 1}, Type: entities.TaskType{ID: 1},
+		Parent: &entities.Task{ID: 9}}, 3, []int64{1})
+	var reference *domain.InvalidReferenceError
+	if !errors.As(err, &reference) || reference.Field != "parent" {
+		t.Fatalf("expected an invalid parent reference, got %v", err)
+	}
+
+	// A parent that could not be looked up is not
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		if id == 5 {
+			return entities.Task{ID: 5}, nil
+		}
+		return entities.Task{}, context.DeadlineExceeded
+	}
+	_, err = service.Create(context.Background(), entities.Task{Workflow: entities.Workflow{ID: 1}, Type: entities.TaskType{ID: 1},
+		Parent: &entities.Task{ID: 9}}, 3, []int64{1})
+	if !errors.Is(err, context.DeadlineExceeded) || errors.As(err, &reference) {
+		t.Fatalf("expected the lookup failure to be returned as it is, got %v", err)
+	}
+	_, err = service.AddDependency(context.Background(), 5, 9, []int64{1})
+	if !errors.Is(err, context.DeadlineExceeded) || errors.As(err, &reference) {
+		t.Fatalf("expected the lookup failure to be returned as it is, got %v", err)
+	}
+}
+
+func TestTaskService_OverdueFollowsTaskRule(t *testing.T) {
+	past := entities.NewDateTime(time.Now().Add(-time.Hour))
+	repo := &mockTaskRepo{}
+	repo.GetAllOverdueFn = func() ([]entities.Task, error) {
+		return []entities.Task{
+			{ID: 1, Deadline: past},
+			{ID: 2, Deadline: past, Completed: true},
+			{ID: 3, Deadline: entities.NewDateTime(time.Now().Add(time.Hour))},
+		}, nil
+	}
+
+	tasks, err := taskService(repo).Overdue(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != 1 {
+		t.Fatalf("expected only the open task past its deadline, got %+v", tasks)
+	}
+}
+
+func TestWorkflowService_UpdateRejectsAuthorChange(t *testing.T) {
+	workflows := transitionWorkflows()
+	workflows.GetByIDFn = func(id int64) (entities.Workflow, error) {
+		return entities.Workflow{ID: id, Author: entities.User{ID: 1}}, nil
+	}
+	service := domain.NewWorkflowService(workflows)
+
+	_, err := service.Update(context.Background(), 1, entities.Workflow{Author: entities.User{ID: 2}}, nil, 2, nil)
+	if !errors.Is(err, domain.ErrWorkflowAuthorChange) {
+		t.Fatalf("expected the author change to be rejected, got %v", err)
+	}
+}
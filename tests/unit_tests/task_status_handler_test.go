@@ -2,6 +2,7 @@ package unittests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -18,17 +19,19 @@ type mockStatusRepo struct {
 	GetAllFn func() ([]entities.TaskStatus, error)
 }
 
-func (m *mockStatusRepo) Create(status entities.TaskStatus) (entities.TaskStatus, error) {
+func (m *mockStatusRepo) Create(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error) {
 	return m.CreateFn(status)
 }
-func (m *mockStatusRepo) GetByID(id int64) (entities.TaskStatus, error) {
+func (m *mockStatusRepo) GetByID(ctx context.Context, id int64) (entities.TaskStatus, error) {
 	return entities.TaskStatus{}, nil
 }
-func (m *mockStatusRepo) Update(status entities.TaskStatus) (entities.TaskStatus, error) {
+func (m *mockStatusRepo) Update(ctx context.Context, status entities.TaskStatus) (entities.TaskStatus, error) {
 	return entities.TaskStatus{}, nil
 }
-func (m *mockStatusRepo) Remove(id int64) error                  { return nil }
-func (m *mockStatusRepo) GetAll() ([]entities.TaskStatus, error) { return m.GetAllFn() }
+func (m *mockStatusRepo) Remove(ctx context.Context, id int64, version int64) error { return nil }
+func (m *mockStatusRepo) GetAll(ctx context.Context) ([]entities.TaskStatus, error) {
+	return m.GetAllFn()
+}
 
 func TestCreateTaskStatus_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -40,7 +43,7 @@ func TestCreateTaskStatus_Success(t *testing.T) {
 
 	handler := handlers.NewTaskStatusHandler(repo)
 
-	body := entities.TaskStatus{Label: "Todo", Active: true}
+	body := gin.H{"label": "Todo", "active": true}
 	b, _ := json.Marshal(body)
 
 	w := httptest.NewRecorder()
@@ -49,7 +52,7 @@ func TestCreateTaskStatus_Success(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
-	handler.CreateTaskStatus(c)
+	serve(c, handler.CreateTaskStatus)
 
 	if w.Code != http.StatusCreated {
 		t.Fatalf("expected status %d got %d", http.StatusCreated, w.Code)
@@ -68,7 +71,7 @@ func TestGetAllTaskStatuses_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/task-statuses", nil)
 	c.Request = req
 
-	handler.GetAllTaskStatuses(c)
+	serve(c, handler.GetAllTaskStatuses)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
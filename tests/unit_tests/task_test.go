@@ -1,6 +1,7 @@
 package unittests
 
 import (
+	"errors"
 	"testing"
 	"time"
 	todo "todo-api/internal/domain/entities"
@@ -45,3 +46,283 @@ func TestTask_ChangeStatusMarksCompletedWhenLast(t *testing.T) {
 		t.Fatalf("expected task to be marked completed when status is last")
 	}
 }
+
+func TestTask_TransitionTo(t *testing.T) {
+	todoStatus := todo.TaskStatus{ID: 1, Label: "Todo", Active: true}
+	doingStatus := todo.TaskStatus{ID: 2, Label: "Doing", Active: true}
+	doneStatus := todo.TaskStatus{ID: 3, Label: "Done", Active: true}
+	wf := todo.NewWorkflow("w", map[uint8]todo.TaskStatus{0: todoStatus, 1: doingStatus, 5: doneStatus}, todo.User{ID: 1})
+
+	task := todo.NewTask("t", "d", 1, time.Now().Add(24*time.Hour), todo.TaskType{ID: 1})
+	task.Workflow = wf
+	task.Status = todoStatus
+
+	if err := task.TransitionTo(99, todo.TransitionContext{}); !errors.Is(err, todo.ErrStatusNotInWorkflow) {
+		t.Fatalf("expected a status outside the workflow to be rejected, got %v", err)
+	}
+
+	if err := task.TransitionTo(3, todo.TransitionContext{}); err != nil {
+		t.Fatalf("expected any move within a non sequential workflow, got %v", err)
+	}
+	if !task.Completed || task.Status.Label != "Done" {
+		t.Fatalf("expected the last status to complete the task, got %+v", task.Status)
+	}
+
+	if err := task.TransitionTo(1, todo.TransitionContext{}); err != nil {
+		t.Fatalf("expected the task to be reopened, got %v", err)
+	}
+	if task.Completed {
+		t.Fatalf("expected leaving the last status to reopen the task")
+	}
+}
+
+func TestTask_TransitionToSequentialWorkflow(t *testing.T) {
+	todoStatus := todo.TaskStatus{ID: 1, Label: "Todo", Active: true}
+	doingStatus := todo.TaskStatus{ID: 2, Label: "Doing", Active: true}
+	doneStatus := todo.TaskStatus{ID: 3, Label: "Done", Active: true}
+	wf := todo.NewWorkflow("w", map[uint8]todo.TaskStatus{0: todoStatus, 1: doingStatus, 5: doneStatus}, todo.User{ID: 1})
+	wf.Sequential = true
+
+	task := todo.NewTask("t", "d", 1, time.Now().Add(24*time.Hour), todo.TaskType{ID: 1})
+	task.Workflow = wf
+	task.Status = todoStatus
+
+	if err := task.TransitionTo(3, todo.TransitionContext{}); !errors.Is(err, todo.ErrTransitionNotAllowed) {
+		t.Fatalf("expected skipping a status to be rejected, got %v", err)
+	}
+	if err := task.TransitionTo(2, todo.TransitionContext{}); err != nil {
+		t.Fatalf("expected a move to the next status, got %v", err)
+	}
+	// Position 5 directly follows position 1, gaps in the ordinals do not matter
+	if err := task.TransitionTo(3, todo.TransitionContext{}); err != nil {
+		t.Fatalf("expected a move to the next status, got %v", err)
+	}
+	if !task.Completed {
+		t.Fatalf("expected the last status to complete the task")
+	}
+}
+
+func supportWorkflow() todo.Workflow {
+	wf := todo.NewWorkflow("support", map[uint8]todo.TaskStatus{
+		0: {ID: 1, Label: "Todo", Active: true},
+		1: {ID: 2, Label: "In Progress", Active: true},
+		2: {ID: 3, Label: "Blocked", Active: true},
+		3: {ID: 4, Label: "Done", Active: true},
+		4: {ID: 5, Label: "Cancelled", Active: true},
+	}, todo.User{ID: 1})
+	wf.Graph = &todo.WorkflowGraph{
+		InitialStatusID:   1,
+		TerminalStatusIDs: []int64{4, 5},
+		Transitions: []todo.Transition{
+			{From: 1, To: 2}, {From: 2, To: 3}, {From: 3, To: 2}, {From: 2, To: 4},
+			{From: 1, To: 5}, {From: 2, To: 5}, {From: 3, To: 5},
+		},
+	}
+	return wf
+}
+
+func TestWorkflow_ValidateGraph(t *testing.T) {
+	wf := supportWorkflow()
+	if err := wf.ValidateGraph(); err != nil {
+		t.Fatalf("expected a valid graph, got %v", err)
+	}
+
+	noTerminal := supportWorkflow()
+	noTerminal.Graph.TerminalStatusIDs = nil
+	if err := noTerminal.ValidateGraph(); err == nil {
+		t.Fatalf("expected a graph without terminal status to be rejected")
+	}
+
+	unreachable := supportWorkflow()
+	unreachable.Graph.Transitions = unreachable.Graph.Transitions[:4]
+	if err := unreachable.ValidateGraph(); err == nil {
+		t.Fatalf("expected a status unreachable from the initial one to be rejected")
+	}
+
+	deadEnd := supportWorkflow()
+	deadEnd.Graph.Transitions = []todo.Transition{{From: 1, To: 2}, {From: 2, To: 3}, {From: 2, To: 4}, {From: 2, To: 5}}
+	if err := deadEnd.ValidateGraph(); err == nil {
+		t.Fatalf("expected a non terminal status without way out to be rejected")
+	}
+
+	// Blocked and In Progress loop on each other, Done and Cancelled are out of reach once a task started
+	loop := supportWorkflow()
+	loop.Graph.Transitions = []todo.Transition{{From: 1, To: 2}, {From: 2, To: 3}, {From: 3, To: 2}, {From: 1, To: 4}, {From: 1, To: 5}}
+	if err := loop.ValidateGraph(); err == nil {
+		t.Fatalf("expected a loop without way to a terminal status to be rejected")
+	}
+
+	unknownStatus := supportWorkflow()
+	unknownStatus.Graph.Transitions = append(unknownStatus.Graph.Transitions, todo.Transition{From: 2, To: 99})
+	if err := unknownStatus.ValidateGraph(); err == nil {
+		t.Fatalf("expected a transition to a status outside the workflow to be rejected")
+	}
+}
+
+func TestTask_TransitionToFollowsGraph(t *testing.T) {
+	task := todo.NewTask("t", "d", 1, time.Now().Add(24*time.Hour), todo.TaskType{ID: 1})
+	task.Workflow = supportWorkflow()
+	task.Status = todo.TaskStatus{ID: 1}
+
+	if err := task.TransitionTo(3, todo.TransitionContext{}); !errors.Is(err, todo.ErrTransitionNotAllowed) {
+		t.Fatalf("expected a move without edge to be rejected, got %v", err)
+	}
+	for _, statusID := range []int64{2, 3, 2} {
+		if err := task.TransitionTo(statusID, todo.TransitionContext{}); err != nil {
+			t.Fatalf("expected the move to %d to be allowed, got %v", statusID, err)
+		}
+	}
+	if task.Completed {
+		t.Fatalf("expected the task to stay open before a terminal status")
+	}
+
+	if err := task.TransitionTo(5, todo.TransitionContext{}); err != nil {
+		t.Fatalf("expected the task to be cancelled, got %v", err)
+	}
+	if !task.Completed {
+		t.Fatalf("expected every terminal status to complete the task")
+	}
+	if err := task.TransitionTo(1, todo.TransitionContext{}); !errors.Is(err, todo.ErrTransitionNotAllowed) {
+		t.Fatalf("expected a terminal status without edges to be final, got %v", err)
+	}
+}
+
+func TestTask_TransitionToChecksGuards(t *testing.T) {
+	todoStatus := todo.TaskStatus{ID: 1, Label: "Todo", Active: true}
+	reviewStatus := todo.TaskStatus{ID: 2, Label: "In Review", Active: true}
+	doneStatus := todo.TaskStatus{ID: 3, Label: "Done", Active: true}
+	wf := todo.NewWorkflow("w", map[uint8]todo.TaskStatus{0: todoStatus, 1: reviewStatus, 2: doneStatus}, todo.User{ID: 1})
+	wf.Guards = []todo.TransitionGuard{
+		{ToStatusID: 2, Kind: todo.GuardRequiredField, Field: "responsible"},
+		{ToStatusID: 2, Kind: todo.GuardRequiredField, Field: "description"},
+		{FromStatusID: 2, ToStatusID: 3, Kind: todo.GuardSubtasksCompleted},
+	}
+	if err := wf.ValidateGuards(); err != nil {
+		t.Fatalf("expected valid guards, got %v", err)
+	}
+
+	task := todo.NewTask("t", "", 1, time.Now().Add(24*time.Hour), todo.TaskType{ID: 1})
+	task.Workflow = wf
+	task.Status = todoStatus
+
+	err := task.TransitionTo(2, todo.TransitionContext{})
+	var guardErr *todo.GuardError
+	if !errors.As(err, &guardErr) || len(guardErr.Failures) != 2 {
+		t.Fatalf("expected both missing fields to be reported, got %v", err)
+	}
+	if task.Status.ID != 1 {
+		t.Fatalf("expected the status to be unchanged after failed guards")
+	}
+
+	task.AssignTo(7)
+	task.Description = "ready"
+	if err := task.TransitionTo(2, todo.TransitionContext{}); err != nil {
+		t.Fatalf("expected the guards to pass, got %v", err)
+	}
+
+	open := todo.TransitionContext{Subtasks: []todo.Task{{ID: 8, Completed: true}, {ID: 9}}}
+	if err := task.TransitionTo(3, open); !errors.As(err, &guardErr) {
+		t.Fatalf("expected open subtasks to block the task, got %v", err)
+	}
+	done := todo.TransitionContext{Subtasks: []todo.Task{{ID: 8, Completed: true}}}
+	if err := task.TransitionTo(3, done); err != nil {
+		t.Fatalf("expected completed subtasks to let the task finish, got %v", err)
+	}
+}
+
+func TestWorkflow_ValidateGuards(t *testing.T) {
+	wf := todo.NewWorkflow("w", map[uint8]todo.TaskStatus{0: {ID: 1, Label: "Todo", Active: true}}, todo.User{ID: 1})
+
+	wf.Guards = []todo.TransitionGuard{{ToStatusID: 1, Kind: "approved_by_cto"}}
+	if err := wf.ValidateGuards(); err == nil {
+		t.Fatalf("expected an unknown guard kind to be rejected")
+	}
+	wf.Guards = []todo.TransitionGuard{{ToStatusID: 1, Kind: todo.GuardRequiredField, Field: "budget"}}
+	if err := wf.ValidateGuards(); err == nil {
+		t.Fatalf("expected an unknown field to be rejected")
+	}
+	wf.Guards = []todo.TransitionGuard{{ToStatusID: 9, Kind: todo.GuardSubtasksCompleted}}
+	if err := wf.ValidateGuards(); err == nil {
+		t.Fatalf("expected a status outside the workflow to be rejected")
+	}
+}
+
+func TestWorkflow_ValidateActions(t *testing.T) {
+	wf := todo.NewWorkflow("w", map[uint8]todo.TaskStatus{0: {ID: 1, Label: "Todo", Active: true}, 1: {ID: 2, Label: "Done", Active: true}}, todo.User{ID: 1})
+
+	wf.Actions = []todo.TransitionAction{{ToStatusID: 2, Kind: "close_sprint"}}
+	if err := wf.ValidateActions(); err == nil {
+		t.Fatalf("expected an unknown action kind to be rejected")
+	}
+	wf.Actions = []todo.TransitionAction{{ToStatusID: 2, Kind: todo.ActionSetDeadline}}
+	if err := wf.ValidateActions(); err == nil {
+		t.Fatalf("expected a set_deadline action without offset to be rejected")
+	}
+	wf.Actions = []todo.TransitionAction{{ToStatusID: 9, Kind: todo.ActionAssign, UserID: 3}}
+	if err := wf.ValidateActions(); err == nil {
+		t.Fatalf("expected a status outside the workflow to be rejected")
+	}
+
+	wf.Actions = []todo.TransitionAction{
+		{ToStatusID: 2, Kind: todo.ActionAssign, UserID: 3},
+		{FromStatusID: 2, ToStatusID: 1, Kind: todo.ActionNotifyWatchers, WatcherIDs: []int64{4}},
+	}
+	if err := wf.ValidateActions(); err != nil {
+		t.Fatalf("expected valid actions, got %v", err)
+	}
+	if actions := wf.ActionsFor(1, 2); len(actions) != 1 || actions[0].Kind != todo.ActionAssign {
+		t.Fatalf("expected the assignment to run when entering Done, got %+v", actions)
+	}
+	if actions := wf.ActionsFor(1, 1); len(actions) != 0 {
+		t.Fatalf("expected no action for a move from another status, got %+v", actions)
+	}
+}
+
+func TestTask_Changes(t *testing.T) {
+	deadline := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	before := todo.Task{ID: 4, Title: "t", Status: todo.TaskStatus{ID: 1}, Deadline: todo.NewDateTime(deadline), ResponsibleID: 2}
+
+	if events := before.Changes(before, 1); len(events) != 0 {
+		t.Fatalf("expected no events for an unchanged task, got %+v", events)
+	}
+
+	after := before
+	after.Title = "t2"
+	after.ResponsibleID = 0
+	after.Parent = &todo.Task{ID: 9}
+	after.Deadline = todo.NewDateTime(deadline.In(time.FixedZone("CET", 3600)))
+
+	events := before.Changes(after, 7)
+	if len(events) != 3 {
+		t.Fatalf("expected title, parent and responsible events, got %+v", events)
+	}
+	if events[0].Field != "title" || *events[0].OldValue != "t" || *events[0].NewValue != "t2" || events[0].ActorID != 7 || events[0].TaskID != 4 {
+		t.Fatalf("unexpected title event %+v", events[0])
+	}
+	if events[1].Field != "parent" || events[1].OldValue != nil || *events[1].NewValue != "9" {
+		t.Fatalf("unexpected parent event %+v", events[1])
+	}
+	if events[2].Field != "responsible" || *events[2].OldValue != "2" || events[2].NewValue != nil {
+		t.Fatalf("unexpected responsible event %+v", events[2])
+	}
+}
+
+func TestWorkflowMigration_Plan(t *testing.T) {
+	target := todo.NewWorkflow("w", map[uint8]todo.TaskStatus{0: {ID: 1, Label: "Todo", Active: true}, 1: {ID: 2, Label: "Done", Active: true}}, todo.User{ID: 1})
+
+	var orphaned *todo.OrphanedTasksError
+	if _, err := (todo.WorkflowMigration{}).Plan(&target, []int64{4, 1, 3}); !errors.As(err, &orphaned) || len(orphaned.StatusIDs) != 2 || orphaned.StatusIDs[0] != 3 {
+		t.Fatalf("expected statuses 3 and 4 to be reported, got %v", err)
+	}
+	if _, err := (todo.WorkflowMigration{StatusMapping: map[int64]int64{3: 9}}).Plan(&target, []int64{3}); !errors.Is(err, todo.ErrInvalidMigration) {
+		t.Fatalf("expected a mapping outside the target to be rejected, got %v", err)
+	}
+
+	plan, err := (todo.WorkflowMigration{StatusMapping: map[int64]int64{3: 2, 4: 1}}).Plan(&target, []int64{1, 3, 4})
+	if err != nil {
+		t.Fatalf("expected a complete mapping to be planned, got %v", err)
+	}
+	if plan[1] != 1 || plan[3] != 2 || plan[4] != 1 {
+		t.Fatalf("unexpected plan %v", plan)
+	}
+}
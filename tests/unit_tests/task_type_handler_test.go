@@ -2,6 +2,7 @@ package unittests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -18,15 +19,19 @@ type mockTypeRepo struct {
 	GetAllFn func() ([]entities.TaskType, error)
 }
 
-func (m *mockTypeRepo) Create(taskType entities.TaskType) (entities.TaskType, error) {
+func (m *mockTypeRepo) Create(ctx context.Context, taskType entities.TaskType) (entities.TaskType, error) {
 	return m.CreateFn(taskType)
 }
-func (m *mockTypeRepo) GetByID(id int64) (entities.TaskType, error) { return entities.TaskType{}, nil }
-func (m *mockTypeRepo) Update(taskType entities.TaskType) (entities.TaskType, error) {
+func (m *mockTypeRepo) GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.TaskType, error) {
 	return entities.TaskType{}, nil
 }
-func (m *mockTypeRepo) Remove(id int64) error                { return nil }
-func (m *mockTypeRepo) GetAll() ([]entities.TaskType, error) { return m.GetAllFn() }
+func (m *mockTypeRepo) Update(ctx context.Context, taskType entities.TaskType) (entities.TaskType, error) {
+	return entities.TaskType{}, nil
+}
+func (m *mockTypeRepo) Remove(ctx context.Context, id int64, version int64) error { return nil }
+func (m *mockTypeRepo) GetAll(ctx context.Context, teamIDs []int64) ([]entities.TaskType, error) {
+	return m.GetAllFn()
+}
 
 func TestCreateTaskType_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -38,7 +43,7 @@ func TestCreateTaskType_Success(t *testing.T) {
 
 	handler := handlers.NewTaskTypeHandler(repo)
 
-	body := entities.TaskType{Name: "Bug"}
+	body := gin.H{"name": "Bug"}
 	b, _ := json.Marshal(body)
 
 	w := httptest.NewRecorder()
@@ -47,7 +52,7 @@ func TestCreateTaskType_Success(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
-	handler.CreateTaskType(c)
+	serve(c, handler.CreateTaskType)
 
 	if w.Code != http.StatusCreated {
 		t.Fatalf("expected status %d got %d", http.StatusCreated, w.Code)
@@ -66,7 +71,7 @@ func TestGetAllTaskTypes_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/task-types", nil)
 	c.Request = req
 
-	handler.GetAllTaskTypes(c)
+	serve(c, handler.GetAllTaskTypes)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
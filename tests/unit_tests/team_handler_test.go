@@ -0,0 +1,123 @@
+package unittests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockTeamRepo struct {
+	CreateFn         func(team entities.Team) (entities.Team, error)
+	GetByIDFn        func(id int64) (entities.Team, error)
+	GetAllFn         func() ([]entities.Team, error)
+	GetAllByMemberFn func(userID int64) ([]entities.Team, error)
+	AddMemberFn      func(teamID int64, userID int64) error
+}
+
+func (m *mockTeamRepo) Create(ctx context.Context, team entities.Team) (entities.Team, error) {
+	return m.CreateFn(team)
+}
+func (m *mockTeamRepo) GetByID(ctx context.Context, id int64) (entities.Team, error) {
+	return m.GetByIDFn(id)
+}
+func (m *mockTeamRepo) Update(ctx context.Context, team entities.Team) (entities.Team, error) {
+	return team, nil
+}
+func (m *mockTeamRepo) Remove(ctx context.Context, id int64) error          { return nil }
+func (m *mockTeamRepo) GetAll(ctx context.Context) ([]entities.Team, error) { return m.GetAllFn() }
+func (m *mockTeamRepo) GetAllByMember(ctx context.Context, userID int64) ([]entities.Team, error) {
+	return m.GetAllByMemberFn(userID)
+}
+func (m *mockTeamRepo) GetTeamIDsByMember(ctx context.Context, userID int64) ([]int64, error) {
+	return nil, nil
+}
+func (m *mockTeamRepo) AddMember(ctx context.Context, teamID int64, userID int64) error {
+	return m.AddMemberFn(teamID, userID)
+}
+func (m *mockTeamRepo) RemoveMember(ctx context.Context, teamID int64, userID int64) error {
+	return nil
+}
+func (m *mockTeamRepo) GetMembers(ctx context.Context, teamID int64) ([]entities.User, error) {
+	return []entities.User{{ID: 1}}, nil
+}
+
+func TestGetAllTeams_MemberSeesOwnTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTeamRepo{}
+	repo.GetAllFn = func() ([]entities.Team, error) {
+		t.Fatal("non admin callers must not list every team")
+		return nil, nil
+	}
+	repo.GetAllByMemberFn = func(userID int64) ([]entities.Team, error) {
+		if userID != 42 {
+			t.Fatalf("expected teams of the caller, got user %d", userID)
+		}
+		return []entities.Team{{ID: 1, Name: "Platform"}}, nil
+	}
+
+	handler := handlers.NewTeamHandler(repo)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/teams", nil)
+	c.Set("user_id", int64(42))
+	c.Set("user_role", entities.RoleMember)
+
+	serve(c, handler.GetAllTeams)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestGetTeamMembers_HidesOtherTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTeamHandler(&mockTeamRepo{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/teams/2/members", nil)
+	c.Params = gin.Params{{Key: "id", Value: "2"}}
+	c.Set("user_id", int64(42))
+	c.Set("user_role", entities.RoleMember)
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.GetTeamMembers)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestAddTeamMember_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockTeamRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Team, error) { return entities.Team{ID: id}, nil }
+	repo.AddMemberFn = func(teamID int64, userID int64) error { return domain.ErrAlreadyTeamMember }
+
+	handler := handlers.NewTeamHandler(repo)
+
+	b, _ := json.Marshal(map[string]int64{"user_id": 3})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/teams/1/members", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	serve(c, handler.AddTeamMember)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d got %d", http.StatusConflict, w.Code)
+	}
+}
@@ -0,0 +1,176 @@
+package unittests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+)
+
+type mockActionFailureRepo struct {
+	recorded []entities.ActionFailure
+}
+
+func (m *mockActionFailureRepo) Record(ctx context.Context, failure entities.ActionFailure) (entities.ActionFailure, error) {
+	failure.ID = int64(len(m.recorded) + 1)
+	m.recorded = append(m.recorded, failure)
+	return failure, nil
+}
+func (m *mockActionFailureRepo) GetAllByTask(ctx context.Context, taskID int64) ([]entities.ActionFailure, error) {
+	return m.recorded, nil
+}
+
+type mockNotifier struct {
+	err      error
+	notified []int64
+}
+
+func (m *mockNotifier) Notify(userIDs []int64, task entities.Task, message string) error {
+	m.notified = append(m.notified, userIDs...)
+	return m.err
+}
+
+func actionWorkflow(actions ...entities.TransitionAction) entities.Workflow {
+	return entities.Workflow{
+		ID: 4,
+		Statuses: map[uint8]entities.TaskStatus{
+			0: {ID: 1, Label: "Todo", Active: true},
+			1: {ID: 2, Label: "Review", Active: true},
+			2: {ID: 3, Label: "Done", Active: true},
+		},
+		Actions: actions,
+	}
+}
+
+func TestTransitionActionRunner_AssignsAndSetsDeadline(t *testing.T) {
+	repo := &mockTaskRepo{}
+	var stored entities.Task
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		stored = task
+		return task, nil
+	}
+	failures := &mockActionFailureRepo{}
+	runner := domain.NewTransitionActionRunner(repo, &mockWorkflowRepo{}, &mockDependencyRepo{}, failures, &mockNotifier{})
+
+	task := entities.Task{ID: 5, Status: entities.TaskStatus{ID: 2}, Workflow: actionWorkflow(
+		entities.TransitionAction{ToStatusID: 2, Kind: entities.ActionAssign, UserID: 7},
+		entities.TransitionAction{FromStatusID: 1, ToStatusID: 2, Kind: entities.ActionSetDeadline, DeadlineOffsetHours: 48},
+		entities.TransitionAction{ToStatusID: 3, Kind: entities.ActionAssign, UserID: 9},
+	)}
+
+	if got := runner.Run(context.Background(), &task, 1, 1, nil); len(got) != 0 {
+		t.Fatalf("expected no failures, got %+v", got)
+	}
+	if task.ResponsibleID != 7 {
+		t.Fatalf("expected the task to be assigned to 7, got %v", task.ResponsibleID)
+	}
+	if until := time.Until(task.Deadline.Time); until < 47*time.Hour || until > 48*time.Hour {
+		t.Fatalf("expected the deadline 48 hours ahead, got %v", until)
+	}
+	if stored.ResponsibleID != 7 {
+		t.Fatalf("expected the assignment to be stored")
+	}
+}
+
+func TestTransitionActionRunner_RecordsFailures(t *testing.T) {
+	repo := &mockTaskRepo{}
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		return entities.Task{}, errors.New("database is gone")
+	}
+	failures := &mockActionFailureRepo{}
+	notifier := &mockNotifier{err: errors.New("mail server down")}
+	runner := domain.NewTransitionActionRunner(repo, &mockWorkflowRepo{}, &mockDependencyRepo{}, failures, notifier)
+
+	task := entities.Task{ID: 5, Status: entities.TaskStatus{ID: 3}, Workflow: actionWorkflow(
+		entities.TransitionAction{ToStatusID: 3, Kind: entities.ActionAssign, UserID: 7},
+		entities.TransitionAction{ToStatusID: 3, Kind: entities.ActionNotifyWatchers, WatcherIDs: []int64{2, 3}},
+	)}
+
+	got := runner.Run(context.Background(), &task, 2, 1, nil)
+	if len(got) != 2 || len(failures.recorded) != 2 {
+		t.Fatalf("expected both failures to be recorded, got %+v", got)
+	}
+	if got[0].Action != entities.ActionAssign || got[0].Error != "database is gone" || got[0].FromStatusID != 2 {
+		t.Fatalf("unexpected failure %+v", got[0])
+	}
+	if got[1].Action != entities.ActionNotifyWatchers || len(notifier.notified) != 2 {
+		t.Fatalf("expected the watchers to be notified after the failed assignment, got %+v", got[1])
+	}
+	if task.ResponsibleID != 0 {
+		t.Fatalf("expected a failed assignment to leave the task unchanged")
+	}
+}
+
+func TestTransitionActionRunner_AdvancesParent(t *testing.T) {
+	workflow := actionWorkflow(entities.TransitionAction{ToStatusID: 3, Kind: entities.ActionAdvanceParent, ParentStatusID: 2})
+	workflows := &mockWorkflowRepo{}
+	workflows.GetByIDFn = func(id int64) (entities.Workflow, error) { return workflow, nil }
+
+	siblingDone := false
+	repo := &mockTaskRepo{}
+	repo.GetAllByParentFn = func(parentID int64) ([]entities.Task, error) {
+		return []entities.Task{{ID: 5, Completed: true}, {ID: 6, Completed: siblingDone}}, nil
+	}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, Status: entities.TaskStatus{ID: 1}, Workflow: entities.Workflow{ID: 4}}, nil
+	}
+	var stored []entities.Task
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		stored = append(stored, task)
+		return task, nil
+	}
+	runner := domain.NewTransitionActionRunner(repo, workflows, &mockDependencyRepo{}, &mockActionFailureRepo{}, &mockNotifier{})
+
+	task := entities.Task{ID: 5, Status: entities.TaskStatus{ID: 3}, Completed: true, Workflow: workflow, Parent: &entities.Task{ID: 1}}
+	if got := runner.Run(context.Background(), &task, 2, 1, nil); len(got) != 0 || len(stored) != 0 {
+		t.Fatalf("expected the parent to wait for its open subtask, got %+v and %d updates", got, len(stored))
+	}
+
+	siblingDone = true
+	if got := runner.Run(context.Background(), &task, 2, 1, nil); len(got) != 0 {
+		t.Fatalf("expected no failures, got %+v", got)
+	}
+	if len(stored) != 1 || stored[0].ID != 1 || stored[0].Status.ID != 2 {
+		t.Fatalf("expected the parent to move to status 2, got %+v", stored)
+	}
+}
+
+func TestTransitionActionRunner_RunsTheActionsOfTheAdvancedParent(t *testing.T) {
+	workflow := actionWorkflow(
+		entities.TransitionAction{ToStatusID: 3, Kind: entities.ActionAdvanceParent, ParentStatusID: 2},
+		entities.TransitionAction{ToStatusID: 2, Kind: entities.ActionSetDeadline, DeadlineOffsetHours: 24},
+	)
+	workflows := &mockWorkflowRepo{}
+	workflows.GetByIDFn = func(id int64) (entities.Workflow, error) { return workflow, nil }
+
+	// The repository checks versions as the real one does
+	versions := map[int64]int64{1: 4}
+	repo := &mockTaskRepo{}
+	repo.GetAllByParentFn = func(parentID int64) ([]entities.Task, error) {
+		return []entities.Task{{ID: 5, Completed: true}}, nil
+	}
+	repo.GetByIDFn = func(id int64) (entities.Task, error) {
+		return entities.Task{ID: id, Status: entities.TaskStatus{ID: 1}, Workflow: entities.Workflow{ID: 4}, Version: versions[id]}, nil
+	}
+	var stored []entities.Task
+	repo.UpdateFn = func(task entities.Task) (entities.Task, error) {
+		if task.Version != versions[task.ID] {
+			return entities.Task{}, domain.ErrVersionConflict
+		}
+		versions[task.ID]++
+		task.Version = versions[task.ID]
+		stored = append(stored, task)
+		return task, nil
+	}
+	runner := domain.NewTransitionActionRunner(repo, workflows, &mockDependencyRepo{}, &mockActionFailureRepo{}, &mockNotifier{})
+
+	task := entities.Task{ID: 5, Status: entities.TaskStatus{ID: 3}, Completed: true, Workflow: workflow, Parent: &entities.Task{ID: 1}}
+	if got := runner.Run(context.Background(), &task, 2, 1, nil); len(got) != 0 {
+		t.Fatalf("expected the deadline of the parent to be set, got %+v", got)
+	}
+	if len(stored) != 2 || stored[1].ID != 1 || stored[1].Version != 6 || stored[1].Deadline.IsZero() {
+		t.Fatalf("expected the parent to move to status 2 then get a deadline, got %+v", stored)
+	}
+}
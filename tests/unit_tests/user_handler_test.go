@@ -0,0 +1,206 @@
+package unittests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/domain/entities"
+	"todo-api/internal/infrastructure/api/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockUserRepo struct {
+	CreateFn        func(user entities.User) (entities.User, error)
+	GetByIDFn       func(id int64) (entities.User, error)
+	GetByUsernameFn func(username string) (entities.User, error)
+	UpdateFn        func(user entities.User) (entities.User, error)
+	DeactivateFn    func(id int64) error
+	GetAllFn        func() ([]entities.User, error)
+}
+
+func (m *mockUserRepo) Create(ctx context.Context, user entities.User) (entities.User, error) {
+	return m.CreateFn(user)
+}
+func (m *mockUserRepo) GetByID(ctx context.Context, id int64) (entities.User, error) {
+	return m.GetByIDFn(id)
+}
+func (m *mockUserRepo) GetByUsername(ctx context.Context, username string) (entities.User, error) {
+	return m.GetByUsernameFn(username)
+}
+func (m *mockUserRepo) Update(ctx context.Context, user entities.User) (entities.User, error) {
+	return m.UpdateFn(user)
+}
+func (m *mockUserRepo) Deactivate(ctx context.Context, id int64) error      { return m.DeactivateFn(id) }
+func (m *mockUserRepo) GetAll(ctx context.Context) ([]entities.User, error) { return m.GetAllFn() }
+
+func TestCreateUser_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockUserRepo{}
+	repo.CreateFn = func(user entities.User) (entities.User, error) {
+		if !user.Active {
+			t.Fatalf("expected new users to be active")
+		}
+		if !user.CheckPassword("s3cret") {
+			t.Fatalf("expected the password to be hashed into the user")
+		}
+		user.ID = 7
+		return user, nil
+	}
+
+	handler := handlers.NewUserHandler(repo)
+
+	body := gin.H{"name": "Jane", "username": "jane", "email": "jane@example.com", "password": "s3cret"}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	serve(c, handler.CreateUser)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d got %d, body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte("PasswordHash")) {
+		t.Fatalf("expected the password hash to be omitted from the response")
+	}
+}
+
+func TestCreateUser_MissingPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewUserHandler(&mockUserRepo{})
+
+	body := gin.H{"name": "Jane", "username": "jane", "email": "jane@example.com"}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	serve(c, handler.CreateUser)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestCreateUser_PasswordTooLongInBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewUserHandler(&mockUserRepo{
+		CreateFn: func(user entities.User) (entities.User, error) {
+			t.Fatalf("expected a password bcrypt cannot hash not to be stored")
+			return user, nil
+		},
+	})
+
+	// 30 characters, but 90 bytes past bcrypt's limit of 72
+	body := gin.H{"name": "Jane", "username": "jane", "email": "jane@example.com", "password": strings.Repeat("€", 30)}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	serve(c, handler.CreateUser)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "password must be at most 72 bytes long") {
+		t.Fatalf("expected the password length to be reported, got %s", w.Body.String())
+	}
+}
+
+func TestCreateUser_DuplicateUsername(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockUserRepo{}
+	repo.CreateFn = func(user entities.User) (entities.User, error) {
+		return entities.User{}, domain.ErrUsernameTaken
+	}
+
+	handler := handlers.NewUserHandler(repo)
+
+	body := gin.H{"name": "Jane", "username": "jane", "email": "jane@example.com", "password": "s3cret"}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	serve(c, handler.CreateUser)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestUpdateUser_DuplicateEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockUserRepo{}
+	repo.GetByIDFn = func(id int64) (entities.User, error) {
+		return entities.User{ID: id, Name: "Jane", Username: "jane", Email: "jane@example.com", Active: true}, nil
+	}
+	repo.UpdateFn = func(user entities.User) (entities.User, error) {
+		return entities.User{}, domain.ErrEmailTaken
+	}
+
+	handler := handlers.NewUserHandler(repo)
+
+	body := gin.H{"name": "Jane", "username": "jane", "email": "john@example.com"}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "2"}}
+
+	serve(c, handler.UpdateUser)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestDeactivateUser_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockUserRepo{}
+	repo.DeactivateFn = func(id int64) error { return domain.NewNotFoundError("user", id) }
+
+	handler := handlers.NewUserHandler(repo)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodDelete, "/users/999", nil)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "999"}}
+
+	serve(c, handler.DeactivateUser)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestNewUser_PasswordTooLongToHash(t *testing.T) {
+	user, err := entities.NewUser("Jane", "jane", "jane@example.com", strings.Repeat("a", 73))
+	if err == nil {
+		t.Fatalf("expected the password to fail hashing, got hash %q", user.PasswordHash)
+	}
+}
@@ -2,11 +2,15 @@ package unittests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"todo-api/internal/domain"
 	"todo-api/internal/domain/entities"
 	"todo-api/internal/infrastructure/api/handlers"
 
@@ -14,21 +18,41 @@ import (
 )
 
 type mockWorkflowRepo struct {
-	CreateFn func(w entities.Workflow) (entities.Workflow, error)
-	GetAllFn func() ([]entities.Workflow, error)
+	CreateFn  func(w entities.Workflow) (entities.Workflow, error)
+	GetByIDFn func(id int64) (entities.Workflow, error)
+	UpdateFn  func(w entities.Workflow, migration *entities.WorkflowMigration) (entities.Workflow, error)
+	RemoveFn  func(id int64, migration *entities.WorkflowMigration) error
+	MigrateFn func(migration entities.WorkflowMigration) (int64, error)
+	GetAllFn  func() ([]entities.Workflow, error)
 }
 
-func (m *mockWorkflowRepo) Create(w entities.Workflow) (entities.Workflow, error) {
+func (m *mockWorkflowRepo) Create(ctx context.Context, w entities.Workflow) (entities.Workflow, error) {
 	return m.CreateFn(w)
 }
-func (m *mockWorkflowRepo) GetByID(id int64) (entities.Workflow, error) {
+func (m *mockWorkflowRepo) GetByID(ctx context.Context, id int64, teamIDs []int64) (entities.Workflow, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(id)
+	}
 	return entities.Workflow{}, nil
 }
-func (m *mockWorkflowRepo) Update(w entities.Workflow) (entities.Workflow, error) {
+func (m *mockWorkflowRepo) Update(ctx context.Context, w entities.Workflow, migration *entities.WorkflowMigration, teamIDs []int64) (entities.Workflow, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(w, migration)
+	}
 	return entities.Workflow{}, nil
 }
-func (m *mockWorkflowRepo) Remove(id int64) error                { return nil }
-func (m *mockWorkflowRepo) GetAll() ([]entities.Workflow, error) { return m.GetAllFn() }
+func (m *mockWorkflowRepo) Remove(ctx context.Context, id int64, version int64, migration *entities.WorkflowMigration, teamIDs []int64) error {
+	if m.RemoveFn != nil {
+		return m.RemoveFn(id, migration)
+	}
+	return nil
+}
+func (m *mockWorkflowRepo) Migrate(ctx context.Context, migration entities.WorkflowMigration, teamIDs []int64) (int64, error) {
+	return m.MigrateFn(migration)
+}
+func (m *mockWorkflowRepo) GetAll(ctx context.Context, teamIDs []int64) ([]entities.Workflow, error) {
+	return m.GetAllFn()
+}
 
 func TestCreateWorkflow_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -38,9 +62,9 @@ func TestCreateWorkflow_Success(t *testing.T) {
 		return w, nil
 	}
 
-	handler := handlers.NewWorkflowHandler(repo)
+	handler := handlers.NewWorkflowHandler(domain.NewWorkflowService(repo))
 
-	body := entities.Workflow{Name: "Default"}
+	body := gin.H{"name": "Default", "statuses": gin.H{"0": gin.H{"id": 1, "active": true}}}
 	b, _ := json.Marshal(body)
 
 	w := httptest.NewRecorder()
@@ -48,29 +72,189 @@ func TestCreateWorkflow_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/workflows", bytes.NewReader(b))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
+	c.Set("user_id", int64(1))
+	c.Set("team_ids", []int64{1})
 
-	handler.CreateWorkflow(c)
+	serve(c, handler.CreateWorkflow)
 
 	if w.Code != http.StatusCreated {
 		t.Fatalf("expected status %d got %d", http.StatusCreated, w.Code)
 	}
 }
 
+func TestCreateWorkflow_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockWorkflowRepo{}
+	repo.CreateFn = func(w entities.Workflow) (entities.Workflow, error) {
+		t.Fatalf("expected an invalid workflow not to be stored")
+		return w, nil
+	}
+
+	handler := handlers.NewWorkflowHandler(domain.NewWorkflowService(repo))
+
+	// The guard points to a status the workflow does not contain
+	body := gin.H{
+		"name":     "Default",
+		"statuses": gin.H{"0": gin.H{"id": 1, "active": true}},
+		"guards":   []gin.H{{"to_status_id": 9, "kind": "subtasks_completed"}},
+	}
+	b, _ := json.Marshal(body)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/workflows", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Set("user_id", int64(1))
+	c.Set("team_ids", []int64{1})
+
+	serve(c, handler.CreateWorkflow)
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "guard status 9 is not part of the workflow") {
+		t.Fatalf("expected 400 naming the guard, got %d %s", w.Code, w.Body.String())
+	}
+}
+
 func TestGetAllWorkflows_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	repo := &mockWorkflowRepo{}
 	repo.GetAllFn = func() ([]entities.Workflow, error) { return []entities.Workflow{{ID: 1, Name: "W"}}, nil }
 
-	handler := handlers.NewWorkflowHandler(repo)
+	handler := handlers.NewWorkflowHandler(domain.NewWorkflowService(repo))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	req := httptest.NewRequest(http.MethodGet, "/workflows", nil)
 	c.Request = req
 
-	handler.GetAllWorkflows(c)
+	serve(c, handler.GetAllWorkflows)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status %d got %d", http.StatusOK, w.Code)
 	}
 }
+
+func workflowRequest(method, target, body string) (*httptest.ResponseRecorder, *gin.Context) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", "*")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "2"}}
+	c.Set("user_id", int64(1))
+	c.Set("team_ids", []int64{1})
+	return w, c
+}
+
+func TestUpdateWorkflow_OrphanedTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockWorkflowRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Workflow, error) {
+		return entities.Workflow{ID: id, Author: entities.User{ID: 1}, TeamID: 1}, nil
+	}
+	repo.UpdateFn = func(w entities.Workflow, migration *entities.WorkflowMigration) (entities.Workflow, error) {
+		if migration != nil {
+			t.Fatalf("expected no migration without a status mapping")
+		}
+		return entities.Workflow{}, &entities.OrphanedTasksError{StatusIDs: []int64{2}}
+	}
+
+	w, c := workflowRequest(http.MethodPut, "/workflows/2", `{"name": "W", "statuses": {"0": {"id": 1, "active": true}}}`)
+	serve(c, handlers.NewWorkflowHandler(domain.NewWorkflowService(repo)).UpdateWorkflow)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d got %d", http.StatusConflict, w.Code)
+	}
+	var body struct {
+		OrphanedStatusIDs []int64 `json:"orphaned_status_ids"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.OrphanedStatusIDs) != 1 || body.OrphanedStatusIDs[0] != 2 {
+		t.Fatalf("expected the orphaned statuses to be listed, got %s", w.Body.String())
+	}
+}
+
+func TestUpdateWorkflow_WithStatusMapping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockWorkflowRepo{}
+	repo.GetByIDFn = func(id int64) (entities.Workflow, error) {
+		return entities.Workflow{ID: id, Author: entities.User{ID: 1}, TeamID: 1}, nil
+	}
+	repo.UpdateFn = func(w entities.Workflow, migration *entities.WorkflowMigration) (entities.Workflow, error) {
+		if migration == nil || migration.StatusMapping[2] != 1 || migration.ActorID != 1 {
+			t.Fatalf("expected the status mapping to be passed on, got %+v", migration)
+		}
+		return w, nil
+	}
+
+	w, c := workflowRequest(http.MethodPut, "/workflows/2", `{"name": "W", "statuses": {"0": {"id": 1, "active": true}}, "status_mapping": {"2": 1}}`)
+	serve(c, handlers.NewWorkflowHandler(domain.NewWorkflowService(repo)).UpdateWorkflow)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestDeleteWorkflow_WithMigration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockWorkflowRepo{}
+	repo.RemoveFn = func(id int64, migration *entities.WorkflowMigration) error {
+		if migration == nil || migration.SourceWorkflowID != 2 || migration.TargetWorkflowID != 1 {
+			t.Fatalf("expected the tasks to be moved to workflow 1, got %+v", migration)
+		}
+		return nil
+	}
+
+	w, c := workflowRequest(http.MethodDelete, "/workflows/2", `{"target_workflow_id": 1}`)
+	serve(c, handlers.NewWorkflowHandler(domain.NewWorkflowService(repo)).DeleteWorkflow)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d got %d", http.StatusNoContent, w.Code)
+	}
+}
+
+func TestDeleteWorkflow_InUse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockWorkflowRepo{}
+	repo.RemoveFn = func(id int64, migration *entities.WorkflowMigration) error {
+		if migration != nil {
+			t.Fatalf("expected no migration without a body")
+		}
+		return &entities.OrphanedTasksError{StatusIDs: []int64{1, 2}}
+	}
+
+	w, c := workflowRequest(http.MethodDelete, "/workflows/2", "")
+	serve(c, handlers.NewWorkflowHandler(domain.NewWorkflowService(repo)).DeleteWorkflow)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestMigrateWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &mockWorkflowRepo{}
+	repo.MigrateFn = func(migration entities.WorkflowMigration) (int64, error) {
+		if migration.SourceWorkflowID != 2 || migration.TargetWorkflowID != 3 || migration.StatusMapping[4] != 7 {
+			t.Fatalf("unexpected migration %+v", migration)
+		}
+		return 3, nil
+	}
+
+	w, c := workflowRequest(http.MethodPost, "/workflows/2/migrate", `{"target_workflow_id": 3, "status_mapping": {"4": 7}}`)
+	serve(c, handlers.NewWorkflowHandler(domain.NewWorkflowService(repo)).MigrateWorkflow)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"migrated_tasks":3`) {
+		t.Fatalf("expected 3 migrated tasks, got %d: %s", w.Code, w.Body.String())
+	}
+
+	repo.MigrateFn = func(migration entities.WorkflowMigration) (int64, error) {
+		return 0, fmt.Errorf("%w: status 4 is mapped to 9", entities.ErrInvalidMigration)
+	}
+	w, c = workflowRequest(http.MethodPost, "/workflows/2/migrate", `{"status_mapping": {"4": 9}}`)
+	serve(c, handlers.NewWorkflowHandler(domain.NewWorkflowService(repo)).MigrateWorkflow)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
@@ -1,6 +1,12 @@
 package utils
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultRequestTimeout = 30 * time.Second
 
 func GetEnvironmentVariable(key string) string {
 
@@ -11,3 +17,16 @@ func GetEnvironmentVariable(key string) string {
 	return value
 
 }
+
+// LoadRequestTimeout reads the optional REQUEST_TIMEOUT duration (e.g. "30s"), the time an API request is given
+// to complete before its database queries are cancelled
+func LoadRequestTimeout() (time.Duration, error) {
+	timeout, err := getDurationEnvironmentVariable("REQUEST_TIMEOUT", defaultRequestTimeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("REQUEST_TIMEOUT environment variable must be a positive duration")
+	}
+	return timeout, nil
+}
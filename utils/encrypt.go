@@ -2,20 +2,17 @@ package utils
 
 import (
 	"log"
-	"todo-api/internal/infrastructure/api"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) string {
-
-	pwdBytes := []byte(password)
-	hashed_Salted, err := bcrypt.GenerateFromPassword(pwdBytes, 7)
+// HashPassword salts and hashes a password, it fails for passwords longer than 72 bytes
+func HashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), 7)
 	if err != nil {
-		log.Println(err)
-		return api.ERROR_CODE_HASH_PASS_FAILED
+		return "", err
 	}
-	return string(hashed_Salted)
+	return string(hashed), nil
 }
 
 func CheckPasswordHash(password string, hashedPassword string) bool {
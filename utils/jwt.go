@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	AccessTokenType  = "access"
+	RefreshTokenType = "refresh"
+
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// JWTConfig holds the HMAC signing key and token lifetimes used to issue and validate JWTs
+type JWTConfig struct {
+	Secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// TokenClaims are the claims carried by access and refresh tokens.
+// Type distinguishes both kinds so a refresh token can't be used as a bearer token,
+// Role is the user's role when the token was issued.
+type TokenClaims struct {
+	Subject   string `json:"sub"`
+	TokenID   string `json:"jti"`
+	Type      string `json:"typ"`
+	Role      string `json:"role,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// LoadJWTConfig reads JWT_SECRET and the optional JWT_ACCESS_TTL / JWT_REFRESH_TTL durations (e.g. "15m", "168h")
+func LoadJWTConfig() (JWTConfig, error) {
+	secret := GetEnvironmentVariable("JWT_SECRET")
+	if secret == "" {
+		return JWTConfig{}, fmt.Errorf("JWT_SECRET environment variable is not set")
+	}
+
+	accessTTL, err := getDurationEnvironmentVariable("JWT_ACCESS_TTL", defaultAccessTokenTTL)
+	if err != nil {
+		return JWTConfig{}, err
+	}
+
+	refreshTTL, err := getDurationEnvironmentVariable("JWT_REFRESH_TTL", defaultRefreshTokenTTL)
+	if err != nil {
+		return JWTConfig{}, err
+	}
+
+	return JWTConfig{
+		Secret:     []byte(secret),
+		AccessTTL:  accessTTL,
+		RefreshTTL: refreshTTL,
+	}, nil
+}
+
+// GenerateToken signs the claims with HMAC-SHA256 and returns the compact JWT
+func GenerateToken(claims TokenClaims, secret []byte) (string, error) {
+	header, err := json.Marshal(jwtHeader{Algorithm: "HS256", Type: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token header: %w", err)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + sign(unsigned, secret), nil
+}
+
+// ParseToken verifies the signature and expiry of a JWT and returns its claims
+func ParseToken(token string, secret []byte) (TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return TokenClaims{}, ErrInvalidToken
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return TokenClaims{}, ErrInvalidToken
+	}
+
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil || header.Algorithm != "HS256" {
+		return TokenClaims{}, ErrInvalidToken
+	}
+
+	expected := sign(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return TokenClaims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return TokenClaims{}, ErrInvalidToken
+	}
+
+	var claims TokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return TokenClaims{}, ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return TokenClaims{}, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+func sign(unsigned string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func getDurationEnvironmentVariable(key string, fallback time.Duration) (time.Duration, error) {
+	value := GetEnvironmentVariable(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s environment variable is not a valid duration: %w", key, err)
+	}
+	return duration, nil
+}
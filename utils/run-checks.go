@@ -28,6 +28,10 @@ func CheckEnvironmentVariables() error {
 		return fmt.Errorf("DB_NAME environment variable is not set")
 	}
 
+	if getEnvironmentVariable("JWT_SECRET") == "" {
+		return fmt.Errorf("JWT_SECRET environment variable is not set")
+	}
+
 	return nil
 }
 